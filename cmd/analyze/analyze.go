@@ -14,17 +14,28 @@ limitations under the License.
 package analyze
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/ai/interactive"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/analysis"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// failOnExitCode is the exit code used when --fail-on matches a failure, so
+// it can be told apart from errors running the analysis.
+const failOnExitCode = 2
+
 var (
 	explain         bool
 	backend         string
@@ -41,6 +52,16 @@ var (
 	customAnalysis  bool
 	customHeaders   []string
 	withStats       bool
+	watch           bool
+	watchInterval   time.Duration
+	baseline        string
+	minSeverity     string
+	failOn          string
+	fromSnapshot    string
+	contexts        []string
+	allContexts     bool
+	correlate       bool
+	ignoreFile      string
 )
 
 // AnalyzeCmd represents the problems command
@@ -51,6 +72,25 @@ var AnalyzeCmd = &cobra.Command{
 	Long: `This command will find problems within your Kubernetes cluster and
 	provide you with a list of issues that need to be resolved`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if allContexts || len(contexts) > 0 {
+			if allContexts && len(contexts) > 0 {
+				color.Red("Error: --contexts cannot be used with --all-contexts")
+				os.Exit(1)
+			}
+			if watch || fromSnapshot != "" {
+				color.Red("Error: --contexts and --all-contexts cannot be used with --watch or --from-snapshot")
+				os.Exit(1)
+			}
+			if allContexts {
+				var err error
+				contexts, err = analysis.ListContexts(viper.GetString("kubeconfig"))
+				if err != nil {
+					color.Red("Error: %v", err)
+					os.Exit(1)
+				}
+			}
+		}
+
 		// Create analysis configuration first.
 		config, err := analysis.NewAnalysis(
 			backend,
@@ -65,6 +105,8 @@ var AnalyzeCmd = &cobra.Command{
 			interactiveMode,
 			customHeaders,
 			withStats,
+			fromSnapshot,
+			contexts,
 		)
 
 		if err != nil {
@@ -73,11 +115,91 @@ var AnalyzeCmd = &cobra.Command{
 		}
 		defer config.Close()
 
+		// a missing default ignore file is fine, a missing explicit one is not
+		path := ignoreFile
+		if path == "" {
+			path = analysis.DefaultIgnoreFile
+		}
+		rules, err := analysis.LoadSuppressionFile(path)
+		if err != nil && (ignoreFile != "" || !os.IsNotExist(err)) {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		if err := config.AddSuppressionRules(rules, path); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+
+		if minSeverity != "" {
+			config.MinSeverity, err = common.ParseSeverity(minSeverity)
+			if err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+		}
+		var failOnSeverity common.Severity
+		if failOn != "" {
+			failOnSeverity, err = common.ParseSeverity(failOn)
+			if err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		if watch {
+			if interactiveMode {
+				color.Red("Error: --interactive cannot be used with --watch")
+				os.Exit(1)
+			}
+			if fromSnapshot != "" {
+				color.Red("Error: --from-snapshot cannot be used with --watch")
+				os.Exit(1)
+			}
+			if correlate {
+				color.Red("Error: --correlate cannot be used with --watch")
+				os.Exit(1)
+			}
+			if failOn != "" || baseline != "" {
+				color.Red("Error: --fail-on and --baseline cannot be used with --watch")
+				os.Exit(1)
+			}
+			if !slices.Contains(analysis.WatchOutputFormats, output) {
+				color.Red("Error: unsupported watch output format: %s. Available format %s", output, strings.Join(analysis.WatchOutputFormats, ","))
+				os.Exit(1)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			config.Context = ctx
+			err := config.Watch(watchInterval, anonymize, func(event analysis.WatchEvent) {
+				eventData, err := analysis.PrintWatchEvent(output, event)
+				if err != nil {
+					color.Red("Error: %v", err)
+					return
+				}
+				fmt.Println(string(eventData))
+			})
+			if err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		if customAnalysis {
 			config.RunCustomAnalysis()
 		}
 		config.RunAnalysis()
+		if correlate {
+			config.Correlate()
+		}
 
+		// Explanations from backends that support streaming are printed as
+		// they arrive, so the text output is already shown. Incidents are
+		// printed once they are explained.
+		streamed := explain && output == "text" && baseline == "" && len(config.Incidents) == 0 && ai.SupportsStreaming(config.AIClient)
+		if streamed {
+			config.StreamOutput = os.Stdout
+		}
 		if explain {
 			if err := config.GetAIResults(output, anonymize); err != nil {
 				color.Red("Error: %v", err)
@@ -85,7 +207,23 @@ var AnalyzeCmd = &cobra.Command{
 			}
 		}
 		// print results
-		output_data, err := config.PrintOutput(output)
+		var output_data []byte
+		// with a baseline only newly added failures count towards --fail-on
+		var failed bool
+		if baseline != "" {
+			var baselineOutput *analysis.JsonOutput
+			baselineOutput, err = analysis.LoadBaseline(baseline)
+			if err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			diff := config.CompareBaseline(baselineOutput)
+			failed = failOn != "" && diff.HasAddedAtLeast(failOnSeverity)
+			output_data, err = config.PrintDiffOutput(output, diff)
+		} else {
+			failed = failOn != "" && config.HasFailuresAtLeast(failOnSeverity)
+			output_data, err = config.PrintOutput(output)
+		}
 		if err != nil {
 			color.Red("Error: %v", err)
 			os.Exit(1)
@@ -96,7 +234,9 @@ var AnalyzeCmd = &cobra.Command{
 			fmt.Println(string(statsData))
 		}
 
-		fmt.Println(string(output_data))
+		if !streamed {
+			fmt.Println(string(output_data))
+		}
 
 		if interactiveMode && explain {
 			if output == "json" {
@@ -122,6 +262,12 @@ var AnalyzeCmd = &cobra.Command{
 				}
 			}
 		}
+
+		if failed {
+			// os.Exit skips deferred calls.
+			config.Close()
+			os.Exit(failOnExitCode)
+		}
 	},
 }
 
@@ -139,11 +285,11 @@ func init() {
 	// add flag for backend
 	AnalyzeCmd.Flags().StringVarP(&backend, "backend", "b", "", "Backend AI provider")
 	// output as json
-	AnalyzeCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	AnalyzeCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json, sarif, junit, markdown, html, mermaid, dot)")
 	// add language options for output
 	AnalyzeCmd.Flags().StringVarP(&language, "language", "l", "english", "Languages to use for AI (e.g. 'English', 'Spanish', 'French', 'German', 'Italian', 'Portuguese', 'Dutch', 'Russian', 'Chinese', 'Japanese', 'Korean')")
 	// add max concurrency
-	AnalyzeCmd.Flags().IntVarP(&maxConcurrency, "max-concurrency", "m", 10, "Maximum number of concurrent requests to the Kubernetes API server")
+	AnalyzeCmd.Flags().IntVarP(&maxConcurrency, "max-concurrency", "m", 10, "Maximum number of concurrent requests to the Kubernetes API server and the AI backend")
 	// kubernetes doc flag
 	AnalyzeCmd.Flags().BoolVarP(&withDoc, "with-doc", "d", false, "Give me the official documentation of the involved field")
 	// interactive mode flag
@@ -156,4 +302,24 @@ func init() {
 	AnalyzeCmd.Flags().StringVarP(&labelSelector, "selector", "L", "", "Label selector (label query) to filter on, supports '=', '==', and '!='. (e.g. -L key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
 	// print stats
 	AnalyzeCmd.Flags().BoolVarP(&withStats, "with-stat", "s", false, "Print analysis stats. This option disables errors display.")
+	// minimum severity flag
+	AnalyzeCmd.Flags().StringVar(&minSeverity, "min-severity", "", "Only report failures of at least this severity (info, warning, critical)")
+	// fail on severity flag
+	AnalyzeCmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Exit with code %d if a failure of at least this severity is found (info, warning, critical)", failOnExitCode))
+	// snapshot flag
+	AnalyzeCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "Analyze the resources in a snapshot instead of a live cluster: a directory of manifests, a `kubectl cluster-info dump` output directory, or a tar archive of either")
+	// ignore-file flag
+	AnalyzeCmd.Flags().StringVar(&ignoreFile, "ignore-file", "", fmt.Sprintf("Path to a YAML list of suppression rules for known findings (default %s if it exists)", analysis.DefaultIgnoreFile))
+	// baseline flag
+	AnalyzeCmd.Flags().StringVar(&baseline, "baseline", "", "Path to a previous JSON analysis output; report added, resolved and unchanged findings against it")
+	// correlate flag
+	AnalyzeCmd.Flags().BoolVar(&correlate, "correlate", false, "Group related problems, such as a failing Pod and the Deployment and Service it breaks, into incidents with one probable root cause. With --explain each incident is explained with a single request")
+	// contexts flag
+	AnalyzeCmd.Flags().StringSliceVar(&contexts, "contexts", []string{}, "Analyze these kubeconfig contexts concurrently and merge their results into one report (e.g. --contexts prod-eu,prod-us)")
+	// all contexts flag
+	AnalyzeCmd.Flags().BoolVar(&allContexts, "all-contexts", false, "Analyze every context of the kubeconfig concurrently and merge their results into one report")
+	// watch flag
+	AnalyzeCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep watching the cluster and report problems as they appear or resolve")
+	// watch resync interval flag
+	AnalyzeCmd.Flags().DurationVar(&watchInterval, "watch-interval", 10*time.Minute, "Interval at which all watched analyzers are re-run, in addition to change-driven runs")
 }
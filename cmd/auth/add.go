@@ -113,6 +113,10 @@ var addCmd = &cobra.Command{
 			color.Red("Error: topK ranges from 1 to 100.")
 			os.Exit(1)
 		}
+		if requestsPerMinute < 0 {
+			color.Red("Error: requests per minute must not be negative.")
+			os.Exit(1)
+		}
 
 		if ai.NeedPassword(backend) && password == "" {
 			fmt.Printf("Enter %s Key: ", backend)
@@ -127,20 +131,21 @@ var addCmd = &cobra.Command{
 
 		// create new provider object
 		newProvider := ai.AIProvider{
-			Name:           backend,
-			Model:          model,
-			Password:       password,
-			BaseURL:        baseURL,
-			EndpointName:   endpointName,
-			Engine:         engine,
-			Temperature:    temperature,
-			ProviderRegion: providerRegion,
-			ProviderId:     providerId,
-			CompartmentId:  compartmentId,
-			TopP:           topP,
-			TopK:           topK,
-			MaxTokens:      maxTokens,
-			OrganizationId: organizationId,
+			Name:              backend,
+			Model:             model,
+			Password:          password,
+			BaseURL:           baseURL,
+			EndpointName:      endpointName,
+			Engine:            engine,
+			Temperature:       temperature,
+			ProviderRegion:    providerRegion,
+			ProviderId:        providerId,
+			CompartmentId:     compartmentId,
+			TopP:              topP,
+			TopK:              topK,
+			MaxTokens:         maxTokens,
+			OrganizationId:    organizationId,
+			RequestsPerMinute: requestsPerMinute,
 		}
 
 		if providerIndex == -1 {
@@ -185,4 +190,6 @@ func init() {
 	addCmd.Flags().StringVarP(&compartmentId, "compartmentId", "k", "", "Compartment ID for generative AI model (only for oci backend)")
 	// add flag for openai organization
 	addCmd.Flags().StringVarP(&organizationId, "organizationId", "o", "", "OpenAI or AzureOpenAI Organization ID (only for openai and azureopenai backend)")
+	// add flag for requests per minute
+	addCmd.Flags().IntVar(&requestsPerMinute, "requests-per-minute", 0, "Maximum number of requests per minute sent to the backend AI provider (0 means no limit)")
 }
@@ -19,20 +19,21 @@ import (
 )
 
 var (
-	backend        string
-	password       string
-	baseURL        string
-	endpointName   string
-	model          string
-	engine         string
-	temperature    float32
-	providerRegion string
-	providerId     string
-	compartmentId  string
-	topP           float32
-	topK           int32
-	maxTokens      int
-	organizationId string
+	backend           string
+	password          string
+	baseURL           string
+	endpointName      string
+	model             string
+	engine            string
+	temperature       float32
+	providerRegion    string
+	providerId        string
+	compartmentId     string
+	topP              float32
+	topK              int32
+	maxTokens         int
+	organizationId    string
+	requestsPerMinute int
 )
 
 var configAI ai.AIConfiguration
@@ -61,4 +62,6 @@ func init() {
 	AuthCmd.AddCommand(defaultCmd)
 	// add subcommand to update backend provider
 	AuthCmd.AddCommand(updateCmd)
+	// add subcommand to set fallback backend providers
+	AuthCmd.AddCommand(fallbackCmd)
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	fallbackProviders []string
+	clearFallback     bool
+)
+
+var fallbackCmd = &cobra.Command{
+	Use:   "fallback",
+	Short: "Set the AI backend providers to fall back to",
+	Long: `The command to set the AI backend providers that are tried, in order, when the
+selected provider fails, times out or has exhausted its quota`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := viper.UnmarshalKey("ai", &configAI)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+
+		if clearFallback {
+			configAI.Fallback = nil
+		} else if len(fallbackProviders) == 0 {
+			if len(configAI.Fallback) != 0 {
+				color.Yellow("Your fallback providers are %s", strings.Join(configAI.Fallback, ", "))
+			} else {
+				color.Yellow("No fallback providers are set")
+			}
+			os.Exit(0)
+		} else {
+			var providers []string
+			for _, providerName := range fallbackProviders {
+				// lowercase the provider name
+				providerName = strings.ToLower(strings.TrimSpace(providerName))
+
+				// Check if the provider is in the provider list
+				providerExists := false
+				for _, provider := range configAI.Providers {
+					if provider.Name == providerName {
+						providerExists = true
+					}
+				}
+				if !providerExists {
+					color.Red("Error: Provider %s does not exist", providerName)
+					os.Exit(1)
+				}
+				providers = append(providers, providerName)
+			}
+			configAI.Fallback = providers
+		}
+
+		viper.Set("ai", configAI)
+		// Viper write config
+		err = viper.WriteConfig()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		// Print acknowledgement
+		if len(configAI.Fallback) == 0 {
+			color.Green("Fallback providers cleared")
+		} else {
+			color.Green("Fallback providers set to %s", strings.Join(configAI.Fallback, ", "))
+		}
+	},
+}
+
+func init() {
+	// fallback providers flag
+	fallbackCmd.Flags().StringSliceVarP(&fallbackProviders, "providers", "p", []string{}, "The names of the providers to fall back to, in order (e.g. azureopenai,openai)")
+	// clear flag
+	fallbackCmd.Flags().BoolVar(&clearFallback, "clear", false, "Remove all fallback providers")
+}
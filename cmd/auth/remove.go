@@ -15,6 +15,7 @@ package auth
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
@@ -52,6 +53,9 @@ var removeCmd = &cobra.Command{
 					if configAI.DefaultProvider == b {
 						configAI.DefaultProvider = "openai"
 					}
+					configAI.Fallback = slices.DeleteFunc(configAI.Fallback, func(name string) bool {
+						return name == b
+					})
 					color.Green("%s deleted from the AI backend provider list", b)
 					break
 				}
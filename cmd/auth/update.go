@@ -57,6 +57,10 @@ var updateCmd = &cobra.Command{
 			color.Red("Error: temperature ranges from 0 to 1.")
 			os.Exit(1)
 		}
+		if requestsPerMinute < 0 {
+			color.Red("Error: requests per minute must not be negative.")
+			os.Exit(1)
+		}
 
 		foundBackend := false
 		for i, provider := range configAI.Providers {
@@ -85,6 +89,10 @@ var updateCmd = &cobra.Command{
 					configAI.Providers[i].OrganizationId = organizationId
 					color.Blue("Organization Id updated successfully")
 				}
+				if cmd.Flags().Changed("requests-per-minute") {
+					configAI.Providers[i].RequestsPerMinute = requestsPerMinute
+					color.Blue("Requests per minute updated successfully")
+				}
 				configAI.Providers[i].Temperature = temperature
 				color.Green("%s updated in the AI backend provider list", backend)
 			}
@@ -117,4 +125,6 @@ func init() {
 	updateCmd.Flags().StringVarP(&engine, "engine", "e", "", "Update Azure AI deployment name")
 	// update flag for organizationId
 	updateCmd.Flags().StringVarP(&organizationId, "organizationId", "o", "", "Update OpenAI or Azure organization Id")
+	// update flag for requests per minute
+	updateCmd.Flags().IntVar(&requestsPerMinute, "requests-per-minute", 0, "Update the maximum number of requests per minute sent to the backend AI provider (0 means no limit)")
 }
@@ -32,6 +32,9 @@ var (
 	projectId      string
 	endpoint       string
 	insecure       bool
+	redisDB        int
+	redisTLS       bool
+	redisPrefix    string
 )
 
 // addCmd represents the add command
@@ -42,7 +45,8 @@ var addCmd = &cobra.Command{
 	The supported cache types are:
 	- Azure Blob storage
 	- Google Cloud storage
-	- S3`,
+	- S3
+	- Redis`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			color.Red("Error: Please provide a value for cache types. Run k8sgpt cache add --help")
@@ -50,7 +54,18 @@ var addCmd = &cobra.Command{
 		}
 		fmt.Println(color.YellowString("Adding remote based cache"))
 		cacheType := args[0]
-		remoteCache, err := cache.NewCacheProvider(cacheType, bucketName, region, endpoint, storageAccount, containerName, projectId, insecure)
+		remoteCache, err := cache.NewCacheProviderWithOptions(cacheType, cache.CacheProviderOptions{
+			BucketName:     bucketName,
+			Region:         region,
+			Endpoint:       endpoint,
+			StorageAccount: storageAccount,
+			ContainerName:  containerName,
+			ProjectId:      projectId,
+			Insecure:       insecure,
+			RedisDB:        redisDB,
+			RedisTLS:       redisTLS,
+			RedisPrefix:    redisPrefix,
+		})
 		if err != nil {
 			color.Red("Error: %v", err)
 			os.Exit(1)
@@ -66,12 +81,15 @@ var addCmd = &cobra.Command{
 func init() {
 	CacheCmd.AddCommand(addCmd)
 	addCmd.Flags().StringVarP(&region, "region", "r", "us-east-1", "The region to use for the AWS S3 or GCS cache")
-	addCmd.Flags().StringVarP(&endpoint, "endpoint", "e", "", "The S3 or minio endpoint")
-	addCmd.Flags().BoolVarP(&insecure, "insecure", "i", false, "Skip TLS verification for S3/Minio custom endpoint")
+	addCmd.Flags().StringVarP(&endpoint, "endpoint", "e", "", "The S3 or minio endpoint, or the address of the Redis server")
+	addCmd.Flags().BoolVarP(&insecure, "insecure", "i", false, "Skip TLS verification for S3/Minio custom endpoint or Redis")
 	addCmd.Flags().StringVarP(&bucketName, "bucket", "b", "", "The name of the AWS S3 bucket to use for the cache")
 	addCmd.Flags().StringVarP(&projectId, "projectid", "p", "", "The GCP project ID")
 	addCmd.Flags().StringVarP(&storageAccount, "storageacc", "s", "", "The Azure storage account name of the container")
 	addCmd.Flags().StringVarP(&containerName, "container", "c", "", "The Azure container name to use for the cache")
+	addCmd.Flags().IntVar(&redisDB, "db", 0, "The Redis database to use for the cache")
+	addCmd.Flags().BoolVar(&redisTLS, "tls", false, "Connect to Redis over TLS")
+	addCmd.Flags().StringVar(&redisPrefix, "prefix", "", "The prefix of the Redis keys of the cache (default \"k8sgpt:\")")
 	addCmd.MarkFlagsRequiredTogether("storageacc", "container")
 	// Tedious check to ensure we don't include arguments from different providers
 	addCmd.MarkFlagsMutuallyExclusive("region", "storageacc")
@@ -22,7 +22,7 @@ import (
 var CacheCmd = &cobra.Command{
 	Use:   "cache",
 	Short: "For working with the cache the results of an analysis",
-	Long:  `Cache commands allow you to add a remote cache, list the contents of the cache, and remove items from the cache.`,
+	Long:  `Cache commands allow you to add a remote cache, list the contents of the cache, remove items from the cache, and export, import or garbage collect cached explanations.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmd.Help()
 		if err != nil {
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ttl                 string
+	maxSize             string
+	encryptionKeyFile   string
+	encryptionKeyEnv    string
+	encryptionSecret    string
+	encryptionSecretKey string
+	disableEncryption   bool
+	tiered              bool
+)
+
+// configureCmd represents the configure command
+var configureCmd = &cobra.Command{
+	Use:   "configure",
+	Short: "Configure expiry, size limits, tiering and encryption of the cache",
+	Long: `This command allows you to configure how long cached explanations are kept, how large the file based cache may grow and how cached explanations are encrypted.
+	The TTL applies to every cache type; the maximum size only to the file based cache, which evicts the least recently used entries first.
+	A tiered cache reads through the local file based cache in front of the remote cache and writes to both.
+	Pass an empty value to remove a limit.
+	Encryption applies to every cache type. The key is 16, 24 or 32 bytes, raw or base64 encoded, read from a file, an environment variable or a Kubernetes Secret.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		encryptionChanged := cmd.Flags().Changed("encryption-key-file") || cmd.Flags().Changed("encryption-key-env") ||
+			cmd.Flags().Changed("encryption-key-secret") || cmd.Flags().Changed("encryption-key-secret-key")
+		if !cmd.Flags().Changed("ttl") && !cmd.Flags().Changed("max-size") && !cmd.Flags().Changed("tiered") && !encryptionChanged && !disableEncryption {
+			color.Red("Error: Please provide a value for --ttl, --max-size, --tiered or the encryption key. Run k8sgpt cache configure --help")
+			os.Exit(1)
+		}
+		cacheInfo, err := cache.ParseCacheConfiguration()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("ttl") {
+			if _, err := cache.ParseTTL(ttl); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			cacheInfo.TTL = ttl
+		}
+		if cmd.Flags().Changed("max-size") {
+			if _, err := cache.ParseMaxSize(maxSize); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			cacheInfo.MaxSize = maxSize
+		}
+		if cmd.Flags().Changed("tiered") {
+			cacheInfo.Tiered = tiered
+		}
+		if disableEncryption {
+			cacheInfo.Encryption = cache.CacheEncryptionConfiguration{}
+		}
+		if encryptionChanged {
+			cacheInfo.Encryption = cache.CacheEncryptionConfiguration{
+				KeyFile:   encryptionKeyFile,
+				KeyEnv:    encryptionKeyEnv,
+				Secret:    encryptionSecret,
+				SecretKey: encryptionSecretKey,
+			}
+			// Fail now rather than on the next analysis.
+			if _, err := cache.LoadEncryptionKey(cacheInfo.Encryption); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+		}
+		if err := cache.UpdateCacheConfiguration(cacheInfo); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("Cache configured: ttl=%q max-size=%q encrypted=%t tiered=%t", cacheInfo.TTL, cacheInfo.MaxSize, cacheInfo.Encryption.Enabled(), cacheInfo.Tiered))
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(configureCmd)
+	// ttl flag
+	configureCmd.Flags().StringVar(&ttl, "ttl", "", "How long cached explanations are kept, e.g. 168h")
+	// max-size flag
+	configureCmd.Flags().StringVar(&maxSize, "max-size", "", "The maximum size of the file based cache, e.g. 100Mi")
+	// tiered flag
+	configureCmd.Flags().BoolVar(&tiered, "tiered", false, "Read through a local file based cache in front of the remote cache")
+	// encryption-key-file flag
+	configureCmd.Flags().StringVar(&encryptionKeyFile, "encryption-key-file", "", "A file holding the key encrypting cached explanations")
+	// encryption-key-env flag
+	configureCmd.Flags().StringVar(&encryptionKeyEnv, "encryption-key-env", "", "An environment variable holding the key encrypting cached explanations")
+	// encryption-key-secret flag
+	configureCmd.Flags().StringVar(&encryptionSecret, "encryption-key-secret", "", "A Kubernetes Secret holding the key encrypting cached explanations, as namespace/name")
+	// encryption-key-secret-key flag
+	configureCmd.Flags().StringVar(&encryptionSecretKey, "encryption-key-secret-key", "key", "The data key of the encryption key in the Kubernetes Secret")
+	// disable-encryption flag
+	configureCmd.Flags().BoolVar(&disableEncryption, "disable-encryption", false, "Store cached explanations without encryption")
+	configureCmd.MarkFlagsMutuallyExclusive("encryption-key-file", "encryption-key-env", "encryption-key-secret", "disable-encryption")
+	configureCmd.MarkFlagsRequiredTogether("encryption-key-secret-key", "encryption-key-secret")
+}
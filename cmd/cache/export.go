@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [archive]",
+	Short: "Export the cache to an archive",
+	Long: `This command allows you to export every cached explanation to a gzip compressed tar archive, e.g. to pre-warm the cache of an air-gapped cluster with k8sgpt cache import.
+	Encrypted entries are written decrypted, so protect the archive accordingly.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			color.Red("Error: Please provide a value for archive. Run k8sgpt cache export --help")
+			os.Exit(1)
+		}
+		c, err := cache.GetCacheConfiguration()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+
+		f, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		exported, err := cache.Export(c, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("Exported %d cache entries to %s.", exported, args[0]))
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var olderThan string
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove old entries from the cache",
+	Long:  `This command allows you to remove the cached explanations last updated longer ago than --older-than, in any cache type.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		age, err := cache.ParseAge(olderThan)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		c, err := cache.GetCacheConfiguration()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+
+		removed, err := cache.GC(c, age)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("Removed %d cache entries older than %s.", removed, olderThan))
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(gcCmd)
+	// older-than flag
+	gcCmd.Flags().StringVar(&olderThan, "older-than", "30d", "Remove entries last updated longer ago than this, e.g. 30d or 12h")
+}
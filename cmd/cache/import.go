@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var overwrite bool
+
+var importCmd = &cobra.Command{
+	Use:   "import [archive]",
+	Short: "Import an archive into the cache",
+	Long:  `This command allows you to store the cached explanations of an archive written by k8sgpt cache export in the configured cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			color.Red("Error: Please provide a value for archive. Run k8sgpt cache import --help")
+			os.Exit(1)
+		}
+		c, err := cache.GetCacheConfiguration()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		imported, err := cache.Import(c, f, overwrite)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("Imported %d cache entries from %s.", imported, args[0]))
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(importCmd)
+	// overwrite flag
+	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace entries that are already cached")
+}
@@ -15,8 +15,10 @@ limitations under the License.
 package cache
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 
 	"github.com/fatih/color"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
@@ -28,7 +30,7 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List the contents of the cache",
-	Long:  `This command allows you to list the contents of the cache.`,
+	Long:  `This command allows you to list the contents of the cache, followed by the hits, misses and latency of each cache tier across runs.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// load remote cache if it is configured
@@ -58,6 +60,30 @@ var listCmd = &cobra.Command{
 			table.Append([]string{v.Name, v.UpdatedAt.String()})
 		}
 		table.Render()
+
+		stats, err := cache.GetStats()
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		if len(stats) == 0 {
+			return
+		}
+		fmt.Println()
+		statsTable := tablewriter.NewWriter(os.Stdout)
+		statsTable.SetHeader([]string{"Tier", "Hits", "Misses", "Hit Ratio", "Errors", "Avg Load", "Avg Store"})
+		for _, s := range stats {
+			statsTable.Append([]string{
+				s.Tier,
+				strconv.FormatUint(s.Hits, 10),
+				strconv.FormatUint(s.Misses, 10),
+				fmt.Sprintf("%.1f%%", s.HitRatio()*100),
+				strconv.FormatUint(s.Errors, 10),
+				s.AverageLoadTime().String(),
+				s.AverageStoreTime().String(),
+			})
+		}
+		statsTable.Render()
 	},
 }
 
@@ -15,6 +15,7 @@ package customanalyzer
 
 import (
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	customAnalyzer "github.com/k8sgpt-ai/k8sgpt/pkg/custom_analyzer"
@@ -23,9 +24,14 @@ import (
 )
 
 var (
-	name string
-	url  string
-	port int
+	name    string
+	url     string
+	port    int
+	wasm    string
+	kinds   []string
+	timeout string
+	// connection flags
+	connection customAnalyzer.Connection
 )
 
 var addCmd = &cobra.Command{
@@ -41,20 +47,46 @@ var addCmd = &cobra.Command{
 		}
 		analyzer := customAnalyzer.NewCustomAnalyzer()
 
-		// Check if configuration is valid
-		err = analyzer.Check(configCustomAnalyzer, name, url, port)
-		if err != nil {
-			color.Red("Error adding custom analyzer: %s", err.Error())
-			os.Exit(1)
+		if timeout != "" {
+			if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+				color.Red("Error: invalid timeout %q", timeout)
+				os.Exit(1)
+			}
 		}
 
-		configCustomAnalyzer = append(configCustomAnalyzer, customAnalyzer.CustomAnalyzerConfiguration{
-			Name: name,
-			Connection: customAnalyzer.Connection{
-				Url:  url,
-				Port: port,
-			},
-		})
+		if wasm != "" {
+			// WebAssembly analyzers run in-process and have no connection
+			module, err := analyzer.CheckWasm(configCustomAnalyzer, name, wasm, kinds)
+			if err != nil {
+				color.Red("Error adding custom analyzer: %s", err.Error())
+				os.Exit(1)
+			}
+			configCustomAnalyzer = append(configCustomAnalyzer, customAnalyzer.CustomAnalyzerConfiguration{
+				Name:    name,
+				Wasm:    module,
+				Timeout: timeout,
+			})
+		} else {
+			// Check if configuration is valid
+			err = analyzer.Check(configCustomAnalyzer, name, url, port)
+			if err != nil {
+				color.Red("Error adding custom analyzer: %s", err.Error())
+				os.Exit(1)
+			}
+
+			connection.Url = url
+			connection.Port = port
+			if err := connection.Validate(); err != nil {
+				color.Red("Error adding custom analyzer: %s", err.Error())
+				os.Exit(1)
+			}
+
+			configCustomAnalyzer = append(configCustomAnalyzer, customAnalyzer.CustomAnalyzerConfiguration{
+				Name:       name,
+				Connection: connection,
+				Timeout:    timeout,
+			})
+		}
 
 		viper.Set("custom_analyzers", configCustomAnalyzer)
 		if err := viper.WriteConfig(); err != nil {
@@ -70,4 +102,23 @@ func init() {
 	addCmd.Flags().StringVarP(&name, "name", "n", "my-custom-analyzer", "Name of the custom analyzer.")
 	addCmd.Flags().StringVarP(&url, "url", "u", "localhost", "URL for the custom analyzer connection.")
 	addCmd.Flags().IntVarP(&port, "port", "r", 8085, "Port for the custom analyzer connection.")
+	addCmd.Flags().StringVar(&wasm, "wasm", "", "Path to a WebAssembly analyzer module, run in-process instead of over a connection.")
+	addCmd.MarkFlagsMutuallyExclusive("wasm", "url")
+	addCmd.MarkFlagsMutuallyExclusive("wasm", "port")
+	// kinds flag
+	addCmd.Flags().StringSliceVar(&kinds, "kinds", []string{}, "Kinds the WebAssembly analyzer may list, as Kind or Kind.group, e.g. Pod,Deployment.apps (default every kind but Secrets).")
+	// timeout flag
+	addCmd.Flags().StringVar(&timeout, "timeout", "", "Timeout of each run of the custom analyzer, e.g. 2m (default 30s).")
+	// tls flags
+	addCmd.Flags().BoolVar(&connection.TLS, "tls", false, "Connect to the custom analyzer over TLS.")
+	addCmd.Flags().StringVar(&connection.CAFile, "ca-file", "", "CA certificate to verify the custom analyzer with instead of the system roots.")
+	addCmd.Flags().StringVar(&connection.CertFile, "cert-file", "", "Client certificate for mutual TLS.")
+	addCmd.Flags().StringVar(&connection.KeyFile, "key-file", "", "Client key for mutual TLS.")
+	addCmd.Flags().StringVar(&connection.ServerName, "server-name", "", "Server name to verify the certificate of the custom analyzer against.")
+	addCmd.Flags().BoolVar(&connection.InsecureSkipVerify, "insecure-skip-verify", false, "Skip the verification of the certificate of the custom analyzer.")
+	// bearer token flags
+	addCmd.Flags().StringVar(&connection.TokenEnv, "token-env", "", "Environment variable holding a bearer token for the custom analyzer.")
+	addCmd.Flags().StringVar(&connection.TokenFile, "token-file", "", "File holding a bearer token for the custom analyzer, read on every run.")
+	addCmd.MarkFlagsMutuallyExclusive("token-env", "token-file")
+	addCmd.MarkFlagsRequiredTogether("cert-file", "key-file")
 }
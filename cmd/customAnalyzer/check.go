@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customanalyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/custom"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var checkNames string
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the health of custom analyzers",
+	Long:  "The check command probes the configured custom analyzers and reports whether they are reachable, their version and the capabilities they declare. It exits with an error if any analyzer cannot run.",
+	Run: func(cmd *cobra.Command, args []string) {
+		var analyzers []custom.CustomAnalyzer
+		if err := viper.UnmarshalKey("custom_analyzers", &analyzers); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		if checkNames != "" {
+			selected := strings.Split(checkNames, ",")
+			analyzers = slices.DeleteFunc(analyzers, func(a custom.CustomAnalyzer) bool {
+				return !slices.Contains(selected, a.Name)
+			})
+		}
+		if len(analyzers) == 0 {
+			color.Yellow("No custom analyzers to check")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Endpoint", "Status", "Version", "Capabilities", "Latency"})
+		failed := false
+		for _, analyzer := range analyzers {
+			endpoint := fmt.Sprintf("%s:%s", analyzer.Connection.Url, analyzer.Connection.Port)
+			if analyzer.Wasm.Path != "" {
+				endpoint = analyzer.Wasm.Path
+			}
+
+			health, err := custom.Probe(context.Background(), analyzer)
+			if err != nil {
+				failed = true
+				table.Append([]string{analyzer.Name, endpoint, color.RedString("UNREACHABLE: %s", err), "", "", ""})
+				continue
+			}
+			state := color.GreenString(health.Status)
+			if !health.Serving() {
+				failed = true
+				state = color.RedString(health.Status)
+			}
+			table.Append([]string{
+				analyzer.Name,
+				endpoint,
+				state,
+				health.Version,
+				strings.Join(health.Capabilities, ", "),
+				health.Latency.Round(time.Millisecond).String(),
+			})
+		}
+		table.Render()
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&checkNames, "names", "n", "", "Comma separated names of the custom analyzers to check, all by default.")
+}
@@ -24,7 +24,7 @@ var configCustomAnalyzer []customAnalyzer.CustomAnalyzerConfiguration
 var CustomAnalyzerCmd = &cobra.Command{
 	Use:   "custom-analyzer",
 	Short: "Manage a custom analyzer",
-	Long:  `This command allows you to manage custom analyzers, including adding, removing, and listing them.`,
+	Long:  `This command allows you to manage custom analyzers, including adding, removing, listing and checking them.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			_ = cmd.Help()
@@ -40,4 +40,6 @@ func init() {
 	CustomAnalyzerCmd.AddCommand(removeCmd)
 	// list subcomment to list custom analyzer
 	CustomAnalyzerCmd.AddCommand(listCmd)
+	// check subcommand to probe custom analyzers
+	CustomAnalyzerCmd.AddCommand(checkCmd)
 }
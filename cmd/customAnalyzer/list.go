@@ -54,7 +54,24 @@ func init() {
 }
 
 func printDetails(analyzer customAnalyzer.CustomAnalyzerConfiguration) {
+	if analyzer.Wasm.Path != "" {
+		fmt.Printf("   - Wasm: %s\n", analyzer.Wasm.Path)
+		fmt.Printf("   - Digest: %s\n", analyzer.Wasm.Digest)
+		return
+	}
 	fmt.Printf("   - Url: %s\n", analyzer.Connection.Url)
 	fmt.Printf("   - Port: %d\n", analyzer.Connection.Port)
+	if analyzer.Connection.TLS {
+		fmt.Printf("   - TLS: %t (mutual: %t)\n", true, analyzer.Connection.CertFile != "")
+	}
+	if analyzer.Connection.TokenEnv != "" {
+		fmt.Printf("   - Bearer token: $%s\n", analyzer.Connection.TokenEnv)
+	}
+	if analyzer.Connection.TokenFile != "" {
+		fmt.Printf("   - Bearer token file: %s\n", analyzer.Connection.TokenFile)
+	}
+	if analyzer.Timeout != "" {
+		fmt.Printf("   - Timeout: %s\n", analyzer.Timeout)
+	}
 
 }
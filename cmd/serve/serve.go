@@ -15,7 +15,9 @@ package serve
 
 import (
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 
 	k8sgptserver "github.com/k8sgpt-ai/k8sgpt/pkg/server"
 
@@ -191,8 +193,14 @@ var ServeCmd = &cobra.Command{
 			}
 		}()
 
-		// Wait for both servers to exit
-		select {}
+		// Serve until stopped, then save what the analyses left pending.
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		if err := server.Shutdown(); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
 	},
 }
 
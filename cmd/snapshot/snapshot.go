@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	outputFile string
+	namespace  string
+	redact     bool
+)
+
+// SnapshotCmd represents the snapshot command
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture the resources k8sgpt analyzes into an archive",
+	Long: `This command captures every resource the analyzers read from your Kubernetes cluster
+	into a compressed archive that can be analyzed later with k8sgpt analyze --from-snapshot`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kubecontext := viper.GetString("kubecontext")
+		kubeconfig := viper.GetString("kubeconfig")
+		client, err := kubernetes.NewClient(kubecontext, kubeconfig)
+		if err != nil {
+			color.Red("Error initialising kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		objects, warnings, err := snapshot.Capture(context.Background(), client.GetCtrlClient(), namespace)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			color.Yellow("Warning: %s", warning)
+		}
+
+		if redact {
+			snapshot.Redact(objects)
+		}
+
+		if outputFile == "" {
+			outputFile = fmt.Sprintf("k8sgpt-snapshot-%s.tar.gz", time.Now().Format("20060102-150405"))
+		}
+		f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		if err := snapshot.WriteArchive(f, objects); err != nil {
+			f.Close()
+			color.Red("Error writing snapshot: %v", err)
+			os.Exit(1)
+		}
+		if err := f.Close(); err != nil {
+			color.Red("Error writing snapshot: %v", err)
+			os.Exit(1)
+		}
+
+		color.Green("Captured %d objects to %s", len(objects), outputFile)
+	},
+}
+
+func init() {
+	// output flag
+	SnapshotCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Path of the archive to write (default k8sgpt-snapshot-<timestamp>.tar.gz)")
+	// namespace flag
+	SnapshotCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to capture; cluster-scoped resources are always captured")
+	// redact flag
+	SnapshotCmd.Flags().BoolVarP(&redact, "redact", "r", false, "Mask object names and namespaces, and references to them, before writing the archive")
+}
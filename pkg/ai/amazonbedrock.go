@@ -119,8 +119,8 @@ func (a *AmazonBedRockClient) Configure(config IAIConfig) error {
 	return nil
 }
 
-// GetCompletion sends a request to the model for generating completion based on the provided prompt.
-func (a *AmazonBedRockClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+// requestBody prepares the input data for the model invocation based on the model.
+func (a *AmazonBedRockClient) requestBody(prompt string) ([]byte, error) {
 
 	// Prepare the input data for the model invocation based on the model & the Response Body per model as well.
 	var request map[string]interface{}
@@ -149,11 +149,17 @@ func (a *AmazonBedRockClient) GetCompletion(ctx context.Context, prompt string)
             },
 		}
 	default:
-        return "", fmt.Errorf("model %s not supported", a.model)
+        return nil, fmt.Errorf("model %s not supported", a.model)
 	}
 
 
-	body, err := json.Marshal(request)
+	return json.Marshal(request)
+}
+
+// GetCompletion sends a request to the model for generating completion based on the provided prompt.
+func (a *AmazonBedRockClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+
+	body, err := a.requestBody(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -221,6 +227,78 @@ func (a *AmazonBedRockClient) GetCompletion(ctx context.Context, prompt string)
         return "", fmt.Errorf("model %s not supported", a.model)
     }
 }
+
+// StreamCompletion sends a request to the model and streams the generated completion.
+// AI21 Jurassic models cannot stream, their completion is sent as a single chunk.
+func (a *AmazonBedRockClient) StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	if a.model == ModelA21J2UltraV1 || a.model == ModelA21J2JumboInstruct {
+		completion, err := a.GetCompletion(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
+		chunks := make(chan StreamChunk, 1)
+		chunks <- StreamChunk{Content: completion}
+		close(chunks)
+		return chunks, nil
+	}
+
+	body, err := a.requestBody(prompt)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.InvokeModelWithResponseStreamWithContext(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+		Body:        body,
+		ModelId:     aws.String(a.model),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stream := resp.GetStream()
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+		for event := range stream.Events() {
+			part, ok := event.(*bedrockruntime.PayloadPart)
+			if !ok {
+				continue
+			}
+			content, err := a.streamedContent(part.Bytes)
+			if err != nil {
+				sendChunk(ctx, chunks, StreamChunk{Err: err})
+				return
+			}
+			if content == "" {
+				continue
+			}
+			if !sendChunk(ctx, chunks, StreamChunk{Content: content}) {
+				return
+			}
+		}
+		if err := stream.Err(); err != nil {
+			sendChunk(ctx, chunks, StreamChunk{Err: err})
+		}
+	}()
+	return chunks, nil
+}
+
+// streamedContent extracts the generated text from a streamed response chunk.
+func (a *AmazonBedRockClient) streamedContent(data []byte) (string, error) {
+	var chunk struct {
+		// Anthropic Claude
+		Completion string `json:"completion"`
+		// Amazon Titan
+		OutputText string `json:"outputText"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return "", err
+	}
+	return chunk.Completion + chunk.OutputText, nil
+}
+
 // GetName returns the name of the AmazonBedRockClient.
 func (a *AmazonBedRockClient) GetName() string {
 	return amazonbedrockAIClientName
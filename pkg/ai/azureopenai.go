@@ -37,18 +37,18 @@ func (c *AzureAIClient) Configure(config IAIConfig) error {
 
 	}
 
+	var transport http.RoundTripper = http.DefaultTransport
 	if proxyEndpoint != "" {
 		proxyUrl, err := url.Parse(proxyEndpoint)
 		if err != nil {
 			return err
 		}
-		transport := &http.Transport{
+		transport = &http.Transport{
 			Proxy: http.ProxyURL(proxyUrl),
 		}
-
-		defaultConfig.HTTPClient = &http.Client{
-			Transport: transport,
-		}
+	}
+	defaultConfig.HTTPClient = &http.Client{
+		Transport: &retryAfterTransport{Origin: transport},
 	}
 	if orgId != "" {
 		defaultConfig.OrgID = orgId
@@ -64,9 +64,8 @@ func (c *AzureAIClient) Configure(config IAIConfig) error {
 	return nil
 }
 
-func (c *AzureAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
-	// Create a completion request
-	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+func (c *AzureAIClient) chatCompletionRequest(prompt string) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
 		Model: c.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
@@ -75,13 +74,22 @@ func (c *AzureAIClient) GetCompletion(ctx context.Context, prompt string) (strin
 			},
 		},
 		Temperature: c.temperature,
-	})
+	}
+}
+
+func (c *AzureAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	// Create a completion request
+	resp, err := c.client.CreateChatCompletion(ctx, c.chatCompletionRequest(prompt))
 	if err != nil {
 		return "", err
 	}
 	return resp.Choices[0].Message.Content, nil
 }
 
+func (c *AzureAIClient) StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	return streamChatCompletion(ctx, c.client, c.chatCompletionRequest(prompt))
+}
+
 func (c *AzureAIClient) GetName() string {
 	return azureAIClientName
 }
@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const fallbackClientSeparator = ","
+
+// FallbackBackend is a configured backend of a FallbackClient.
+type FallbackBackend struct {
+	Client IAI
+	// Limiter limits the requests to the backend, nil means no limit.
+	Limiter *rate.Limiter
+	// Timeout bounds a single request, 0 means no timeout.
+	Timeout time.Duration
+}
+
+// FallbackClient sends a request to its backends in order until one of them
+// answers, so an unavailable, slow or exhausted backend does not fail the
+// request.
+type FallbackClient struct {
+	backends []FallbackBackend
+}
+
+// NewFallbackClient returns a client trying backends in the given order.
+// The backends must already be configured.
+func NewFallbackClient(backends []FallbackBackend) *FallbackClient {
+	return &FallbackClient{backends: backends}
+}
+
+// Configure is a no-op, the backends are configured before they are passed
+// to NewFallbackClient.
+func (c *FallbackClient) Configure(_ IAIConfig) error {
+	return nil
+}
+
+func (c *FallbackClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	var errs []error
+	for _, backend := range c.backends {
+		completion, err := c.try(ctx, backend, func(ctx context.Context) (string, error) {
+			return backend.Client.GetCompletion(ctx, prompt)
+		})
+		if err == nil {
+			recordAnsweredBy(ctx, backend.Client.GetName())
+			return completion, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", backend.Client.GetName(), err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// StreamCompletion streams the completion of the first backend that starts
+// answering. Once a backend has sent part of the completion a failure is not
+// recovered, as the part may already be shown.
+func (c *FallbackClient) StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	var errs []error
+	for _, backend := range c.backends {
+		var cancel context.CancelFunc = func() {}
+		streamCtx := ctx
+		if backend.Timeout > 0 {
+			streamCtx, cancel = context.WithTimeout(ctx, backend.Timeout)
+		}
+
+		first, chunks, err := c.startStream(streamCtx, backend, prompt)
+		if err != nil {
+			cancel()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", backend.Client.GetName(), err))
+			continue
+		}
+
+		recordAnsweredBy(ctx, backend.Client.GetName())
+		forwarded := make(chan StreamChunk)
+		go func() {
+			defer close(forwarded)
+			defer cancel()
+			if first != nil && !sendChunk(ctx, forwarded, *first) {
+				return
+			}
+			if chunks == nil {
+				return
+			}
+			for chunk := range chunks {
+				if !sendChunk(ctx, forwarded, chunk) {
+					return
+				}
+			}
+		}()
+		return forwarded, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// startStream starts streaming from backend and waits for the first chunk,
+// which is nil for an empty completion. Backends that cannot stream return
+// their whole completion as the first chunk and no channel.
+func (c *FallbackClient) startStream(ctx context.Context, backend FallbackBackend, prompt string) (*StreamChunk, <-chan StreamChunk, error) {
+	if backend.Limiter != nil {
+		if err := backend.Limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	streaming, ok := backend.Client.(IAIStreaming)
+	if !ok {
+		completion, err := backend.Client.GetCompletion(ctx, prompt)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &StreamChunk{Content: completion}, nil, nil
+	}
+
+	chunks, err := streaming.StreamCompletion(ctx, prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+	first, ok := <-chunks
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, chunks, nil
+	}
+	if first.Err != nil {
+		return nil, nil, first.Err
+	}
+	return &first, chunks, nil
+}
+
+func (c *FallbackClient) try(ctx context.Context, backend FallbackBackend, fn func(ctx context.Context) (string, error)) (string, error) {
+	if backend.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, backend.Timeout)
+		defer cancel()
+	}
+	if backend.Limiter != nil {
+		if err := backend.Limiter.Wait(ctx); err != nil {
+			return "", err
+		}
+	}
+	return fn(ctx)
+}
+
+// GetName returns the names of the backends in the order they are tried.
+func (c *FallbackClient) GetName() string {
+	names := make([]string, 0, len(c.backends))
+	for _, backend := range c.backends {
+		names = append(names, backend.Client.GetName())
+	}
+	return strings.Join(names, fallbackClientSeparator)
+}
+
+func (c *FallbackClient) Close() {
+	for _, backend := range c.backends {
+		backend.Client.Close()
+	}
+}
+
+type answeredByKey struct{}
+
+type answeredBy struct {
+	mu      sync.Mutex
+	backend string
+}
+
+// WithAnsweredBy returns a context in which a FallbackClient records the
+// backend that answered, and a function returning that backend. The function
+// returns an empty string if no FallbackClient answered.
+func WithAnsweredBy(ctx context.Context) (context.Context, func() string) {
+	a := &answeredBy{}
+	return context.WithValue(ctx, answeredByKey{}, a), func() string {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.backend
+	}
+}
+
+func recordAnsweredBy(ctx context.Context, backend string) {
+	if a, ok := ctx.Value(answeredByKey{}).(*answeredBy); ok {
+		a.mu.Lock()
+		a.backend = backend
+		a.mu.Unlock()
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAIClient answers with completion or fails with err, after delay.
+type fakeAIClient struct {
+	nopCloser
+	name       string
+	completion string
+	err        error
+	delay      time.Duration
+	calls      int
+}
+
+func (c *fakeAIClient) Configure(_ IAIConfig) error { return nil }
+
+func (c *fakeAIClient) GetCompletion(ctx context.Context, _ string) (string, error) {
+	c.calls++
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	return c.completion, c.err
+}
+
+func (c *fakeAIClient) GetName() string { return c.name }
+
+// fakeStreamingAIClient streams its completion, or fails before the first chunk.
+type fakeStreamingAIClient struct {
+	fakeAIClient
+}
+
+func (c *fakeStreamingAIClient) StreamCompletion(_ context.Context, _ string) (<-chan StreamChunk, error) {
+	chunks := make(chan StreamChunk, 1)
+	if c.err != nil {
+		chunks <- StreamChunk{Err: c.err}
+	} else {
+		chunks <- StreamChunk{Content: c.completion}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func TestFallbackClient(t *testing.T) {
+	down := &fakeAIClient{name: "ollama", err: errors.New("connection refused")}
+	slow := &fakeAIClient{name: "azureopenai", completion: "too late", delay: time.Second}
+	up := &fakeAIClient{name: "openai", completion: "explanation"}
+	client := NewFallbackClient([]FallbackBackend{
+		{Client: down},
+		{Client: slow, Timeout: 10 * time.Millisecond},
+		{Client: up},
+	})
+	require.Equal(t, "ollama,azureopenai,openai", client.GetName())
+
+	ctx, answeredBy := WithAnsweredBy(context.Background())
+	completion, err := client.GetCompletion(ctx, "prompt")
+	require.NoError(t, err)
+	require.Equal(t, "explanation", completion)
+	require.Equal(t, "openai", answeredBy())
+	require.Equal(t, 1, down.calls)
+	require.Equal(t, 1, slow.calls)
+}
+
+func TestFallbackClientAllFail(t *testing.T) {
+	client := NewFallbackClient([]FallbackBackend{
+		{Client: &fakeAIClient{name: "ollama", err: errors.New("connection refused")}},
+		{Client: &fakeAIClient{name: "openai", err: errors.New("error, status code: 429")}},
+	})
+
+	_, err := client.GetCompletion(context.Background(), "prompt")
+	require.ErrorContains(t, err, "ollama: connection refused")
+	require.ErrorContains(t, err, "openai: error, status code: 429")
+	require.True(t, IsRetryable(err))
+}
+
+func TestFallbackClientCancelled(t *testing.T) {
+	up := &fakeAIClient{name: "openai", completion: "explanation"}
+	client := NewFallbackClient([]FallbackBackend{
+		{Client: &fakeAIClient{name: "ollama", delay: time.Second}},
+		{Client: up},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.GetCompletion(ctx, "prompt")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Zero(t, up.calls, "a cancelled request must not fall back")
+}
+
+func TestFallbackClientStream(t *testing.T) {
+	client := NewFallbackClient([]FallbackBackend{
+		{Client: &fakeStreamingAIClient{fakeAIClient{name: "ollama", err: errors.New("model not loaded")}}},
+		{Client: &fakeAIClient{name: "openai", completion: "explanation"}},
+	})
+	require.True(t, SupportsStreaming(client))
+
+	ctx, answeredBy := WithAnsweredBy(context.Background())
+	var chunks []string
+	completion, err := GetCompletionStreamed(ctx, client, "prompt", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "explanation", completion)
+	require.Equal(t, []string{"explanation"}, chunks)
+	require.Equal(t, "openai", answeredBy())
+}
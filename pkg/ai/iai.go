@@ -100,6 +100,8 @@ func NewClient(provider string) IAI {
 type AIConfiguration struct {
 	Providers       []AIProvider `mapstructure:"providers"`
 	DefaultProvider string       `mapstructure:"defaultprovider"`
+	// Fallback lists the providers tried, in order, when the selected one fails.
+	Fallback []string `mapstructure:"fallback" yaml:"fallback,omitempty"`
 }
 
 type AIProvider struct {
@@ -120,6 +122,10 @@ type AIProvider struct {
 	MaxTokens      int           `mapstructure:"maxtokens" yaml:"maxtokens,omitempty"`
 	OrganizationId string        `mapstructure:"organizationid" yaml:"organizationid,omitempty"`
 	CustomHeaders  []http.Header `mapstructure:"customHeaders"`
+	// RequestsPerMinute limits the requests sent to the provider, 0 means no limit.
+	RequestsPerMinute int `mapstructure:"requestsperminute" yaml:"requestsperminute,omitempty"`
+	// Timeout bounds a request before falling back to the next provider, e.g. "30s".
+	Timeout string `mapstructure:"timeout" yaml:"timeout,omitempty"`
 }
 
 func (p *AIProvider) GetBaseURL() string {
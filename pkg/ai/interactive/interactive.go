@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/analysis"
 	"github.com/pterm/pterm"
 )
@@ -55,13 +56,15 @@ func (a *InteractionRunner) StartInteraction() {
 		contextWindow := fmt.Sprintf("%s %s %s", prompt, string(a.contextWindow),
 			queryString)
 
-		response, err := a.config.AIClient.GetCompletion(a.config.Context,
-			contextWindow)
+		_, err = ai.GetCompletionStreamed(a.config.Context, a.config.AIClient,
+			contextWindow, func(chunk string) {
+				pterm.Print(chunk)
+			})
+		pterm.Println()
 		if err != nil {
 			color.Red("Error: %v", err)
 			a.State <- E_EXITED
 			continue
 		}
-		pterm.Println(response)
 	}
 }
@@ -49,19 +49,18 @@ func (c *OllamaClient) Configure(config IAIConfig) error {
 	}
 
 	proxyEndpoint := config.GetProxyEndpoint()
-	httpClient := http.DefaultClient
+	var transport http.RoundTripper = http.DefaultTransport
 	if proxyEndpoint != "" {
 		proxyUrl, err := url.Parse(proxyEndpoint)
 		if err != nil {
 			return err
 		}
-		transport := &http.Transport{
+		transport = &http.Transport{
 			Proxy: http.ProxyURL(proxyUrl),
 		}
-
-		httpClient = &http.Client{
-			Transport: transport,
-		}
+	}
+	httpClient := &http.Client{
+		Transport: &retryAfterTransport{Origin: transport},
 	}
 
 	c.client = ollama.NewClient(baseClientURL, httpClient)
@@ -76,16 +75,20 @@ func (c *OllamaClient) Configure(config IAIConfig) error {
 	c.topP = config.GetTopP()
 	return nil
 }
-func (c *OllamaClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
-	req := &ollama.GenerateRequest{
+func (c *OllamaClient) generateRequest(prompt string, stream bool) *ollama.GenerateRequest {
+	return &ollama.GenerateRequest{
 		Model:  c.model,
 		Prompt: prompt,
-		Stream: new(bool),
+		Stream: &stream,
 		Options: map[string]interface{}{
 			"temperature": c.temperature,
 			"top_p":       c.topP,
 		},
 	}
+}
+
+func (c *OllamaClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	req := c.generateRequest(prompt, false)
 	completion := ""
 	respFunc := func(resp ollama.GenerateResponse) error {
 		completion = resp.Response
@@ -97,6 +100,26 @@ func (c *OllamaClient) GetCompletion(ctx context.Context, prompt string) (string
 	}
 	return completion, nil
 }
+func (c *OllamaClient) StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		err := c.client.Generate(ctx, c.generateRequest(prompt, true), func(resp ollama.GenerateResponse) error {
+			if resp.Response == "" {
+				return nil
+			}
+			if !sendChunk(ctx, chunks, StreamChunk{Content: resp.Response}) {
+				return ctx.Err()
+			}
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			sendChunk(ctx, chunks, StreamChunk{Err: err})
+		}
+	}()
+	return chunks, nil
+}
+
 func (a *OllamaClient) GetName() string {
 	return ollamaClientName
 }
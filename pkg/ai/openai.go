@@ -16,6 +16,7 @@ package ai
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -84,9 +85,8 @@ func (c *OpenAIClient) Configure(config IAIConfig) error {
 	return nil
 }
 
-func (c *OpenAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
-	// Create a completion request
-	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+func (c *OpenAIClient) chatCompletionRequest(prompt string) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
 		Model: c.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
@@ -99,13 +99,53 @@ func (c *OpenAIClient) GetCompletion(ctx context.Context, prompt string) (string
 		PresencePenalty:  presencePenalty,
 		FrequencyPenalty: frequencyPenalty,
 		TopP:             c.topP,
-	})
+	}
+}
+
+func (c *OpenAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	// Create a completion request
+	resp, err := c.client.CreateChatCompletion(ctx, c.chatCompletionRequest(prompt))
 	if err != nil {
 		return "", err
 	}
 	return resp.Choices[0].Message.Content, nil
 }
 
+func (c *OpenAIClient) StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	return streamChatCompletion(ctx, c.client, c.chatCompletionRequest(prompt))
+}
+
+// streamChatCompletion streams a chat completion from an OpenAI compatible API.
+func streamChatCompletion(ctx context.Context, client *openai.Client, req openai.ChatCompletionRequest) (<-chan StreamChunk, error) {
+	stream, err := client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				sendChunk(ctx, chunks, StreamChunk{Err: err})
+				return
+			}
+			if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+				continue
+			}
+			if !sendChunk(ctx, chunks, StreamChunk{Content: resp.Choices[0].Delta.Content}) {
+				return
+			}
+		}
+	}()
+	return chunks, nil
+}
+
 func (c *OpenAIClient) GetName() string {
 	return openAIClientName
 }
@@ -129,5 +169,9 @@ func (t *OpenAIHeaderTransport) RoundTrip(req *http.Request) (*http.Response, er
 		}
 	}
 
-	return t.Origin.RoundTrip(clonedReq)
+	resp, err := t.Origin.RoundTrip(clonedReq)
+	if err == nil {
+		recordRetryAfter(req.Context(), resp)
+	}
+	return resp, err
 }
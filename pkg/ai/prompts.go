@@ -49,6 +49,12 @@ const (
 	    - {list of container names}
 	`
 
+	incident_prompt = `Several Kubernetes objects report errors that share one probable root cause. They are listed below delimited by triple dashes, the root cause first, written in --- %s --- language; --- %s ---.
+	Explain how the root cause leads to the related errors and provide the most possible solution in a step by step style in no more than 400 characters. Write the output in the following format:
+	Error: {Explain the incident here}
+	Solution: {Step by step solution here}
+	`
+
 	kyverno_prompt = `Simplify the following Kyverno warnings message delimited by triple dashes written in --- %s --- language; --- %s ---.
 	Provide the most probable solution as a kubectl command. 
 
@@ -62,6 +68,7 @@ const (
 
 var PromptMap = map[string]string{
 	"default":                       default_prompt,
+	"incident":                      incident_prompt,   // for incidents of correlated results
 	"VulnerabilityReport":           trivy_vuln_prompt, // for Trivy integration, the key should match `Result.Kind` in pkg/common/types.go
 	"ConfigAuditReport":             trivy_conf_prompt,
 	"PrometheusConfigValidate":      prom_conf_prompt,
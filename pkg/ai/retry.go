@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	ollama "github.com/ollama/ollama/api"
+	"github.com/sashabaranov/go-openai"
+	"golang.org/x/time/rate"
+)
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*rate.Limiter{}
+)
+
+// ProviderLimiter returns the token bucket rate limiter shared by every
+// request to provider. Requests are spread evenly across the minute; a
+// requestsPerMinute of zero or less means no limit.
+func ProviderLimiter(provider string, requestsPerMinute int) *rate.Limiter {
+	limit := rate.Inf
+	if requestsPerMinute > 0 {
+		limit = rate.Limit(float64(requestsPerMinute) / 60)
+	}
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	limiter, ok := limiters[provider]
+	if !ok {
+		limiter = rate.NewLimiter(limit, 1)
+		limiters[provider] = limiter
+	} else if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	return limiter
+}
+
+// Backoff configures the retries of failed completions.
+type Backoff struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Initial is the delay before the first retry; it doubles on every retry.
+	Initial time.Duration
+	// Max caps the exponential delay. A longer Retry-After is still honored.
+	Max time.Duration
+}
+
+var DefaultBackoff = Backoff{
+	MaxRetries: 5,
+	Initial:    time.Second,
+	Max:        time.Minute,
+}
+
+// Delay returns how long to wait before retry number attempt (starting at 0).
+// A Retry-After sent by the backend takes precedence over the exponential
+// delay.
+func (b Backoff) Delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := b.Initial << attempt
+	if delay <= 0 || delay > b.Max {
+		delay = b.Max
+	}
+	// Add up to 20% jitter so concurrent requests do not retry in lockstep.
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it succeeds, fails with an error that is not
+// retryable or runs out of retries. Every attempt waits for limiter first.
+// The context passed to fn records the Retry-After header of HTTP responses
+// received by the clients of this package.
+func Retry(ctx context.Context, limiter *rate.Limiter, backoff Backoff, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		hint := &retryAfterHint{}
+		err := fn(context.WithValue(ctx, retryAfterKey{}, hint))
+		if err == nil || attempt >= backoff.MaxRetries || !IsRetryable(err) {
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				return permanent.err
+			}
+			return err
+		}
+
+		timer := time.NewTimer(backoff.Delay(attempt, hint.get()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryable reports whether err is a rate limit or a transient server error.
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	if err == nil || errors.As(err, &permanent) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if code := statusCode(err); code != 0 {
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+	// Backends without typed errors only mention the status code.
+	return strings.Contains(err.Error(), "status code: 429")
+}
+
+func statusCode(err error) int {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	var requestErr *openai.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.HTTPStatusCode
+	}
+	var ollamaErr ollama.StatusError
+	if errors.As(err, &ollamaErr) {
+		return ollamaErr.StatusCode
+	}
+	var awsErr awserr.RequestFailure
+	if errors.As(err, &awsErr) {
+		return awsErr.StatusCode()
+	}
+	return 0
+}
+
+type retryAfterKey struct{}
+
+// retryAfterHint carries the Retry-After of a failed response back to Retry,
+// since the errors of the backend SDKs do not expose response headers.
+type retryAfterHint struct {
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+func (h *retryAfterHint) get() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.delay
+}
+
+// recordRetryAfter stores the Retry-After header of resp, given in seconds or
+// as an HTTP date, in the hint of ctx.
+func recordRetryAfter(ctx context.Context, resp *http.Response) {
+	hint, ok := ctx.Value(retryAfterKey{}).(*retryAfterHint)
+	if !ok || resp == nil {
+		return
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	}
+	if delay <= 0 {
+		return
+	}
+	hint.mu.Lock()
+	hint.delay = delay
+	hint.mu.Unlock()
+}
+
+// retryAfterTransport records the Retry-After header of responses.
+type retryAfterTransport struct {
+	Origin http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Origin.RoundTrip(req)
+	if err == nil {
+		recordRetryAfter(req.Context(), resp)
+	}
+	return resp, err
+}
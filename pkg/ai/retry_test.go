@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+var testBackoff = Backoff{MaxRetries: 3, Initial: time.Millisecond, Max: 10 * time.Millisecond}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCall int
+	}{
+		{
+			name:         "rate limited",
+			err:          &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests},
+			expectedCall: 4,
+		},
+		{
+			name:         "server error",
+			err:          &openai.RequestError{HTTPStatusCode: http.StatusBadGateway},
+			expectedCall: 4,
+		},
+		{
+			name:         "bad request",
+			err:          &openai.APIError{HTTPStatusCode: http.StatusBadRequest},
+			expectedCall: 1,
+		},
+		{
+			name:         "permanent",
+			err:          Permanent(&openai.APIError{HTTPStatusCode: http.StatusTooManyRequests}),
+			expectedCall: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), nil, testBackoff, func(context.Context) error {
+				calls++
+				return tt.err
+			})
+			require.Error(t, err)
+			require.Equal(t, tt.expectedCall, calls)
+
+			var permanent *permanentError
+			require.False(t, errors.As(err, &permanent), "permanent marker must not leak")
+		})
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), rate.NewLimiter(rate.Inf, 1), testBackoff, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("error, status code: 429")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestRetryAfterRecorded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"rate limited"}}`)
+	}))
+	defer server.Close()
+
+	client := &OpenAIClient{}
+	require.NoError(t, client.Configure(&mockConfig{baseURL: server.URL}))
+
+	var retryAfter time.Duration
+	err := Retry(context.Background(), nil, Backoff{}, func(ctx context.Context) error {
+		_, err := client.GetCompletion(ctx, "prompt")
+		retryAfter = ctx.Value(retryAfterKey{}).(*retryAfterHint).get()
+		return err
+	})
+	require.True(t, IsRetryable(err))
+	require.Equal(t, 7*time.Second, retryAfter)
+	require.Equal(t, 7*time.Second, testBackoff.Delay(0, retryAfter))
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 4 * time.Second}
+	for attempt, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		delay := b.Delay(attempt, 0)
+		require.GreaterOrEqual(t, delay, base)
+		require.LessOrEqual(t, delay, base+base/5)
+	}
+}
+
+func TestProviderLimiter(t *testing.T) {
+	limiter := ProviderLimiter("test-provider", 120)
+	require.Equal(t, rate.Limit(2), limiter.Limit())
+	require.Same(t, limiter, ProviderLimiter("test-provider", 0))
+	require.Equal(t, rate.Inf, limiter.Limit())
+}
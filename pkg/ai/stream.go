@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"strings"
+)
+
+// StreamChunk is a piece of a streamed completion. A failed stream ends with
+// a chunk that has Err set.
+type StreamChunk struct {
+	Content string
+	Err     error
+}
+
+// IAIStreaming is implemented by clients that can stream completions.
+type IAIStreaming interface {
+	IAI
+	// StreamCompletion generates text based on prompt and sends it through the
+	// returned channel as it is produced. The channel is closed once the
+	// completion is done or ctx is cancelled.
+	StreamCompletion(ctx context.Context, prompt string) (<-chan StreamChunk, error)
+}
+
+// SupportsStreaming reports whether client can stream completions.
+func SupportsStreaming(client IAI) bool {
+	_, ok := client.(IAIStreaming)
+	return ok
+}
+
+// GetCompletionStreamed generates text based on prompt, calling onChunk with
+// every piece of the completion as it arrives, and returns the whole
+// completion. Clients that cannot stream call onChunk once with the result of
+// GetCompletion.
+func GetCompletionStreamed(ctx context.Context, client IAI, prompt string, onChunk func(string)) (string, error) {
+	streaming, ok := client.(IAIStreaming)
+	if !ok {
+		completion, err := client.GetCompletion(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		onChunk(completion)
+		return completion, nil
+	}
+
+	chunks, err := streaming.StreamCompletion(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	var completion strings.Builder
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return "", chunk.Err
+		}
+		completion.WriteString(chunk.Content)
+		onChunk(chunk.Content)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return completion.String(), nil
+}
+
+// sendChunk delivers chunk unless ctx is cancelled first.
+func sendChunk(ctx context.Context, chunks chan<- StreamChunk, chunk StreamChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectStream(t *testing.T, client IAI) ([]string, string) {
+	var chunks []string
+	completion, err := GetCompletionStreamed(context.Background(), client, "prompt", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	return chunks, completion
+}
+
+func TestOpenAIClient_StreamCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"The pod ", "is ", "pending."} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := &OpenAIClient{}
+	require.NoError(t, client.Configure(&mockConfig{baseURL: server.URL}))
+	require.True(t, SupportsStreaming(client))
+
+	chunks, completion := collectStream(t, client)
+	require.Equal(t, []string{"The pod ", "is ", "pending."}, chunks)
+	require.Equal(t, "The pod is pending.", completion)
+}
+
+func TestOpenAIClient_StreamCompletionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"rate limited"}}`)
+	}))
+	defer server.Close()
+
+	client := &OpenAIClient{}
+	require.NoError(t, client.Configure(&mockConfig{baseURL: server.URL}))
+
+	_, err := GetCompletionStreamed(context.Background(), client, "prompt", func(string) {})
+	require.ErrorContains(t, err, "429")
+}
+
+func TestOllamaClient_StreamCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/generate", r.URL.Path)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, token := range []string{"Check ", "the image."} {
+			fmt.Fprintf(w, "{\"model\":\"llama3\",\"response\":%q,\"done\":false}\n", token)
+		}
+		fmt.Fprint(w, "{\"model\":\"llama3\",\"response\":\"\",\"done\":true}\n")
+	}))
+	defer server.Close()
+
+	client := &OllamaClient{}
+	require.NoError(t, client.Configure(&mockConfig{baseURL: server.URL}))
+
+	chunks, completion := collectStream(t, client)
+	require.Equal(t, []string{"Check ", "the image."}, chunks)
+	require.Equal(t, "Check the image.", completion)
+}
+
+func TestGetCompletionStreamedWithoutStreaming(t *testing.T) {
+	client := &NoOpAIClient{}
+	require.False(t, SupportsStreaming(client))
+
+	chunks, completion := collectStream(t, client)
+	require.Equal(t, []string{completion}, chunks)
+}
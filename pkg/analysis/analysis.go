@@ -18,6 +18,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -30,9 +33,11 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/custom"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/snapshot"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
 )
 
 type Analysis struct {
@@ -52,6 +57,21 @@ type Analysis struct {
 	WithDoc            bool
 	WithStats          bool
 	Stats              []common.AnalysisStats
+	MinSeverity        common.Severity     // Failures below this severity are dropped
+	StreamOutput       io.Writer           // Receives the text output while explanations are generated
+	AIModel            string              // The model of the AI provider, part of the cache key
+	AITemperature      float32             // The temperature of the AI provider, part of the cache key
+	RateLimiter        *rate.Limiter       // Limits the requests to the AI backend, nil means no limit
+	Backoff            *ai.Backoff         // Retries of failed AI requests, ai.DefaultBackoff if nil
+	Clusters           []Cluster           // The clusters of a multi-cluster analysis, Client is the first reachable one
+	ClusterErrors      map[string][]string // Errors of a multi-cluster analysis, keyed by cluster
+	Incidents          []Incident          // Related results grouped by Correlate
+	Suppressions       []SuppressionRule   // Rules that silence known findings, see AddSuppressionRules
+	Suppressed         []SuppressedFinding // Findings silenced by a rule or IgnoreAnnotation
+	DeferStatsSave     bool                // Leave saving the cache statistics to the caller instead of Close
+
+	graphs map[string]objectGraph       // Objects related to the results, keyed by cluster
+	stores map[string]*kubernetes.Store // Objects read during the run, keyed by cluster
 }
 
 type (
@@ -65,11 +85,14 @@ const (
 )
 
 type JsonOutput struct {
-	Provider string          `json:"provider"`
-	Errors   AnalysisErrors  `json:"errors"`
-	Status   AnalysisStatus  `json:"status"`
-	Problems int             `json:"problems"`
-	Results  []common.Result `json:"results"`
+	Provider      string                    `json:"provider"`
+	Errors        AnalysisErrors            `json:"errors"`
+	ClusterErrors map[string]AnalysisErrors `json:"clusterErrors,omitempty"`
+	Status        AnalysisStatus            `json:"status"`
+	Problems      int                       `json:"problems"`
+	Results       []common.Result           `json:"results"`
+	Incidents     []Incident                `json:"incidents,omitempty"`
+	Suppressed    int                       `json:"suppressed,omitempty"`
 }
 
 func NewAnalysis(
@@ -85,13 +108,38 @@ func NewAnalysis(
 	interactiveMode bool,
 	httpHeaders []string,
 	withStats bool,
+	snapshotPath string,
+	contexts []string,
 ) (*Analysis, error) {
-	// Get kubernetes client from viper.
-	kubecontext := viper.GetString("kubecontext")
-	kubeconfig := viper.GetString("kubeconfig")
-	client, err := kubernetes.NewClient(kubecontext, kubeconfig)
-	if err != nil {
-		return nil, fmt.Errorf("initialising kubernetes client: %w", err)
+	var client *kubernetes.Client
+	var clusters []Cluster
+	var err error
+	if len(contexts) > 0 {
+		// Analyze every context; unreachable clusters are reported with the results.
+		clusters = newClusters(contexts, viper.GetString("kubeconfig"), maxConcurrency)
+		for _, cluster := range clusters {
+			if cluster.Err == nil {
+				client = cluster.Client
+				break
+			}
+		}
+		if client == nil {
+			return nil, fmt.Errorf("none of the contexts %s is reachable: %w", strings.Join(contexts, ", "), clusters[0].Err)
+		}
+	} else if snapshotPath != "" {
+		// Serve the analyzers from a captured snapshot instead of a live cluster.
+		client, err = snapshot.NewClient(snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Get kubernetes client from viper.
+		kubecontext := viper.GetString("kubecontext")
+		kubeconfig := viper.GetString("kubeconfig")
+		client, err = kubernetes.NewClient(kubecontext, kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("initialising kubernetes client: %w", err)
+		}
 	}
 
 	// Load remote cache if it is configured.
@@ -104,6 +152,11 @@ func NewAnalysis(
 		cache.DisableCache()
 	}
 
+	var suppressions []SuppressionRule
+	if err := viper.UnmarshalKey("suppressions", &suppressions); err != nil {
+		return nil, err
+	}
+
 	a := &Analysis{
 		Context:        context.Background(),
 		Filters:        filters,
@@ -116,6 +169,10 @@ func NewAnalysis(
 		MaxConcurrency: maxConcurrency,
 		WithDoc:        withDoc,
 		WithStats:      withStats,
+		Clusters:       clusters,
+	}
+	if err := a.AddSuppressionRules(suppressions, "config"); err != nil {
+		return nil, err
 	}
 	if !explain {
 		// Return early if AI use was not requested.
@@ -141,27 +198,83 @@ func NewAnalysis(
 		backend = "openai"
 	}
 
-	var aiProvider ai.AIProvider
-	for _, provider := range configAI.Providers {
-		if backend == provider.Name {
-			aiProvider = provider
-			break
+	if err := a.configureAIClient(configAI, backend, util.NewHeaders(httpHeaders)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// configureAIClient sets up the client of backend. If fallback providers are
+// configured it is wrapped in an ai.FallbackClient, which rate limits every
+// provider itself.
+func (a *Analysis) configureAIClient(configAI ai.AIConfiguration, backend string, customHeaders []http.Header) error {
+	chain := []string{backend}
+	for _, name := range configAI.Fallback {
+		if !slices.Contains(chain, name) {
+			chain = append(chain, name)
 		}
 	}
 
-	if aiProvider.Name == "" {
-		return nil, fmt.Errorf("AI provider %s not specified in configuration. Please run k8sgpt auth", backend)
+	var backends []ai.FallbackBackend
+	var models []string
+	var temperatures []float32
+	for _, name := range chain {
+		var aiProvider ai.AIProvider
+		for _, provider := range configAI.Providers {
+			if name == provider.Name {
+				aiProvider = provider
+				break
+			}
+		}
+
+		if aiProvider.Name == "" {
+			return fmt.Errorf("AI provider %s not specified in configuration. Please run k8sgpt auth", name)
+		}
+
+		var timeout time.Duration
+		if aiProvider.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(aiProvider.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid timeout for AI provider %s: %w", name, err)
+			}
+		}
+
+		aiClient := ai.NewClient(aiProvider.Name)
+		aiProvider.CustomHeaders = customHeaders
+		if err := aiClient.Configure(&aiProvider); err != nil {
+			return err
+		}
+		models = append(models, aiProvider.Model)
+		temperatures = append(temperatures, aiProvider.Temperature)
+		backends = append(backends, ai.FallbackBackend{
+			Client:  aiClient,
+			Limiter: ai.ProviderLimiter(aiProvider.Name, aiProvider.RequestsPerMinute),
+			Timeout: timeout,
+		})
 	}
 
-	aiClient := ai.NewClient(aiProvider.Name)
-	customHeaders := util.NewHeaders(httpHeaders)
-	aiProvider.CustomHeaders = customHeaders
-	if err := aiClient.Configure(&aiProvider); err != nil {
-		return nil, err
+	// A fallback chain can answer with any of its models, they are all part
+	// of the cache key.
+	a.AIModel = strings.Join(models, ",")
+	a.AITemperature = temperatures[0]
+	if len(backends) == 1 {
+		a.AIClient = backends[0].Client
+		a.RateLimiter = backends[0].Limiter
+	} else {
+		a.AIClient = ai.NewFallbackClient(backends)
 	}
-	a.AIClient = aiClient
-	a.AnalysisAIProvider = aiProvider.Name
-	return a, nil
+	a.AnalysisAIProvider = a.AIClient.GetName()
+	return nil
+}
+
+// fallbackNote tells which fallback provider explained result, if any.
+func (a *Analysis) fallbackNote(result common.Result) string {
+	primary, _, _ := strings.Cut(a.AnalysisAIProvider, ",")
+	if result.Backend == "" || result.Backend == primary {
+		return ""
+	}
+	return color.YellowString("(explained by fallback provider %s)\n", result.Backend)
 }
 
 func (a *Analysis) CustomAnalyzersAreAvailable() bool {
@@ -178,7 +291,16 @@ func (a *Analysis) RunCustomAnalysis() {
 		a.Errors = append(a.Errors, err.Error())
 		return
 	}
+	if len(a.Clusters) > 0 {
+		a.runClusters(func(clusterAnalysis *Analysis) { clusterAnalysis.runCustomAnalyzers(customAnalyzers) })
+		return
+	}
+	a.runCustomAnalyzers(customAnalyzers)
+}
 
+// runCustomAnalyzers runs customAnalyzers against the cluster of a.Client.
+func (a *Analysis) runCustomAnalyzers(customAnalyzers []custom.CustomAnalyzer) {
+	suppressor := a.newSuppressor(a.clusterStore(""))
 	semaphore := make(chan struct{}, a.MaxConcurrency)
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
@@ -187,102 +309,168 @@ func (a *Analysis) RunCustomAnalysis() {
 		semaphore <- struct{}{}
 		go func(analyzer custom.CustomAnalyzer, wg *sync.WaitGroup, semaphore chan struct{}) {
 			defer wg.Done()
-			canClient, err := custom.NewClient(cAnalyzer.Connection)
+			defer func() { <-semaphore }()
+			results, err := a.runCustomAnalyzer(analyzer)
+			results, suppressed := suppressor.apply(a.applySeverity(results))
+			mutex.Lock()
+			defer mutex.Unlock()
 			if err != nil {
-				mutex.Lock()
-				a.Errors = append(a.Errors, fmt.Sprintf("Client creation error for %s analyzer", cAnalyzer.Name))
-				mutex.Unlock()
+				a.Errors = append(a.Errors, fmt.Sprintf("[%s] %s", analyzer.Name, err))
 				return
 			}
-
-			result, err := canClient.Run()
-			if result.Kind == "" {
-				// for custom analyzer name, we must use a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.',
-				//and must start and end with an alphanumeric character (e.g. 'example.com',
-				//regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')
-				result.Kind = cAnalyzer.Name
-			}
-			if err != nil {
-				mutex.Lock()
-				a.Errors = append(a.Errors, fmt.Sprintf("[%s] %s", cAnalyzer.Name, err))
-				mutex.Unlock()
-			} else {
-				mutex.Lock()
-				a.Results = append(a.Results, result)
-				mutex.Unlock()
-			}
-			<-semaphore
+			a.Results = append(a.Results, results...)
+			a.Suppressed = append(a.Suppressed, suppressed...)
 		}(cAnalyzer, &wg, semaphore)
 	}
 	wg.Wait()
 }
 
-func (a *Analysis) RunAnalysis() {
-	activeFilters := viper.GetStringSlice("active_filters")
+// runCustomAnalyzer runs a custom analyzer within its timeout, scoped to the
+// namespace, label selector and filters of the analysis.
+func (a *Analysis) runCustomAnalyzer(analyzer custom.CustomAnalyzer) ([]common.Result, error) {
+	timeout, err := analyzer.GetTimeout()
+	if err != nil {
+		return nil, err
+	}
+	ctx := a.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	coreAnalyzerMap, analyzerMap := analyzer.GetAnalyzerMap()
+	var results []common.Result
+	if analyzer.Wasm.Path != "" {
+		client, err := custom.NewWasmClient(analyzer.Name, analyzer.Wasm)
+		if err != nil {
+			return nil, err
+		}
+		results, err = client.Run(ctx, a.Client.GetDynamicClient(), a.Client.GetRESTMapper(), a.Namespace, a.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		client, err := custom.NewClient(analyzer.Connection)
+		if err != nil {
+			return nil, fmt.Errorf("client creation error: %w", err)
+		}
+		defer client.Close()
 
-	// we get the openapi schema from the server only if required by the flag "with-doc"
-	openapiSchema := &openapi_v2.Document{}
-	if a.WithDoc {
-		var openApiErr error
+		// Probe the analyzer first so that dead analyzers are skipped with a
+		// clear reason instead of failing the run.
+		probeCtx, cancelProbe := context.WithTimeout(ctx, custom.DefaultProbeTimeout)
+		health, err := client.Check(probeCtx)
+		cancelProbe()
+		if err != nil {
+			return nil, fmt.Errorf("skipped, %s:%s is unavailable: %w", analyzer.Connection.Url, analyzer.Connection.Port, err)
+		}
+		if !health.Serving() {
+			return nil, fmt.Errorf("skipped, %s:%s reports %s", analyzer.Connection.Url, analyzer.Connection.Port, health.Status)
+		}
 
-		openapiSchema, openApiErr = a.Client.Client.Discovery().OpenAPISchema()
-		if openApiErr != nil {
-			a.Errors = append(a.Errors, fmt.Sprintf("[KubernetesDoc] %s", openApiErr))
+		results, err = client.Run(ctx, custom.RunOptions{
+			Namespace:     a.Namespace,
+			LabelSelector: a.LabelSelector,
+			Filters:       a.Filters,
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	analyzerConfig := common.Analyzer{
-		Client:        a.Client,
-		Context:       a.Context,
-		Namespace:     a.Namespace,
-		LabelSelector: a.LabelSelector,
-		AIClient:      a.AIClient,
-		OpenapiSchema: openapiSchema,
+	for i := range results {
+		if results[i].Kind == "" {
+			// for custom analyzer name, we must use a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.',
+			//and must start and end with an alphanumeric character (e.g. 'example.com',
+			//regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')
+			results[i].Kind = analyzer.Name
+		}
 	}
+	return results, nil
+}
+
+func (a *Analysis) RunAnalysis() {
+	analyzers := a.selectAnalyzers()
+	if len(a.Clusters) > 0 {
+		a.runClusters(func(clusterAnalysis *Analysis) { clusterAnalysis.runAnalyzers(analyzers) })
+		return
+	}
+	a.runAnalyzers(analyzers)
+}
+
+// runAnalyzers runs analyzers against the cluster of a.Client.
+func (a *Analysis) runAnalyzers(analyzers map[string]common.IAnalyzer) {
+	analyzerConfig := a.newAnalyzerConfig()
+	suppressor := a.newSuppressor(analyzerConfig.Store)
 
 	semaphore := make(chan struct{}, a.MaxConcurrency)
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
+	for name, analyzer := range analyzers {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go a.executeAnalyzer(analyzer, name, analyzerConfig, suppressor, semaphore, &wg, &mutex)
+	}
+	wg.Wait()
+}
+
+// selectAnalyzers returns the analyzers to run, keyed by filter name.
+func (a *Analysis) selectAnalyzers() map[string]common.IAnalyzer {
+	activeFilters := viper.GetStringSlice("active_filters")
+
+	coreAnalyzerMap, analyzerMap := analyzer.GetAnalyzerMap()
+
 	// if there are no filters selected and no active_filters then run coreAnalyzer
 	if len(a.Filters) == 0 && len(activeFilters) == 0 {
-		for name, analyzer := range coreAnalyzerMap {
-			wg.Add(1)
-			semaphore <- struct{}{}
-			go a.executeAnalyzer(analyzer, name, analyzerConfig, semaphore, &wg, &mutex)
-
-		}
-		wg.Wait()
-		return
+		return coreAnalyzerMap
 	}
+
+	selected := make(map[string]common.IAnalyzer)
 	// if the filters flag is specified
 	if len(a.Filters) != 0 {
 		for _, filter := range a.Filters {
 			if analyzer, ok := analyzerMap[filter]; ok {
-				semaphore <- struct{}{}
-				wg.Add(1)
-				go a.executeAnalyzer(analyzer, filter, analyzerConfig, semaphore, &wg, &mutex)
+				selected[filter] = analyzer
 			} else {
 				a.Errors = append(a.Errors, fmt.Sprintf("\"%s\" filter does not exist. Please run k8sgpt filters list.", filter))
 			}
 		}
-		wg.Wait()
-		return
+		return selected
 	}
 
 	// use active_filters
 	for _, filter := range activeFilters {
 		if analyzer, ok := analyzerMap[filter]; ok {
-			semaphore <- struct{}{}
-			wg.Add(1)
-			go a.executeAnalyzer(analyzer, filter, analyzerConfig, semaphore, &wg, &mutex)
+			selected[filter] = analyzer
 		}
 	}
-	wg.Wait()
+	return selected
+}
+
+func (a *Analysis) newAnalyzerConfig() common.Analyzer {
+	// we get the openapi schema from the server only if required by the flag "with-doc"
+	openapiSchema := &openapi_v2.Document{}
+	if a.WithDoc {
+		var openApiErr error
+
+		openapiSchema, openApiErr = a.Client.Client.Discovery().OpenAPISchema()
+		if openApiErr != nil {
+			a.Errors = append(a.Errors, fmt.Sprintf("[KubernetesDoc] %s", openApiErr))
+		}
+	}
+
+	return common.Analyzer{
+		Client:        a.Client,
+		Context:       a.Context,
+		Namespace:     a.Namespace,
+		LabelSelector: a.LabelSelector,
+		AIClient:      a.AIClient,
+		OpenapiSchema: openapiSchema,
+		Store:         a.clusterStore(""),
+	}
 }
 
-func (a *Analysis) executeAnalyzer(analyzer common.IAnalyzer, filter string, analyzerConfig common.Analyzer, semaphore chan struct{}, wg *sync.WaitGroup, mutex *sync.Mutex) {
+func (a *Analysis) executeAnalyzer(analyzer common.IAnalyzer, filter string, analyzerConfig common.Analyzer, suppressor *suppressor, semaphore chan struct{}, wg *sync.WaitGroup, mutex *sync.Mutex) {
 	defer wg.Done()
 
 	var startTime time.Time
@@ -304,6 +492,7 @@ func (a *Analysis) executeAnalyzer(analyzer common.IAnalyzer, filter string, ana
 		Analyzer:     filter,
 		DurationTime: elapsedTime,
 	}
+	results, suppressed := suppressor.apply(a.applySeverity(results))
 
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -318,86 +507,219 @@ func (a *Analysis) executeAnalyzer(analyzer common.IAnalyzer, filter string, ana
 			a.Stats = append(a.Stats, stat)
 		}
 		a.Results = append(a.Results, results...)
+		a.Suppressed = append(a.Suppressed, suppressed...)
 	}
 	<-semaphore
 }
 
+// applySeverity defaults unset failure severities and drops failures below
+// MinSeverity, along with results that are left without failures.
+func (a *Analysis) applySeverity(results []common.Result) []common.Result {
+	filtered := make([]common.Result, 0, len(results))
+	for _, result := range results {
+		var failures []common.Failure
+		for _, failure := range result.Error {
+			failure.Severity = failure.Level()
+			if failure.Severity.AtLeast(a.MinSeverity) {
+				failures = append(failures, failure)
+			}
+		}
+		if len(failures) == 0 {
+			continue
+		}
+		result.Error = failures
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// HasFailuresAtLeast reports whether any result has a failure of at least
+// the given severity.
+func (a *Analysis) HasFailuresAtLeast(severity common.Severity) bool {
+	return resultsHaveSeverity(a.Results, severity)
+}
+
+func resultsHaveSeverity(results []common.Result, severity common.Severity) bool {
+	for _, result := range results {
+		if result.MaxSeverity().AtLeast(severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAIResults explains the results with the AI backend, running up to
+// MaxConcurrency requests at a time. Each incident is explained with a single
+// request instead of one per result. Requests are rate limited by RateLimiter
+// and retried with Backoff. A result that cannot be explained is left without
+// details and reported in Errors; an error is only returned when no result
+// could be explained.
 func (a *Analysis) GetAIResults(output string, anonymize bool) error {
 	if len(a.Results) == 0 {
 		return nil
 	}
 
+	// Results that are part of an incident are explained with it and share
+	// its explanation, see shareIncidentDetails.
+	correlated := a.correlatedResults()
+	var explain []func(w io.Writer) error
+	var names []string
+	for index, result := range a.Results {
+		if correlated[index] {
+			continue
+		}
+		explain = append(explain, func(w io.Writer) error { return a.explainResult(index, anonymize, w) })
+		names = append(names, fmt.Sprintf("%s %s", result.Kind, result.Name))
+	}
+	for index, incident := range a.Incidents {
+		explain = append(explain, func(io.Writer) error { return a.explainIncident(index, anonymize) })
+		names = append(names, fmt.Sprintf("incident %s", incident.ID))
+	}
+
+	// Text output is streamed to StreamOutput as the explanations arrive
+	// instead of showing a progress bar. Incidents are only shown once all
+	// explanations are known.
+	stream := output == "text" && a.StreamOutput != nil && len(a.Incidents) == 0
+
 	var bar *progressbar.ProgressBar
-	if output != "json" {
-		bar = progressbar.Default(int64(len(a.Results)))
+	var ordered *orderedWriter
+	if stream {
+		fmt.Fprint(a.StreamOutput, a.textHeader())
+		ordered = newOrderedWriter(a.StreamOutput, len(explain))
+	} else if output != "json" {
+		bar = progressbar.Default(int64(len(explain)))
 	}
 
-	for index, analysis := range a.Results {
-		var texts []string
+	concurrency := a.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	errs := make([]error, len(explain))
+	for index := range explain {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
 
-		for _, failure := range analysis.Error {
-			if anonymize {
-				for _, s := range failure.Sensitive {
-					failure.Text = util.ReplaceIfMatch(failure.Text, s.Unmasked, s.Masked)
-				}
+			var w io.Writer
+			if stream {
+				w = ordered.Section(index)
+				defer ordered.Done(index)
 			}
-			texts = append(texts, failure.Text)
-		}
-
-		promptTemplate := ai.PromptMap["default"]
-		// If the resource `Kind` comes from an "integration plugin",
-		// maybe a customized prompt template will be involved.
-		if prompt, ok := ai.PromptMap[analysis.Kind]; ok {
-			promptTemplate = prompt
-		}
-		result, err := a.getAIResultForSanitizedFailures(texts, promptTemplate)
-		if err != nil {
-			// FIXME: can we avoid checking if output is json multiple times?
-			//   maybe implement the progress bar better?
-			if output != "json" {
-				_ = bar.Exit()
+			errs[index] = explain[index](w)
+			if bar != nil {
+				_ = bar.Add(1)
 			}
+		}(index)
+	}
+	wg.Wait()
 
-			// Check for exhaustion.
-			if strings.Contains(err.Error(), "status code: 429") {
-				return fmt.Errorf("exhausted API quota for AI provider %s: %v", a.AIClient.GetName(), err)
-			}
-			return fmt.Errorf("failed while calling AI provider %s: %v", a.AIClient.GetName(), err)
+	var failed []error
+	for index, err := range errs {
+		if err == nil {
+			continue
 		}
+		failed = append(failed, err)
+		a.Errors = append(a.Errors, fmt.Sprintf("failed to explain %s: %v", names[index], err))
+	}
+	if len(failed) < len(explain) {
+		return nil
+	}
+
+	// FIXME: can we avoid checking if output is json multiple times?
+	//   maybe implement the progress bar better?
+	if bar != nil {
+		_ = bar.Exit()
+	}
+	// Check for exhaustion.
+	if strings.Contains(failed[0].Error(), "status code: 429") {
+		return fmt.Errorf("exhausted API quota for AI provider %s: %v", a.AIClient.GetName(), failed[0])
+	}
+	return fmt.Errorf("failed while calling AI provider %s: %v", a.AIClient.GetName(), failed[0])
+}
 
+// explainResult sets the details of the result at index. If w is set the
+// result is rendered to it as text while the explanation arrives.
+func (a *Analysis) explainResult(index int, anonymize bool, w io.Writer) error {
+	analysis := a.Results[index]
+	var texts []string
+
+	for _, failure := range analysis.Error {
 		if anonymize {
-			for _, failure := range analysis.Error {
-				for _, s := range failure.Sensitive {
-					result = strings.ReplaceAll(result, s.Masked, s.Unmasked)
-				}
+			for _, s := range failure.Sensitive {
+				failure.Text = util.ReplaceIfMatch(failure.Text, s.Unmasked, s.Masked)
 			}
 		}
+		texts = append(texts, failure.Text)
+	}
+
+	promptTemplate := ai.PromptMap["default"]
+	// If the resource `Kind` comes from an "integration plugin",
+	// maybe a customized prompt template will be involved.
+	if prompt, ok := ai.PromptMap[analysis.Kind]; ok {
+		promptTemplate = prompt
+	}
+
+	var onChunk func(string)
+	var unmask *unmaskingWriter
+	if w != nil {
+		fmt.Fprint(w, textResult(index, analysis))
+		unmask = newUnmaskingWriter(w, analysis.Error, anonymize)
+		onChunk = unmask.Write
+	}
+	result, backend, err := a.getAIResultForSanitizedFailures(texts, promptTemplate, onChunk)
+	a.Results[index].Backend = backend
+	if w != nil {
+		unmask.Flush()
+		if err != nil {
+			fmt.Fprintln(w, color.RedString("Failed to explain: %v", err))
+		} else {
+			fmt.Fprint(w, a.fallbackNote(a.Results[index]))
+		}
+	}
+	if err != nil {
+		return err
+	}
 
-		analysis.Details = result
-		if output != "json" {
-			_ = bar.Add(1)
+	if anonymize {
+		for _, failure := range analysis.Error {
+			for _, s := range failure.Sensitive {
+				result = strings.ReplaceAll(result, s.Masked, s.Unmasked)
+			}
 		}
-		a.Results[index] = analysis
 	}
+
+	a.Results[index].Details = result
 	return nil
 }
 
-func (a *Analysis) getAIResultForSanitizedFailures(texts []string, promptTmpl string) (string, error) {
+// getAIResultForSanitizedFailures returns the explanation of the failure
+// texts and the backend that generated it, which is empty for cached
+// explanations. If onChunk is set the explanation is streamed to it as it
+// arrives.
+func (a *Analysis) getAIResultForSanitizedFailures(texts []string, promptTmpl string, onChunk func(string)) (string, string, error) {
 	inputKey := strings.Join(texts, " ")
 	// Check for cached data.
-	// TODO(bwplotka): This might depend on model too (or even other client configuration pieces), fix it in later PRs.
-	cacheKey := util.GetCacheKey(a.AIClient.GetName(), a.Language, inputKey)
+	cacheKey := util.GetModelCacheKey(a.AIClient.GetName(), a.AIModel, a.AITemperature, promptTmpl, a.Language, inputKey)
 
 	if !a.Cache.IsCacheDisabled() && a.Cache.Exists(cacheKey) {
 		response, err := a.Cache.Load(cacheKey)
-		if err != nil {
-			return "", err
+		// The entry may expire between Exists and Load, or have been
+		// encrypted with another key.
+		if err != nil && !cache.IsStale(err) {
+			return "", "", err
 		}
 
 		if response != "" {
 			output, err := base64.StdEncoding.DecodeString(response)
 			if err == nil {
-				return string(output), nil
+				if onChunk != nil {
+					onChunk(string(output))
+				}
+				return string(output), "", nil
 			}
 			color.Red("error decoding cached data; ignoring cache item: %v", err)
 		}
@@ -405,18 +727,58 @@ func (a *Analysis) getAIResultForSanitizedFailures(texts []string, promptTmpl st
 
 	// Process template.
 	prompt := fmt.Sprintf(strings.TrimSpace(promptTmpl), a.Language, inputKey)
-	response, err := a.AIClient.GetCompletion(a.Context, prompt)
+	ctx := a.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, answeredBy := ai.WithAnsweredBy(ctx)
+	var response string
+	err := ai.Retry(ctx, a.RateLimiter, a.backoff(), func(ctx context.Context) error {
+		var err error
+		if onChunk == nil {
+			response, err = a.AIClient.GetCompletion(ctx, prompt)
+			return err
+		}
+		started := false
+		response, err = ai.GetCompletionStreamed(ctx, a.AIClient, prompt, func(chunk string) {
+			started = true
+			onChunk(chunk)
+		})
+		if err != nil && started {
+			// Part of the explanation is already shown, do not repeat it.
+			return ai.Permanent(err)
+		}
+		return err
+	})
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	backend := answeredBy()
+	if backend == "" {
+		backend = a.AIClient.GetName()
 	}
 
 	if err = a.Cache.Store(cacheKey, base64.StdEncoding.EncodeToString([]byte(response))); err != nil {
 		color.Red("error storing value to cache; value won't be cached: %v", err)
 	}
-	return response, nil
+	return response, backend, nil
+}
+
+func (a *Analysis) backoff() ai.Backoff {
+	if a.Backoff != nil {
+		return *a.Backoff
+	}
+	return ai.DefaultBackoff
 }
 
 func (a *Analysis) Close() {
+	// Keep the cache statistics of this run for `k8sgpt cache list`.
+	if !a.DeferStatsSave {
+		if err := cache.SaveStats(); err != nil {
+			color.Yellow("warning: error while saving cache statistics: %v", err)
+		}
+	}
 	if a.AIClient == nil {
 		return
 	}
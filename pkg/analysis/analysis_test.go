@@ -16,15 +16,19 @@ package analysis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/adrg/xdg"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/magiconair/properties/assert"
+	"github.com/sashabaranov/go-openai"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
@@ -393,7 +397,7 @@ func TestGetAIResultForSanitizedFailures(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			output, err := tt.a.getAIResultForSanitizedFailures(tt.texts, tt.promptTmpl)
+			output, _, err := tt.a.getAIResultForSanitizedFailures(tt.texts, tt.promptTmpl, nil)
 			if tt.expectedErr == "" {
 				require.NoError(t, err)
 				require.Equal(t, tt.expectedOutput, output)
@@ -404,3 +408,201 @@ func TestGetAIResultForSanitizedFailures(t *testing.T) {
 		})
 	}
 }
+
+// selectiveAIClient fails for prompts mentioning "broken".
+type selectiveAIClient struct {
+	ai.NoOpAIClient
+}
+
+func (c *selectiveAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	if strings.Contains(prompt, "broken") {
+		return "", &openai.APIError{HTTPStatusCode: http.StatusBadRequest, Message: "bad request"}
+	}
+	return c.NoOpAIClient.GetCompletion(ctx, prompt)
+}
+
+func TestGetAIResultsPartialFailure(t *testing.T) {
+	disabledCache := cache.New("disabled-cache")
+	disabledCache.DisableCache()
+
+	a := Analysis{
+		AIClient:       &selectiveAIClient{},
+		Cache:          disabledCache,
+		MaxConcurrency: 2,
+		Results: []common.Result{
+			{Kind: "Pod", Name: "default/ok", Error: []common.Failure{{Text: "Pod ok is pending"}}},
+			{Kind: "Pod", Name: "default/broken", Error: []common.Failure{{Text: "Pod broken is pending"}}},
+			{Kind: "Pod", Name: "default/fine", Error: []common.Failure{{Text: "Pod fine is pending"}}},
+		},
+	}
+	require.NoError(t, a.GetAIResults("json", false))
+	require.NotEmpty(t, a.Results[0].Details)
+	require.Empty(t, a.Results[1].Details)
+	require.NotEmpty(t, a.Results[2].Details)
+	require.Len(t, a.Errors, 1)
+	require.Contains(t, a.Errors[0], "failed to explain Pod default/broken")
+
+	// Without any explanation the run fails.
+	a.Results = a.Results[1:2]
+	a.Errors = nil
+	require.ErrorContains(t, a.GetAIResults("json", false), "failed while calling AI provider")
+}
+
+// downAIClient is a backend that cannot be reached.
+type downAIClient struct {
+	ai.NoOpAIClient
+}
+
+func (c *downAIClient) GetCompletion(_ context.Context, _ string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (c *downAIClient) GetName() string {
+	return "ollama"
+}
+
+func TestGetAIResultsFallback(t *testing.T) {
+	disabledCache := cache.New("disabled-cache")
+	disabledCache.DisableCache()
+
+	client := ai.NewFallbackClient([]ai.FallbackBackend{
+		{Client: &downAIClient{}},
+		{Client: &ai.NoOpAIClient{}},
+	})
+	a := Analysis{
+		AIClient:           client,
+		AnalysisAIProvider: client.GetName(),
+		Explain:            true,
+		Cache:              disabledCache,
+		Results: []common.Result{
+			{Kind: "Pod", Name: "default/web", Error: []common.Failure{{Text: "Pod web is pending"}}},
+		},
+	}
+	require.NoError(t, a.GetAIResults("json", false))
+	require.Equal(t, "noopai", a.Results[0].Backend)
+
+	output, err := a.textOutput()
+	require.NoError(t, err)
+	require.Contains(t, string(output), "AI Provider: ollama,noopai")
+	require.Contains(t, string(output), "(explained by fallback provider noopai)")
+}
+
+func TestConfigureAIClient(t *testing.T) {
+	configAI := ai.AIConfiguration{
+		Providers: []ai.AIProvider{
+			{Name: "openai", Password: "secret", Model: "gpt-4o", Temperature: 0.7},
+			{Name: "localai", BaseURL: "http://localhost:8080/v1", Model: "llama3", Timeout: "30s"},
+		},
+	}
+
+	a := &Analysis{}
+	require.NoError(t, a.configureAIClient(configAI, "openai", nil))
+	require.IsType(t, &ai.OpenAIClient{}, a.AIClient)
+	require.NotNil(t, a.RateLimiter)
+	require.Equal(t, "gpt-4o", a.AIModel)
+	require.Equal(t, float32(0.7), a.AITemperature)
+
+	configAI.Fallback = []string{"localai", "openai"}
+	a = &Analysis{}
+	require.NoError(t, a.configureAIClient(configAI, "openai", nil))
+	require.IsType(t, &ai.FallbackClient{}, a.AIClient)
+	require.Equal(t, "openai,localai", a.AnalysisAIProvider)
+	require.Equal(t, "gpt-4o,llama3", a.AIModel)
+	require.Nil(t, a.RateLimiter)
+
+	configAI.Providers[1].Timeout = "soon"
+	require.ErrorContains(t, (&Analysis{}).configureAIClient(configAI, "openai", nil), "invalid timeout for AI provider localai")
+
+	configAI.Fallback = []string{"ollama"}
+	require.ErrorContains(t, (&Analysis{}).configureAIClient(configAI, "openai", nil), "AI provider ollama not specified in configuration")
+}
+
+func TestGetAIResultForSanitizedFailuresCacheKey(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	xdg.Reload()
+	defer xdg.Reload()
+
+	fileCache := cache.New("file")
+	a := Analysis{
+		AIClient: &ai.NoOpAIClient{},
+		Cache:    fileCache,
+		AIModel:  "gpt-4o",
+		Language: "english",
+	}
+	first, _, err := a.getAIResultForSanitizedFailures([]string{"Pod web is pending"}, "%s %s", nil)
+	require.NoError(t, err)
+	entries, err := fileCache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Another model or prompt template must not be served from the cache.
+	a.AIModel = "llama3"
+	_, _, err = a.getAIResultForSanitizedFailures([]string{"Pod web is pending"}, "%s %s", nil)
+	require.NoError(t, err)
+	_, _, err = a.getAIResultForSanitizedFailures([]string{"Pod web is pending"}, "Explain in %s: %s", nil)
+	require.NoError(t, err)
+	entries, err = fileCache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	a.AIModel = "gpt-4o"
+	cached, backend, err := a.getAIResultForSanitizedFailures([]string{"Pod web is pending"}, "%s %s", nil)
+	require.NoError(t, err)
+	require.Equal(t, first, cached)
+	require.Empty(t, backend, "cached explanations have no backend")
+}
+
+func TestApplySeverity(t *testing.T) {
+	results := []common.Result{
+		{
+			Kind: "CronJob",
+			Name: "default/suspended",
+			Error: []common.Failure{
+				{Text: "CronJob suspended is suspended", Severity: common.SeverityInfo},
+			},
+		},
+		{
+			Kind: "Pod",
+			Name: "default/broken",
+			Error: []common.Failure{
+				{Text: "readiness probe failed", Severity: common.SeverityWarning},
+				{Text: "back-off restarting failed container", Severity: common.SeverityCritical},
+			},
+		},
+		{
+			// Custom analyzers do not set a severity.
+			Kind:  "custom",
+			Name:  "custom",
+			Error: []common.Failure{{Text: "custom failure"}},
+		},
+	}
+
+	a := Analysis{}
+	all := a.applySeverity(results)
+	require.Len(t, all, 3)
+	require.Equal(t, common.SeverityWarning, all[2].Error[0].Severity)
+
+	a.MinSeverity = common.SeverityCritical
+	critical := a.applySeverity(results)
+	require.Len(t, critical, 1)
+	require.Equal(t, "default/broken", critical[0].Name)
+	require.Len(t, critical[0].Error, 1)
+
+	a.Results = all
+	require.True(t, a.HasFailuresAtLeast(common.SeverityCritical))
+	a.Results = all[:1]
+	require.False(t, a.HasFailuresAtLeast(common.SeverityWarning))
+}
+
+func TestRunCustomAnalysisSkipsUnavailableAnalyzers(t *testing.T) {
+	viper.Set("custom_analyzers", []map[string]interface{}{
+		{"name": "dead-analyzer", "connection": map[string]interface{}{"url": "127.0.0.1", "port": "1"}},
+	})
+	defer viper.Set("custom_analyzers", nil)
+
+	a := Analysis{Context: context.Background(), MaxConcurrency: 1}
+	a.RunCustomAnalysis()
+	require.Empty(t, a.Results)
+	require.Len(t, a.Errors, 1)
+	require.Contains(t, a.Errors[0], "[dead-analyzer] skipped, 127.0.0.1:1 is unavailable: Unavailable")
+}
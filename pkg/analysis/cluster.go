@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Cluster is one of the clusters of a multi-cluster analysis, named after
+// its kubeconfig context.
+type Cluster struct {
+	Name   string
+	Client *kubernetes.Client
+	// Err is set if no client could be created for the cluster.
+	Err error
+}
+
+// ListContexts returns the names of the contexts in kubeconfig, or in the
+// default kubeconfig if it is empty.
+func ListContexts(kubeconfig string) ([]string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+	config, err := loadingRules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	if len(contexts) == 0 {
+		return nil, errors.New("the kubeconfig has no contexts")
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// newClusters creates a client for each context concurrently. Clusters that
+// cannot be reached are returned with their error.
+func newClusters(contexts []string, kubeconfig string, maxConcurrency int) []Cluster {
+	clusters := make([]Cluster, len(contexts))
+	semaphore := make(chan struct{}, max(maxConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, context := range contexts {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, context string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			client, err := kubernetes.NewClient(context, kubeconfig)
+			if err != nil {
+				err = fmt.Errorf("initialising kubernetes client: %w", err)
+			}
+			clusters[i] = Cluster{Name: context, Client: client, Err: err}
+		}(i, context)
+	}
+	wg.Wait()
+	return clusters
+}
+
+// runClusters calls run with an analysis of every cluster, at most
+// MaxConcurrency clusters at a time. Results are tagged with their cluster and
+// errors are kept per cluster.
+func (a *Analysis) runClusters(run func(clusterAnalysis *Analysis)) {
+	if a.ClusterErrors == nil {
+		a.ClusterErrors = map[string][]string{}
+	}
+	// The goroutines copy the settings from base; a itself receives their
+	// results.
+	base := *a
+	base.stores = maps.Clone(a.stores)
+
+	semaphore := make(chan struct{}, max(a.MaxConcurrency, 1))
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, cluster := range a.Clusters {
+		if cluster.Err != nil {
+			mutex.Lock()
+			a.ClusterErrors[cluster.Name] = append(a.ClusterErrors[cluster.Name], cluster.Err.Error())
+			mutex.Unlock()
+			continue
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(cluster Cluster) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			// Each cluster runs on a copy of the analysis that shares its
+			// settings but has its own client, results and errors.
+			clusterAnalysis := base
+			clusterAnalysis.Client = cluster.Client
+			clusterAnalysis.Clusters = nil
+			clusterAnalysis.ClusterErrors = nil
+			clusterAnalysis.Results = nil
+			clusterAnalysis.Errors = nil
+			clusterAnalysis.Stats = nil
+			clusterAnalysis.Suppressed = nil
+			// The custom and the built-in analyzers of a cluster share its
+			// store.
+			clusterAnalysis.stores = nil
+			if store, ok := base.stores[cluster.Name]; ok {
+				clusterAnalysis.stores = map[string]*kubernetes.Store{"": store}
+			}
+			run(&clusterAnalysis)
+
+			for i := range clusterAnalysis.Results {
+				clusterAnalysis.Results[i].Cluster = cluster.Name
+			}
+			for i := range clusterAnalysis.Suppressed {
+				clusterAnalysis.Suppressed[i].Cluster = cluster.Name
+			}
+			for i := range clusterAnalysis.Stats {
+				clusterAnalysis.Stats[i].Analyzer = fmt.Sprintf("%s/%s", cluster.Name, clusterAnalysis.Stats[i].Analyzer)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			a.Results = append(a.Results, clusterAnalysis.Results...)
+			a.Stats = append(a.Stats, clusterAnalysis.Stats...)
+			a.Suppressed = append(a.Suppressed, clusterAnalysis.Suppressed...)
+			// Correlation reads the objects the analyzers already listed.
+			if a.stores == nil {
+				a.stores = map[string]*kubernetes.Store{}
+			}
+			a.stores[cluster.Name] = clusterAnalysis.clusterStore("")
+			if len(clusterAnalysis.Errors) > 0 {
+				a.ClusterErrors[cluster.Name] = append(a.ClusterErrors[cluster.Name], clusterAnalysis.Errors...)
+			}
+		}(cluster)
+	}
+	wg.Wait()
+
+	// Keep the merged report stable between runs.
+	sort.SliceStable(a.Results, func(i, j int) bool {
+		return a.Results[i].Cluster < a.Results[j].Cluster
+	})
+}
+
+// warnings returns the errors of the analysis followed by the errors of each
+// cluster, prefixed with its name.
+func (a *Analysis) warnings() []string {
+	warnings := append([]string{}, a.Errors...)
+	clusters := make([]string, 0, len(a.ClusterErrors))
+	for cluster := range a.ClusterErrors {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		for _, err := range a.ClusterErrors[cluster] {
+			warnings = append(warnings, fmt.Sprintf("[%s] %s", cluster, err))
+		}
+	}
+	return warnings
+}
+
+// clusterErrorsOutput returns the errors of each cluster for the JSON output,
+// nil if the analysis has none.
+func (a *Analysis) clusterErrorsOutput() map[string]AnalysisErrors {
+	if len(a.ClusterErrors) == 0 {
+		return nil
+	}
+	clusterErrors := make(map[string]AnalysisErrors, len(a.ClusterErrors))
+	for cluster, errs := range a.ClusterErrors {
+		clusterErrors[cluster] = errs
+	}
+	return clusterErrors
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pendingPodClient(name string) *kubernetes.Client {
+	return &kubernetes.Client{Client: fake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{{
+				Type:    v1.PodScheduled,
+				Reason:  "Unschedulable",
+				Message: "0/1 nodes are available",
+			}},
+		},
+	})}
+}
+
+func TestRunAnalysisClusters(t *testing.T) {
+	a := Analysis{
+		Context:        context.Background(),
+		Filters:        []string{"Pod"},
+		MaxConcurrency: 2,
+		WithStats:      true,
+		Clusters: []Cluster{
+			{Name: "prod-us", Client: pendingPodClient("api")},
+			{Name: "prod-eu", Client: pendingPodClient("web")},
+			{Name: "staging", Err: errors.New("initialising kubernetes client: connection refused")},
+		},
+	}
+	a.RunAnalysis()
+
+	require.Len(t, a.Results, 2)
+	require.Equal(t, "prod-eu", a.Results[0].Cluster)
+	require.Equal(t, "default/web", a.Results[0].Name)
+	require.Equal(t, "prod-us", a.Results[1].Cluster)
+	require.Equal(t, "default/api", a.Results[1].Name)
+	require.Empty(t, a.Errors)
+	require.Equal(t, map[string][]string{
+		"staging": {"initialising kubernetes client: connection refused"},
+	}, a.ClusterErrors)
+	require.ElementsMatch(t, []string{"prod-us/Pod", "prod-eu/Pod"}, []string{a.Stats[0].Analyzer, a.Stats[1].Analyzer})
+
+	// The same failure in two clusters is reported twice.
+	require.NotEqual(t, a.Results[0].Fingerprint(), common.Result{Kind: "Pod", Name: "default/web"}.Fingerprint())
+
+	output, err := a.PrintOutput("json")
+	require.NoError(t, err)
+	var jsonOutput JsonOutput
+	require.NoError(t, json.Unmarshal(output, &jsonOutput))
+	require.Equal(t, 2, jsonOutput.Problems)
+	require.Equal(t, AnalysisErrors{"initialising kubernetes client: connection refused"}, jsonOutput.ClusterErrors["staging"])
+
+	output, err = a.PrintOutput("text")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "[staging] initialising kubernetes client: connection refused")
+	require.Contains(t, string(output), "[prod-eu] Pod default/web")
+}
+
+func TestRunCustomAnalysisClusters(t *testing.T) {
+	viper.Set("custom_analyzers", []map[string]interface{}{
+		{"name": "dead-analyzer", "connection": map[string]interface{}{"url": "127.0.0.1", "port": "1"}},
+	})
+	defer viper.Set("custom_analyzers", nil)
+
+	a := Analysis{
+		Context:        context.Background(),
+		MaxConcurrency: 2,
+		Clusters: []Cluster{
+			{Name: "prod-us", Client: pendingPodClient("api")},
+			{Name: "prod-eu", Client: pendingPodClient("web")},
+		},
+	}
+	a.RunCustomAnalysis()
+
+	// The custom analyzers run once per cluster.
+	require.Empty(t, a.Errors)
+	require.Len(t, a.ClusterErrors, 2)
+	for _, cluster := range []string{"prod-us", "prod-eu"} {
+		require.Len(t, a.ClusterErrors[cluster], 1)
+		require.Contains(t, a.ClusterErrors[cluster][0], "[dead-analyzer] skipped")
+	}
+}
+
+func TestListContexts(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: local
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: admin
+contexts:
+- name: prod-us
+  context: {cluster: local, user: admin}
+- name: prod-eu
+  context: {cluster: local, user: admin}
+`), 0o600))
+
+	contexts, err := ListContexts(kubeconfig)
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod-eu", "prod-us"}, contexts)
+
+	_, err = ListContexts(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
+)
+
+// IncidentObject is an object with findings that is part of an incident.
+type IncidentObject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Incident groups findings that share one probable root cause, such as a
+// failing Pod, the Deployment with unavailable replicas and the Service
+// without ready endpoints.
+type Incident struct {
+	// ID is the fingerprint of the root cause, stable across runs.
+	ID        string           `json:"id"`
+	Cluster   string           `json:"cluster,omitempty"`
+	RootCause IncidentObject   `json:"rootCause"`
+	Related   []IncidentObject `json:"related"`
+	Details   string           `json:"details"`
+	Backend   string           `json:"backend,omitempty"`
+
+	rootCause int   // index of the root cause in Analysis.Results
+	related   []int // indices of the related results in Analysis.Results
+}
+
+// Correlate groups the results into incidents. Findings are linked through
+// the objects of the cluster, see buildObjectGraph; a finding whose object is
+// affected by another finding is reported with that finding's incident.
+// Results that are not related to another result are left alone.
+func (a *Analysis) Correlate() {
+	a.Incidents = nil
+
+	byCluster := map[string][]int{}
+	for i, result := range a.Results {
+		byCluster[result.Cluster] = append(byCluster[result.Cluster], i)
+	}
+	for _, cluster := range sortedMapKeys(byCluster) {
+		graph, ok := a.clusterGraph(cluster)
+		if !ok {
+			continue
+		}
+		a.Incidents = append(a.Incidents, a.incidents(cluster, byCluster[cluster], graph)...)
+	}
+}
+
+// incidents groups the results at indices. A result affected by another
+// result is a symptom. The other results are candidate root causes; those
+// that affect the same results are merged into one incident, whose root
+// cause is the candidate that affects the most results.
+func (a *Analysis) incidents(cluster string, indices []int, graph objectGraph) []Incident {
+	resultAt := map[string]int{}
+	for _, i := range indices {
+		resultAt[resultKey(a.Results[i])] = i
+	}
+
+	// effects lists the results affected by each result.
+	effects := map[int][]int{}
+	symptom := map[int]bool{}
+	for key, i := range resultAt {
+		visited := map[string]bool{key: true}
+		queue := []string{key}
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			for _, edge := range graph[next] {
+				effect := edge.to
+				if visited[effect] {
+					continue
+				}
+				visited[effect] = true
+				queue = append(queue, effect)
+				if j, ok := resultAt[effect]; ok {
+					effects[i] = append(effects[i], j)
+					symptom[j] = true
+				}
+			}
+		}
+	}
+
+	// Merge the candidates that share affected results.
+	groups := map[int]int{}
+	var find func(int) int
+	find = func(i int) int {
+		if parent, ok := groups[i]; ok && parent != i {
+			groups[i] = find(parent)
+			return groups[i]
+		}
+		return i
+	}
+	owner := map[int]int{}
+	for _, i := range indices {
+		if symptom[i] || len(effects[i]) == 0 {
+			continue
+		}
+		groups[i] = i
+		for _, j := range effects[i] {
+			if other, ok := owner[j]; ok {
+				groups[find(i)] = find(other)
+			} else {
+				owner[j] = i
+			}
+		}
+	}
+	members := map[int][]int{}
+	for i := range groups {
+		members[find(i)] = append(members[find(i)], i)
+	}
+
+	var incidents []Incident
+	for _, candidates := range members {
+		rootCause := candidates[0]
+		for _, i := range candidates[1:] {
+			if a.moreLikelyRootCause(i, rootCause, effects) {
+				rootCause = i
+			}
+		}
+		related := map[int]bool{}
+		for _, i := range candidates {
+			if i != rootCause {
+				related[i] = true
+			}
+			for _, j := range effects[i] {
+				related[j] = true
+			}
+		}
+
+		incident := Incident{
+			ID:        a.Results[rootCause].Fingerprint(),
+			Cluster:   cluster,
+			RootCause: incidentObject(a.Results[rootCause]),
+			rootCause: rootCause,
+		}
+		for i := range related {
+			incident.related = append(incident.related, i)
+		}
+		sort.Slice(incident.related, func(x, y int) bool {
+			rx, ry := a.Results[incident.related[x]], a.Results[incident.related[y]]
+			if rx.Kind != ry.Kind {
+				return rx.Kind < ry.Kind
+			}
+			return rx.Name < ry.Name
+		})
+		for _, i := range incident.related {
+			incident.Related = append(incident.Related, incidentObject(a.Results[i]))
+		}
+		incidents = append(incidents, incident)
+	}
+	sort.Slice(incidents, func(i, j int) bool {
+		ri, rj := incidents[i].RootCause, incidents[j].RootCause
+		if ri.Kind != rj.Kind {
+			return ri.Kind < rj.Kind
+		}
+		return ri.Name < rj.Name
+	})
+	return incidents
+}
+
+// moreLikelyRootCause reports whether the result at i is a more likely root
+// cause than the result at j: it affects more results, or as many with a
+// more severe failure, or it comes first by kind and name.
+func (a *Analysis) moreLikelyRootCause(i, j int, effects map[int][]int) bool {
+	if len(effects[i]) != len(effects[j]) {
+		return len(effects[i]) > len(effects[j])
+	}
+	ri, rj := a.Results[i], a.Results[j]
+	if si, sj := ri.MaxSeverity(), rj.MaxSeverity(); si != sj {
+		return si.AtLeast(sj)
+	}
+	if ri.Kind != rj.Kind {
+		return ri.Kind < rj.Kind
+	}
+	return ri.Name < rj.Name
+}
+
+func incidentObject(result common.Result) IncidentObject {
+	return IncidentObject{Kind: result.Kind, Name: result.Name}
+}
+
+// correlatedResults returns the indices of the results that are part of an
+// incident.
+func (a *Analysis) correlatedResults() map[int]bool {
+	correlated := map[int]bool{}
+	for _, incident := range a.Incidents {
+		correlated[incident.rootCause] = true
+		for _, i := range incident.related {
+			correlated[i] = true
+		}
+	}
+	return correlated
+}
+
+// explainIncident sets the details of the incident at index with a single
+// request describing all of its findings.
+func (a *Analysis) explainIncident(index int, anonymize bool) error {
+	incident := a.Incidents[index]
+	var failures []common.Failure
+	describe := func(role string, result common.Result) string {
+		var texts []string
+		for _, failure := range result.Error {
+			failures = append(failures, failure)
+			if anonymize {
+				for _, s := range failure.Sensitive {
+					failure.Text = util.ReplaceIfMatch(failure.Text, s.Unmasked, s.Masked)
+				}
+			}
+			texts = append(texts, failure.Text)
+		}
+		return fmt.Sprintf("%s %s: %s", role, result.Kind, strings.Join(texts, " "))
+	}
+
+	texts := []string{describe("Root cause", a.Results[incident.rootCause])}
+	for _, i := range incident.related {
+		texts = append(texts, describe("Related", a.Results[i]))
+	}
+
+	result, backend, err := a.getAIResultForSanitizedFailures(texts, ai.PromptMap["incident"], nil)
+	if err != nil {
+		return err
+	}
+	if anonymize {
+		for _, failure := range failures {
+			for _, s := range failure.Sensitive {
+				result = strings.ReplaceAll(result, s.Masked, s.Unmasked)
+			}
+		}
+	}
+	a.Incidents[index].Details = result
+	a.Incidents[index].Backend = backend
+	a.shareIncidentDetails(index)
+	return nil
+}
+
+// shareIncidentDetails copies the details of the incident at index to its
+// results, so output formats that show results rather than incidents keep the
+// explanation.
+func (a *Analysis) shareIncidentDetails(index int) {
+	incident := a.Incidents[index]
+	if incident.Details == "" {
+		return
+	}
+	root := &a.Results[incident.rootCause]
+	root.Details = fmt.Sprintf("Root cause of incident %s.\n%s", incident.ID, incident.Details)
+	root.Backend = incident.Backend
+	for _, i := range incident.related {
+		a.Results[i].Details = fmt.Sprintf("Related to %s %s, the root cause of incident %s.\n%s",
+			incident.RootCause.Kind, incident.RootCause.Name, incident.ID, incident.Details)
+		a.Results[i].Backend = incident.Backend
+	}
+}
+
+// incidentOf returns the ID of the incident of every correlated result, by
+// index in Analysis.Results.
+func (a *Analysis) incidentOf() map[int]string {
+	ids := map[int]string{}
+	for _, incident := range a.Incidents {
+		ids[incident.rootCause] = incident.ID
+		for _, i := range incident.related {
+			ids[i] = incident.ID
+		}
+	}
+	return ids
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func ownedBy(kind, name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: kind, Name: name}}
+}
+
+func correlationClient() *kubernetes.Client {
+	return &kubernetes.Client{Client: fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-7d", Namespace: "default", OwnerReferences: ownedBy("Deployment", "web")}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-7d-a", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: ownedBy("ReplicaSet", "web-7d")}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-7d-b", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: ownedBy("ReplicaSet", "web-7d")}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "default"}},
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}, Spec: v1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}},
+					}},
+				}},
+			}}},
+		},
+	)}
+}
+
+func failure(text string, severity common.Severity) []common.Failure {
+	return []common.Failure{{Text: text, Severity: severity}}
+}
+
+func TestCorrelate(t *testing.T) {
+	a := Analysis{
+		Context: context.Background(),
+		Client:  correlationClient(),
+		Results: []common.Result{
+			{Kind: "Deployment", Name: "default/web", Error: failure("Deployment web has 0 of 2 replicas available", common.SeverityWarning)},
+			{Kind: "Ingress", Name: "default/web", Error: failure("Ingress web uses the service web which has no ready endpoints", common.SeverityWarning)},
+			{Kind: "Pod", Name: "default/batch", Error: failure("Pod batch is pending", common.SeverityWarning)},
+			{Kind: "Pod", Name: "default/web-7d-a", Error: failure("the last termination reason is OOMKilled", common.SeverityWarning)},
+			{Kind: "Pod", Name: "default/web-7d-b", Error: failure("the last termination reason is OOMKilled", common.SeverityCritical)},
+			{Kind: "Service", Name: "default/web", Error: failure("Service has no ready endpoints", common.SeverityWarning)},
+		},
+	}
+	a.Correlate()
+
+	require.Empty(t, a.Errors)
+	require.Len(t, a.Incidents, 1)
+	incident := a.Incidents[0]
+	// Both pods affect the same results, the critical one is the root cause.
+	require.Equal(t, IncidentObject{Kind: "Pod", Name: "default/web-7d-b"}, incident.RootCause)
+	require.Equal(t, a.Results[4].Fingerprint(), incident.ID)
+	require.Equal(t, []IncidentObject{
+		{Kind: "Deployment", Name: "default/web"},
+		{Kind: "Ingress", Name: "default/web"},
+		{Kind: "Pod", Name: "default/web-7d-a"},
+		{Kind: "Service", Name: "default/web"},
+	}, incident.Related)
+	require.Equal(t, map[int]bool{0: true, 1: true, 3: true, 4: true, 5: true}, a.correlatedResults())
+}
+
+func TestCorrelateReusesStore(t *testing.T) {
+	client := correlationClient()
+	a := Analysis{
+		Context: context.Background(),
+		Client:  client,
+		Results: []common.Result{
+			{Kind: "Pod", Name: "default/web-7d-a", Error: failure("the last termination reason is OOMKilled", common.SeverityCritical)},
+			{Kind: "Service", Name: "default/web", Error: failure("Service has no ready endpoints", common.SeverityWarning)},
+		},
+	}
+	// The analyzers of the run listed the pods and services already.
+	config := a.newAnalyzerConfig()
+	_, err := config.GetStore().Pods("", "")
+	require.NoError(t, err)
+	_, err = config.GetStore().Services("", "")
+	require.NoError(t, err)
+
+	a.Correlate()
+	require.Len(t, a.Incidents, 1)
+	lists := map[string]int{}
+	for _, action := range client.Client.(*fake.Clientset).Actions() {
+		lists[action.GetResource().Resource]++
+	}
+	require.Equal(t, 1, lists["pods"])
+	require.Equal(t, 1, lists["services"])
+}
+
+// countingAIClient records the prompts it is asked to complete.
+type countingAIClient struct {
+	ai.NoOpAIClient
+	mutex   sync.Mutex
+	prompts []string
+}
+
+func (c *countingAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	c.mutex.Lock()
+	c.prompts = append(c.prompts, prompt)
+	c.mutex.Unlock()
+	return "explained", nil
+}
+
+func TestGetAIResultsIncidents(t *testing.T) {
+	disabledCache := cache.New("disabled-cache")
+	disabledCache.DisableCache()
+	client := &countingAIClient{}
+
+	a := Analysis{
+		Context:        context.Background(),
+		Client:         correlationClient(),
+		AIClient:       client,
+		Cache:          disabledCache,
+		MaxConcurrency: 2,
+		Results: []common.Result{
+			{Kind: "Pod", Name: "default/web-7d-a", Error: failure("the last termination reason is OOMKilled", common.SeverityCritical)},
+			{Kind: "Service", Name: "default/web", Error: failure("Service has no ready endpoints", common.SeverityWarning)},
+			{Kind: "Pod", Name: "default/batch", Error: failure("Pod batch is pending", common.SeverityWarning)},
+		},
+	}
+	a.Correlate()
+	require.Len(t, a.Incidents, 1)
+	require.NoError(t, a.GetAIResults("json", false))
+
+	// One request for the incident and one for the unrelated pod.
+	require.Len(t, client.prompts, 2)
+	var incidentPrompt string
+	for _, prompt := range client.prompts {
+		if strings.Contains(prompt, "Root cause Pod") {
+			incidentPrompt = prompt
+		}
+	}
+	require.Contains(t, incidentPrompt, "Related Service: Service has no ready endpoints")
+	require.Equal(t, "explained", a.Incidents[0].Details)
+	id := a.Incidents[0].ID
+	// The results of the incident share its explanation.
+	require.Equal(t, "Root cause of incident "+id+".\nexplained", a.Results[0].Details)
+	require.Equal(t, "Related to Pod default/web-7d-a, the root cause of incident "+id+".\nexplained", a.Results[1].Details)
+	require.Equal(t, "explained", a.Results[2].Details)
+
+	output, err := a.PrintOutput("text")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "Root cause: Pod default/web-7d-a")
+	require.Contains(t, string(output), "Related: Service default/web")
+	require.Contains(t, string(output), "1: Pod default/batch")
+
+	// Every format keeps the explanation of the incident.
+	output, err = a.PrintOutput("markdown")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "### Incident "+id+"\n\n- **Root cause**: Pod default/web-7d-a\n- **Related**: Service default/web\n\nexplained\n")
+	output, err = a.PrintOutput("html")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "<h3>Incident "+id+"</h3>")
+	output, err = a.PrintOutput("sarif")
+	require.NoError(t, err)
+	require.Contains(t, string(output), `"incident": "`+id+`"`)
+	output, err = a.PrintOutput("junit")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "Root cause of incident "+id)
+	output, err = a.PrintOutput("mermaid")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "  %% Root cause of incident "+id+".\n  %% explained\n")
+	output, err = a.PrintOutput("dot")
+	require.NoError(t, err)
+	require.Contains(t, string(output), `tooltip="Root cause of incident `+id+`.\nexplained"`)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+)
+
+// ResultsDiff holds the findings of the current run compared to a baseline.
+// Each result carries exactly one failure.
+type ResultsDiff struct {
+	Added     []common.Result
+	Resolved  []common.Result
+	Unchanged []common.Result
+}
+
+type DiffOutput struct {
+	Provider      string                    `json:"provider"`
+	Errors        AnalysisErrors            `json:"errors"`
+	ClusterErrors map[string]AnalysisErrors `json:"clusterErrors,omitempty"`
+	Status        AnalysisStatus            `json:"status"`
+	Added         []common.Result           `json:"added"`
+	Resolved      []common.Result           `json:"resolved"`
+	Unchanged     []common.Result           `json:"unchanged"`
+	Suppressed    int                       `json:"suppressed,omitempty"`
+}
+
+// LoadBaseline reads a previous analysis written with `--output json`.
+func LoadBaseline(path string) (*JsonOutput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	var baseline JsonOutput
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// CompareBaseline matches the failures of the current run against the ones
+// in baseline by fingerprint.
+func (a *Analysis) CompareBaseline(baseline *JsonOutput) ResultsDiff {
+	current := splitFailures(a.Results)
+	previous := splitFailures(baseline.Results)
+
+	var diff ResultsDiff
+	for _, key := range sortedKeys(current) {
+		if _, ok := previous[key]; ok {
+			diff.Unchanged = append(diff.Unchanged, current[key])
+		} else {
+			diff.Added = append(diff.Added, current[key])
+		}
+	}
+	for _, key := range sortedKeys(previous) {
+		if _, ok := current[key]; !ok {
+			diff.Resolved = append(diff.Resolved, previous[key])
+		}
+	}
+	return diff
+}
+
+// HasAddedAtLeast reports whether the diff added a failure of at least the
+// given severity.
+func (d ResultsDiff) HasAddedAtLeast(severity common.Severity) bool {
+	return resultsHaveSeverity(d.Added, severity)
+}
+
+// PrintDiffOutput renders diff in the given output format.
+func (a *Analysis) PrintDiffOutput(format string, diff ResultsDiff) ([]byte, error) {
+	switch format {
+	case "json":
+		return a.diffJsonOutput(diff)
+	case "text":
+		return a.diffTextOutput(diff), nil
+	default:
+		return nil, fmt.Errorf("unsupported baseline output format: %s. Available format json,text", format)
+	}
+}
+
+func (a *Analysis) diffJsonOutput(diff ResultsDiff) ([]byte, error) {
+	status := StateOK
+	if len(diff.Added) > 0 {
+		status = StateProblemDetected
+	}
+
+	result := DiffOutput{
+		Provider:      a.AnalysisAIProvider,
+		Errors:        a.Errors,
+		ClusterErrors: a.clusterErrorsOutput(),
+		Status:        status,
+		Added:         diff.Added,
+		Resolved:      diff.Resolved,
+		Unchanged:     diff.Unchanged,
+		Suppressed:    len(a.Suppressed),
+	}
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling json: %v", err)
+	}
+	return output, nil
+}
+
+func (a *Analysis) diffTextOutput(diff ResultsDiff) []byte {
+	var output strings.Builder
+
+	if warnings := a.warnings(); len(warnings) != 0 {
+		output.WriteString(color.YellowString("Warnings : \n"))
+		for _, aerror := range warnings {
+			output.WriteString(fmt.Sprintf("- %s\n", color.YellowString(aerror)))
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(fmt.Sprintf("%s added, %s resolved, %s unchanged\n",
+		color.RedString("%d", len(diff.Added)),
+		color.GreenString("%d", len(diff.Resolved)),
+		color.YellowString("%d", len(diff.Unchanged))))
+	if len(a.Suppressed) != 0 {
+		output.WriteString(fmt.Sprintf("%s suppressed\n", color.YellowString("%d", len(a.Suppressed))))
+	}
+
+	sections := []struct {
+		title   string
+		results []common.Result
+		colorFn func(format string, a ...interface{}) string
+	}{
+		{"Added", diff.Added, color.RedString},
+		{"Resolved", diff.Resolved, color.GreenString},
+		{"Unchanged", diff.Unchanged, color.YellowString},
+	}
+	for _, section := range sections {
+		if len(section.results) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("\n%s:\n", section.colorFn(section.title)))
+		for _, result := range section.results {
+			output.WriteString(fmt.Sprintf("- %s %s(%s): %s\n",
+				color.HiYellowString(result.Kind),
+				color.YellowString(result.Name),
+				color.CyanString(result.ParentObject),
+				section.colorFn("[%s] %s", result.Error[0].Level(), result.Error[0].Text)))
+			if result.Details != "" {
+				output.WriteString(color.GreenString(result.Details + "\n"))
+			}
+		}
+	}
+	return []byte(output.String())
+}
+
+// splitFailures flattens results into one result per failure, keyed by the
+// failure fingerprint so the same failure maps to the same entry between runs.
+func splitFailures(results []common.Result) map[string]common.Result {
+	split := make(map[string]common.Result)
+	for _, result := range results {
+		for _, failure := range result.Error {
+			single := result
+			single.Error = []common.Failure{failure}
+			split[result.FailureFingerprint(failure)] = single
+		}
+	}
+	return split
+}
+
+func sortedKeys(m map[string]common.Result) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
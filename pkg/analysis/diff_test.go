@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFailures(t *testing.T) {
+	results := []common.Result{
+		{
+			Kind: "Pod",
+			Name: "default/example",
+			Error: []common.Failure{
+				{Text: "first"},
+				{Text: "second"},
+			},
+		},
+	}
+
+	split := splitFailures(results)
+	require.Len(t, split, 2)
+	for _, result := range split {
+		require.Len(t, result.Error, 1)
+		require.Equal(t, "default/example", result.Name)
+	}
+}
+
+func TestCompareBaseline(t *testing.T) {
+	baseline := JsonOutput{
+		Results: []common.Result{
+			{
+				Kind: "Deployment",
+				Name: "default/web",
+				Error: []common.Failure{
+					{Text: "Deployment default/web has 1 replicas but 0 are available"},
+				},
+			},
+			{
+				Kind: "CronJob",
+				Name: "default/backup",
+				Error: []common.Failure{
+					{Text: "CronJob backup is suspended"},
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(baseline)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	loaded, err := LoadBaseline(path)
+	require.NoError(t, err)
+
+	a := Analysis{
+		Results: []common.Result{
+			{
+				Kind: "Deployment",
+				Name: "default/web",
+				Error: []common.Failure{
+					// Only the counters changed, so this is the same finding.
+					{Text: "Deployment default/web has 3 replicas but 2 are available"},
+				},
+			},
+			{
+				Kind: "Pod",
+				Name: "default/web-1",
+				Error: []common.Failure{
+					{Text: "Back-off pulling image"},
+				},
+			},
+		},
+	}
+
+	diff := a.CompareBaseline(loaded)
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, "default/web-1", diff.Added[0].Name)
+	require.Len(t, diff.Resolved, 1)
+	require.Equal(t, "default/backup", diff.Resolved[0].Name)
+	require.Len(t, diff.Unchanged, 1)
+	require.Equal(t, "default/web", diff.Unchanged[0].Name)
+
+	output, err := a.PrintDiffOutput("json", diff)
+	require.NoError(t, err)
+	got := DiffOutput{}
+	require.NoError(t, json.Unmarshal(output, &got))
+	require.Equal(t, StateProblemDetected, got.Status)
+
+	_, err = a.PrintDiffOutput("unsupported", diff)
+	require.ErrorContains(t, err, "unsupported baseline output format")
+}
+
+func TestLoadBaselineMissingFile(t *testing.T) {
+	_, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "reading baseline")
+}
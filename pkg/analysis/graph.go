@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gtwapi "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Relations between objects, read from the dependent object to the object it
+// depends on, e.g. a Deployment owns a ReplicaSet.
+const (
+	relationOwns     = "owns"
+	relationSelects  = "selects"
+	relationRoutesTo = "routes to"
+	relationScales   = "scales"
+	relationMounts   = "mounts"
+	relationUses     = "uses"
+)
+
+var (
+	scaledObjectResource = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+	httpRouteResource    = gtwapi.SchemeGroupVersion.WithResource("httproutes")
+)
+
+// objectEdge links an object to an object it affects when it fails.
+type objectEdge struct {
+	to       string
+	relation string
+}
+
+// objectGraph links objects, keyed by objectKey, to the objects they affect
+// when they fail.
+type objectGraph map[string][]objectEdge
+
+func objectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// resultKey is the objectKey of the object of result. Results of
+// cluster-scoped objects have no namespace in their name.
+func resultKey(result common.Result) string {
+	if namespace, name, ok := strings.Cut(result.Name, "/"); ok {
+		return objectKey(result.Kind, namespace, name)
+	}
+	return objectKey(result.Kind, "", result.Name)
+}
+
+// splitObjectKey returns the kind, namespace and name of key.
+func splitObjectKey(key string) (string, string, string) {
+	kind, rest, _ := strings.Cut(key, "/")
+	namespace, name, _ := strings.Cut(rest, "/")
+	return kind, namespace, name
+}
+
+// add records that cause affects effect, which relates to cause as relation.
+func (g objectGraph) add(cause, effect, relation string) {
+	g[cause] = append(g[cause], objectEdge{to: effect, relation: relation})
+}
+
+func (a *Analysis) clusterClient(cluster string) *kubernetes.Client {
+	if cluster == "" {
+		return a.Client
+	}
+	for _, c := range a.Clusters {
+		if c.Name == cluster {
+			return c.Client
+		}
+	}
+	return nil
+}
+
+// clusterStore returns the store of the run in cluster, creating it on first
+// use. It is not safe for concurrent use; analyzers share the store through
+// common.Analyzer.
+func (a *Analysis) clusterStore(cluster string) *kubernetes.Store {
+	if store, ok := a.stores[cluster]; ok {
+		return store
+	}
+	store := kubernetes.NewStore(a.Context, a.clusterClient(cluster), a.Namespace)
+	if a.stores == nil {
+		a.stores = map[string]*kubernetes.Store{}
+	}
+	a.stores[cluster] = store
+	return store
+}
+
+// clusterGraph returns the graph of the namespaces with results in cluster,
+// building it on first use. It reports false if the cluster has no client.
+func (a *Analysis) clusterGraph(cluster string) (objectGraph, bool) {
+	if graph, ok := a.graphs[cluster]; ok {
+		return graph, true
+	}
+	client := a.clusterClient(cluster)
+	if client == nil || client.GetClient() == nil {
+		return nil, false
+	}
+
+	namespaces := map[string]bool{}
+	for _, result := range a.Results {
+		if result.Cluster != cluster {
+			continue
+		}
+		if namespace, _, ok := strings.Cut(result.Name, "/"); ok {
+			namespaces[namespace] = true
+		}
+	}
+	graph := objectGraph{}
+	for _, namespace := range sortedMapKeys(namespaces) {
+		for _, err := range buildObjectGraph(a.clusterStore(cluster), namespace, graph) {
+			a.Errors = append(a.Errors, fmt.Sprintf("[ObjectGraph] %s", err))
+		}
+	}
+	if a.graphs == nil {
+		a.graphs = map[string]objectGraph{}
+	}
+	a.graphs[cluster] = graph
+	return graph, true
+}
+
+// buildObjectGraph adds the objects of namespace to graph: owner chains,
+// Service selectors, Ingress and HTTPRoute backends, HorizontalPodAutoscaler
+// and KEDA ScaledObject targets, and the claims and storage classes of
+// volumes. The objects are read from store, so the lists of the analyzers are
+// reused. Resources that cannot be listed are skipped and returned as errors;
+// optional APIs that are not installed are skipped silently.
+func buildObjectGraph(store *kubernetes.Store, namespace string, graph objectGraph) []error {
+	var errs []error
+	addOwners := func(kind string, meta metav1.ObjectMeta) {
+		for _, owner := range meta.OwnerReferences {
+			graph.add(objectKey(kind, meta.Namespace, meta.Name), objectKey(owner.Kind, meta.Namespace, owner.Name), relationOwns)
+		}
+	}
+
+	pods, err := store.Pods(namespace, "")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("listing pods in %s: %w", namespace, err))
+	} else {
+		for _, pod := range pods {
+			podKey := objectKey("Pod", namespace, pod.Name)
+			addOwners("Pod", pod.ObjectMeta)
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					graph.add(objectKey("PersistentVolumeClaim", namespace, volume.PersistentVolumeClaim.ClaimName), podKey, relationMounts)
+				}
+			}
+		}
+	}
+	if list, err := store.ReplicaSets(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing replicasets in %s: %w", namespace, err))
+	} else {
+		for _, item := range list {
+			addOwners("ReplicaSet", item.ObjectMeta)
+		}
+	}
+	if list, err := store.Deployments(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing deployments in %s: %w", namespace, err))
+	} else {
+		for _, item := range list {
+			addOwners("Deployment", item.ObjectMeta)
+		}
+	}
+	if list, err := store.StatefulSets(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing statefulsets in %s: %w", namespace, err))
+	} else {
+		for _, item := range list {
+			addOwners("StatefulSet", item.ObjectMeta)
+		}
+	}
+	if list, err := store.Jobs(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing jobs in %s: %w", namespace, err))
+	} else {
+		for _, item := range list {
+			addOwners("Job", item.ObjectMeta)
+		}
+	}
+
+	// Services are affected by the pods they select.
+	if services, err := store.Services(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing services in %s: %w", namespace, err))
+	} else if pods != nil {
+		for _, service := range services {
+			if len(service.Spec.Selector) == 0 {
+				continue
+			}
+			selector := labels.SelectorFromSet(service.Spec.Selector)
+			for _, pod := range pods {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					graph.add(objectKey("Pod", namespace, pod.Name), objectKey("Service", namespace, service.Name), relationSelects)
+				}
+			}
+		}
+	}
+
+	// Ingresses and HTTPRoutes are affected by their backend services.
+	if ingresses, err := store.Ingresses(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing ingresses in %s: %w", namespace, err))
+	} else {
+		for _, ingress := range ingresses {
+			ingressKey := objectKey("Ingress", namespace, ingress.Name)
+			if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+				graph.add(objectKey("Service", namespace, backend.Service.Name), ingressKey, relationRoutesTo)
+			}
+			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+				for _, path := range rule.HTTP.Paths {
+					if path.Backend.Service != nil {
+						graph.add(objectKey("Service", namespace, path.Backend.Service.Name), ingressKey, relationRoutesTo)
+					}
+				}
+			}
+		}
+	}
+	if list, err := store.Objects(httpRouteResource, namespace, ""); err == nil {
+		for _, item := range list {
+			var route gtwapi.HTTPRoute
+			if runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &route) != nil {
+				continue
+			}
+			routeKey := objectKey("HTTPRoute", namespace, route.Name)
+			for _, rule := range route.Spec.Rules {
+				for _, backend := range rule.BackendRefs {
+					if backend.Kind != nil && *backend.Kind != "Service" {
+						continue
+					}
+					serviceNamespace := namespace
+					if backend.Namespace != nil {
+						serviceNamespace = string(*backend.Namespace)
+					}
+					graph.add(objectKey("Service", serviceNamespace, string(backend.Name)), routeKey, relationRoutesTo)
+				}
+			}
+		}
+	}
+
+	// Autoscalers are affected by the workloads they scale.
+	if hpas, err := store.HorizontalPodAutoscalers(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing horizontalpodautoscalers in %s: %w", namespace, err))
+	} else {
+		for _, hpa := range hpas {
+			target := hpa.Spec.ScaleTargetRef
+			graph.add(objectKey(target.Kind, namespace, target.Name), objectKey("HorizontalPodAutoscaler", namespace, hpa.Name), relationScales)
+		}
+	}
+	if list, err := store.Objects(scaledObjectResource, namespace, ""); err == nil {
+		for _, item := range list {
+			name, _, _ := unstructured.NestedString(item.Object, "spec", "scaleTargetRef", "name")
+			kind, _, _ := unstructured.NestedString(item.Object, "spec", "scaleTargetRef", "kind")
+			if kind == "" {
+				kind = "Deployment"
+			}
+			if name != "" {
+				graph.add(objectKey(kind, namespace, name), objectKey("ScaledObject", namespace, item.GetName()), relationScales)
+			}
+		}
+	}
+
+	// Claims are affected by their storage class.
+	if pvcs, err := store.PersistentVolumeClaims(namespace, ""); err != nil {
+		errs = append(errs, fmt.Errorf("listing persistentvolumeclaims in %s: %w", namespace, err))
+	} else {
+		for _, pvc := range pvcs {
+			if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+				graph.add(objectKey("StorageClass", "", *pvc.Spec.StorageClassName), objectKey("PersistentVolumeClaim", namespace, pvc.Name), relationUses)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// analysisErrorsSuite is the test suite that collects errors raised while
+// running the analyzers, as opposed to problems found in the cluster.
+const analysisErrorsSuite = "k8sgpt"
+
+func (a *Analysis) junitOutput() ([]byte, error) {
+	suites := map[string]*junitTestSuite{}
+	for _, result := range a.Results {
+		suite, ok := suites[result.Kind]
+		if !ok {
+			suite = &junitTestSuite{Name: result.Kind}
+			suites[result.Kind] = suite
+		}
+
+		for _, failure := range result.Error {
+			text := failure.Text
+			if failure.KubernetesDoc != "" {
+				text = fmt.Sprintf("%s\nKubernetes Doc: %s", text, failure.KubernetesDoc)
+			}
+			name := result.Name
+			if result.ParentObject != "" {
+				name = fmt.Sprintf("%s (%s)", result.Name, result.ParentObject)
+			}
+			if result.Cluster != "" {
+				name = fmt.Sprintf("[%s] %s", result.Cluster, name)
+			}
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				ClassName: result.Kind,
+				Name:      name,
+				Failure: &junitFailure{
+					Message: failure.Text,
+					Type:    string(failure.Level()),
+					Text:    text,
+				},
+				SystemOut: strings.TrimSpace(result.Details),
+			})
+			suite.Tests++
+			suite.Failures++
+		}
+	}
+
+	if warnings := a.warnings(); len(warnings) > 0 {
+		suite := &junitTestSuite{Name: analysisErrorsSuite}
+		for _, aerror := range warnings {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				ClassName: analysisErrorsSuite,
+				Name:      aerror,
+				Error: &junitFailure{
+					Message: aerror,
+					Type:    "AnalysisError",
+					Text:    aerror,
+				},
+			})
+			suite.Tests++
+			suite.Errors++
+		}
+		suites[analysisErrorsSuite] = suite
+	}
+
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	root := junitTestSuites{Name: "k8sgpt"}
+	for _, name := range names {
+		suite := suites[name]
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.TestSuites = append(root.TestSuites, *suite)
+	}
+
+	output, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling junit: %v", err)
+	}
+	return append([]byte(xml.Header), output...), nil
+}
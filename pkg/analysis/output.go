@@ -6,11 +6,18 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 )
 
 var outputFormats = map[string]func(*Analysis) ([]byte, error){
-	"json": (*Analysis).jsonOutput,
-	"text": (*Analysis).textOutput,
+	"json":     (*Analysis).jsonOutput,
+	"text":     (*Analysis).textOutput,
+	"sarif":    (*Analysis).sarifOutput,
+	"junit":    (*Analysis).junitOutput,
+	"markdown": (*Analysis).markdownOutput,
+	"html":     (*Analysis).htmlOutput,
+	"mermaid":  (*Analysis).mermaidOutput,
+	"dot":      (*Analysis).dotOutput,
 }
 
 func getOutputFormats() []string {
@@ -42,11 +49,14 @@ func (a *Analysis) jsonOutput() ([]byte, error) {
 	}
 
 	result := JsonOutput{
-		Provider: a.AnalysisAIProvider,
-		Problems: problems,
-		Results:  a.Results,
-		Errors:   a.Errors,
-		Status:   status,
+		Provider:      a.AnalysisAIProvider,
+		Problems:      problems,
+		Results:       a.Results,
+		Errors:        a.Errors,
+		ClusterErrors: a.clusterErrorsOutput(),
+		Status:        status,
+		Incidents:     a.Incidents,
+		Suppressed:    len(a.Suppressed),
 	}
 	output, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -69,6 +79,50 @@ func (a *Analysis) PrintStats() []byte {
 
 func (a *Analysis) textOutput() ([]byte, error) {
 	var output strings.Builder
+	output.WriteString(a.textHeader())
+
+	if len(a.Results) == 0 {
+		output.WriteString(color.GreenString("No problems detected\n"))
+		return []byte(output.String()), nil
+	}
+	n := 0
+	for _, incident := range a.Incidents {
+		output.WriteString(a.textIncident(n, incident))
+		n++
+	}
+	correlated := a.correlatedResults()
+	for index, result := range a.Results {
+		if correlated[index] {
+			continue
+		}
+		output.WriteString(textResult(n, result))
+		output.WriteString(color.GreenString(result.Details + "\n"))
+		output.WriteString(a.fallbackNote(result))
+		n++
+	}
+	return []byte(output.String()), nil
+}
+
+// textIncident renders an incident: its root cause, the related results and
+// the explanation of the incident.
+func (a *Analysis) textIncident(n int, incident Incident) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s: %s %s\n", color.CyanString("%d", n),
+		color.HiMagentaString("Incident"), color.MagentaString(incident.ID)))
+	output.WriteString(color.HiMagentaString("Root cause: "))
+	output.WriteString(textResultBody(a.Results[incident.rootCause]))
+	for _, i := range incident.related {
+		output.WriteString(color.MagentaString("Related: "))
+		output.WriteString(textResultBody(a.Results[i]))
+	}
+	output.WriteString(color.GreenString(incident.Details + "\n"))
+	output.WriteString(a.fallbackNote(common.Result{Backend: incident.Backend}))
+	return output.String()
+}
+
+// textHeader renders the AI provider and warnings shown above the results.
+func (a *Analysis) textHeader() string {
+	var output strings.Builder
 
 	// Print the AI provider used for this analysis (if explain was enabled).
 	if a.Explain {
@@ -76,31 +130,53 @@ func (a *Analysis) textOutput() ([]byte, error) {
 	} else {
 		output.WriteString(fmt.Sprintf("AI Provider: %s\n", color.YellowString("AI not used; --explain not set")))
 	}
+	if len(a.Suppressed) != 0 {
+		output.WriteString(fmt.Sprintf("Suppressed: %s\n", color.YellowString("%d findings", len(a.Suppressed))))
+	}
 
-	if len(a.Errors) != 0 {
+	if warnings := a.warnings(); len(warnings) != 0 {
 		output.WriteString("\n")
 		output.WriteString(color.YellowString("Warnings : \n"))
-		for _, aerror := range a.Errors {
+		for _, aerror := range warnings {
 			output.WriteString(fmt.Sprintf("- %s\n", color.YellowString(aerror)))
 		}
 	}
 	output.WriteString("\n")
-	if len(a.Results) == 0 {
-		output.WriteString(color.GreenString("No problems detected\n"))
-		return []byte(output.String()), nil
+	return output.String()
+}
+
+// textResult renders a result without its details.
+func textResult(n int, result common.Result) string {
+	return fmt.Sprintf("%s: %s", color.CyanString("%d", n), textResultBody(result))
+}
+
+// textResultBody renders the object and failures of a result.
+func textResultBody(result common.Result) string {
+	var output strings.Builder
+	var cluster string
+	if result.Cluster != "" {
+		cluster = color.MagentaString("[%s] ", result.Cluster)
 	}
-	for n, result := range a.Results {
-		output.WriteString(fmt.Sprintf("%s: %s %s(%s)\n", color.CyanString("%d", n),
-			color.HiYellowString(result.Kind),
-			color.YellowString(result.Name),
-			color.CyanString(result.ParentObject)))
-		for _, err := range result.Error {
-			output.WriteString(fmt.Sprintf("- %s %s\n", color.RedString("Error:"), color.RedString(err.Text)))
-			if err.KubernetesDoc != "" {
-				output.WriteString(fmt.Sprintf("  %s %s\n", color.RedString("Kubernetes Doc:"), color.RedString(err.KubernetesDoc)))
-			}
+	output.WriteString(fmt.Sprintf("%s%s %s(%s)\n", cluster,
+		color.HiYellowString(result.Kind),
+		color.YellowString(result.Name),
+		color.CyanString(result.ParentObject)))
+	for _, err := range result.Error {
+		output.WriteString(fmt.Sprintf("- %s %s %s\n", color.RedString("Error:"), severityString(err.Level()), color.RedString(err.Text)))
+		if err.KubernetesDoc != "" {
+			output.WriteString(fmt.Sprintf("  %s %s\n", color.RedString("Kubernetes Doc:"), color.RedString(err.KubernetesDoc)))
 		}
-		output.WriteString(color.GreenString(result.Details + "\n"))
 	}
-	return []byte(output.String()), nil
+	return output.String()
+}
+
+func severityString(severity common.Severity) string {
+	switch severity {
+	case common.SeverityCritical:
+		return color.HiRedString("[%s]", severity)
+	case common.SeverityWarning:
+		return color.YellowString("[%s]", severity)
+	default:
+		return color.CyanString("[%s]", severity)
+	}
 }
@@ -14,8 +14,11 @@ limitations under the License.
 package analysis
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"testing"
 
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,6 +44,30 @@ func TestPrintOutput(t *testing.T) {
 			format:         "text",
 			expectedOutput: "AI Provider: AI not used; --explain not set\n\nNo problems detected\n",
 		},
+		{
+			name:           "sarif format",
+			a:              &Analysis{},
+			format:         "sarif",
+			expectedOutput: "\"version\": \"2.1.0\"",
+		},
+		{
+			name:           "junit format",
+			a:              &Analysis{},
+			format:         "junit",
+			expectedOutput: "<testsuites name=\"k8sgpt\" tests=\"0\" failures=\"0\" errors=\"0\"></testsuites>",
+		},
+		{
+			name:           "markdown format",
+			a:              &Analysis{},
+			format:         "markdown",
+			expectedOutput: "# K8sGPT Analysis Report\n\nAI Provider: AI not used; --explain not set\n\nNo problems detected\n",
+		},
+		{
+			name:           "html format",
+			a:              &Analysis{},
+			format:         "html",
+			expectedOutput: "<p>No problems detected</p>",
+		},
 		{
 			name:        "unsupported format",
 			a:           &Analysis{},
@@ -62,3 +89,86 @@ func TestPrintOutput(t *testing.T) {
 		})
 	}
 }
+
+func problemAnalysis() *Analysis {
+	return &Analysis{
+		Results: []common.Result{
+			{
+				Kind: "Pod",
+				Name: "default/web-1",
+				Error: []common.Failure{
+					{Text: "Back-off pulling image", Severity: common.SeverityCritical},
+					{Text: "readiness probe failed", Severity: common.SeverityWarning},
+				},
+				Details:      "test-solution",
+				ParentObject: "Deployment/web",
+			},
+			{
+				Kind:  "CronJob",
+				Name:  "default/backup",
+				Error: []common.Failure{{Text: "CronJob backup is suspended", Severity: common.SeverityInfo}},
+			},
+		},
+		Errors: []string{"[Service] test-error"},
+	}
+}
+
+func TestSarifOutput(t *testing.T) {
+	output, err := problemAnalysis().PrintOutput("sarif")
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(output, &log))
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	require.Equal(t, []string{"CronJob", "Pod"}, []string{run.Tool.Driver.Rules[0].ID, run.Tool.Driver.Rules[1].ID})
+	require.Len(t, run.Results, 3)
+	require.Equal(t, "error", run.Results[0].Level)
+	require.Equal(t, "warning", run.Results[1].Level)
+	require.Equal(t, "note", run.Results[2].Level)
+	require.Equal(t, "Pod/default/web-1", run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	require.Equal(t, "default/Pod/web-1", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, 1, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	require.Equal(t, "cluster-scoped/Node/node-1", sarifArtifactURI(common.Result{Kind: "Node", Name: "node-1"}))
+	require.Equal(t, "prod/default/Pod/web-1", sarifArtifactURI(common.Result{Kind: "Pod", Name: "default/web-1", Cluster: "prod"}))
+	require.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+}
+
+func TestJunitOutput(t *testing.T) {
+	output, err := problemAnalysis().PrintOutput("junit")
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(output, &suites))
+	require.Equal(t, 4, suites.Tests)
+	require.Equal(t, 3, suites.Failures)
+	require.Equal(t, 1, suites.Errors)
+	require.Len(t, suites.TestSuites, 3)
+	require.Equal(t, "CronJob", suites.TestSuites[0].Name)
+	require.Equal(t, "Pod", suites.TestSuites[1].Name)
+	require.Equal(t, "critical", suites.TestSuites[1].TestCases[0].Failure.Type)
+	require.Equal(t, "test-solution", suites.TestSuites[1].TestCases[0].SystemOut)
+}
+
+func TestMarkdownOutput(t *testing.T) {
+	a := problemAnalysis()
+	a.Results[0].Error[0].KubernetesDoc = "test-doc"
+	output, err := a.PrintOutput("markdown")
+	require.NoError(t, err)
+
+	require.Contains(t, string(output), "| default | CronJob | 1 |\n| default | Pod | 2 |\n| **Total** | | **3** |")
+	require.Contains(t, string(output), "#### web-1 (Deployment/web)\n\n- **critical**: Back-off pulling image\n  - Kubernetes Doc: test-doc\n")
+	require.Contains(t, string(output), "test-solution")
+	require.Contains(t, string(output), "- [Service] test-error")
+}
+
+func TestHtmlOutput(t *testing.T) {
+	a := problemAnalysis()
+	a.Results[0].Details = "<script>alert(1)</script>"
+	output, err := a.PrintOutput("html")
+	require.NoError(t, err)
+
+	require.Contains(t, string(output), "<tr><td>default</td><td>Pod</td><td>2</td></tr>")
+	require.Contains(t, string(output), "<h2>Namespace: default</h2>")
+	require.NotContains(t, string(output), "<script>")
+}
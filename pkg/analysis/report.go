@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+)
+
+// clusterScope is the namespace heading used for cluster-scoped objects.
+const clusterScope = "(cluster-scoped)"
+
+type reportNamespace struct {
+	Name     string
+	Problems int
+	Kinds    []reportKind
+}
+
+type reportKind struct {
+	Kind     string
+	Problems int
+	Results  []reportResult
+}
+
+type reportResult struct {
+	Name         string
+	ParentObject string
+	Failures     []common.Failure
+	Details      string
+}
+
+type reportIncident struct {
+	ID        string
+	RootCause string
+	Related   []string
+	Details   string
+}
+
+type report struct {
+	Provider   string
+	Errors     []string
+	Problems   int
+	Suppressed int
+	Incidents  []reportIncident
+	Namespaces []reportNamespace
+}
+
+// newReport groups the results by namespace and kind, sorted by name.
+func (a *Analysis) newReport() report {
+	r := report{Errors: a.warnings(), Suppressed: len(a.Suppressed)}
+	if a.Explain {
+		r.Provider = a.AnalysisAIProvider
+	} else {
+		r.Provider = "AI not used; --explain not set"
+	}
+	for _, incident := range a.Incidents {
+		ri := reportIncident{
+			ID:        incident.ID,
+			RootCause: fmt.Sprintf("%s %s", incident.RootCause.Kind, incident.RootCause.Name),
+			Details:   strings.TrimSpace(incident.Details),
+		}
+		for _, related := range incident.Related {
+			ri.Related = append(ri.Related, fmt.Sprintf("%s %s", related.Kind, related.Name))
+		}
+		r.Incidents = append(r.Incidents, ri)
+	}
+
+	grouped := map[string]map[string][]reportResult{}
+	for _, result := range a.Results {
+		namespace, name := clusterScope, result.Name
+		if parts := strings.SplitN(result.Name, "/", 2); len(parts) == 2 {
+			namespace, name = parts[0], parts[1]
+		}
+		if result.Cluster != "" {
+			namespace = fmt.Sprintf("%s/%s", result.Cluster, namespace)
+		}
+		if grouped[namespace] == nil {
+			grouped[namespace] = map[string][]reportResult{}
+		}
+		grouped[namespace][result.Kind] = append(grouped[namespace][result.Kind], reportResult{
+			Name:         name,
+			ParentObject: result.ParentObject,
+			Failures:     result.Error,
+			Details:      strings.TrimSpace(result.Details),
+		})
+	}
+
+	for _, namespace := range sortedMapKeys(grouped) {
+		ns := reportNamespace{Name: namespace}
+		for _, kind := range sortedMapKeys(grouped[namespace]) {
+			results := grouped[namespace][kind]
+			sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+			k := reportKind{Kind: kind, Results: results}
+			for _, result := range results {
+				k.Problems += len(result.Failures)
+			}
+			ns.Problems += k.Problems
+			ns.Kinds = append(ns.Kinds, k)
+		}
+		r.Problems += ns.Problems
+		r.Namespaces = append(r.Namespaces, ns)
+	}
+	return r
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var markdownTableEscaper = strings.NewReplacer("|", "\\|", "\n", " ")
+
+func (a *Analysis) markdownOutput() ([]byte, error) {
+	r := a.newReport()
+	var output strings.Builder
+
+	output.WriteString("# K8sGPT Analysis Report\n\n")
+	output.WriteString(fmt.Sprintf("AI Provider: %s\n\n", r.Provider))
+	if r.Suppressed != 0 {
+		output.WriteString(fmt.Sprintf("Suppressed: %d findings\n\n", r.Suppressed))
+	}
+
+	if len(r.Errors) != 0 {
+		output.WriteString("## Warnings\n\n")
+		for _, aerror := range r.Errors {
+			output.WriteString(fmt.Sprintf("- %s\n", aerror))
+		}
+		output.WriteString("\n")
+	}
+
+	if r.Problems == 0 {
+		output.WriteString("No problems detected\n")
+		return []byte(output.String()), nil
+	}
+
+	output.WriteString("## Summary\n\n")
+	output.WriteString("| Namespace | Kind | Problems |\n")
+	output.WriteString("|-----------|------|----------|\n")
+	for _, ns := range r.Namespaces {
+		for _, kind := range ns.Kinds {
+			output.WriteString(fmt.Sprintf("| %s | %s | %d |\n",
+				markdownTableEscaper.Replace(ns.Name), markdownTableEscaper.Replace(kind.Kind), kind.Problems))
+		}
+	}
+	output.WriteString(fmt.Sprintf("| **Total** | | **%d** |\n", r.Problems))
+
+	if len(r.Incidents) != 0 {
+		output.WriteString("\n## Incidents\n")
+		for _, incident := range r.Incidents {
+			output.WriteString(fmt.Sprintf("\n### Incident %s\n\n", incident.ID))
+			output.WriteString(fmt.Sprintf("- **Root cause**: %s\n", incident.RootCause))
+			for _, related := range incident.Related {
+				output.WriteString(fmt.Sprintf("- **Related**: %s\n", related))
+			}
+			if incident.Details != "" {
+				output.WriteString(fmt.Sprintf("\n%s\n", incident.Details))
+			}
+		}
+	}
+
+	for _, ns := range r.Namespaces {
+		output.WriteString(fmt.Sprintf("\n## Namespace: %s\n", ns.Name))
+		for _, kind := range ns.Kinds {
+			output.WriteString(fmt.Sprintf("\n### %s\n", kind.Kind))
+			for _, result := range kind.Results {
+				output.WriteString(fmt.Sprintf("\n#### %s", result.Name))
+				if result.ParentObject != "" {
+					output.WriteString(fmt.Sprintf(" (%s)", result.ParentObject))
+				}
+				output.WriteString("\n\n")
+				for _, failure := range result.Failures {
+					output.WriteString(fmt.Sprintf("- **%s**: %s\n", failure.Level(), failure.Text))
+					if failure.KubernetesDoc != "" {
+						output.WriteString(fmt.Sprintf("  - Kubernetes Doc: %s\n", strings.ReplaceAll(failure.KubernetesDoc, "\n", " ")))
+					}
+				}
+				if result.Details != "" {
+					output.WriteString(fmt.Sprintf("\n%s\n", result.Details))
+				}
+			}
+		}
+	}
+	return []byte(output.String()), nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>K8sGPT Analysis Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.critical { color: #b00020; }
+.warning { color: #b36b00; }
+.info { color: #005a9c; }
+.details { white-space: pre-wrap; background: #f6f8fa; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>K8sGPT Analysis Report</h1>
+<p>AI Provider: {{ .Provider }}</p>
+{{- if .Suppressed }}
+<p>Suppressed: {{ .Suppressed }} findings</p>
+{{- end }}
+{{- if .Errors }}
+<h2>Warnings</h2>
+<ul>
+{{- range .Errors }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if eq .Problems 0 }}
+<p>No problems detected</p>
+{{- else }}
+<h2>Summary</h2>
+<table>
+<tr><th>Namespace</th><th>Kind</th><th>Problems</th></tr>
+{{- range $ns := .Namespaces }}
+{{- range .Kinds }}
+<tr><td>{{ $ns.Name }}</td><td>{{ .Kind }}</td><td>{{ .Problems }}</td></tr>
+{{- end }}
+{{- end }}
+<tr><th>Total</th><th></th><th>{{ .Problems }}</th></tr>
+</table>
+{{- if .Incidents }}
+<h2>Incidents</h2>
+{{- range .Incidents }}
+<h3>Incident {{ .ID }}</h3>
+<ul>
+<li><strong>Root cause</strong>: {{ .RootCause }}</li>
+{{- range .Related }}
+<li><strong>Related</strong>: {{ . }}</li>
+{{- end }}
+</ul>
+{{- if .Details }}
+<div class="details">{{ .Details }}</div>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- range .Namespaces }}
+<h2>Namespace: {{ .Name }}</h2>
+{{- range .Kinds }}
+<h3>{{ .Kind }}</h3>
+{{- range .Results }}
+<h4>{{ .Name }}{{ if .ParentObject }} ({{ .ParentObject }}){{ end }}</h4>
+<ul>
+{{- range .Failures }}
+<li><strong class="{{ .Level }}">{{ .Level }}</strong>: {{ .Text }}{{ if .KubernetesDoc }}<br>Kubernetes Doc: {{ .KubernetesDoc }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- if .Details }}
+<div class="details">{{ .Details }}</div>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+func (a *Analysis) htmlOutput() ([]byte, error) {
+	var output bytes.Buffer
+	if err := htmlReportTemplate.Execute(&output, a.newReport()); err != nil {
+		return nil, fmt.Errorf("error rendering html: %v", err)
+	}
+	return output.Bytes(), nil
+}
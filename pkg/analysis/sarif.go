@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// The types below cover the subset of SARIF 2.1.0 that k8sgpt produces.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+// sarifPhysicalLocation points at a synthetic artifact per object, as code
+// scanning services such as GitHub reject results without a physical location.
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifArtifactBase is the uriBaseId of the synthetic artifact URIs.
+const sarifArtifactBase = "KUBERNETES"
+
+// sarifArtifactURI returns the synthetic artifact of a result, in the form
+// [cluster/]namespace/kind/name. Cluster scoped objects use the
+// "cluster-scoped" namespace.
+func sarifArtifactURI(result common.Result) string {
+	namespace, name, found := strings.Cut(result.Name, "/")
+	if !found {
+		namespace, name = "cluster-scoped", result.Name
+	}
+	return path.Join(result.Cluster, namespace, result.Kind, name)
+}
+
+var sarifLevels = map[common.Severity]string{
+	common.SeverityInfo:     "note",
+	common.SeverityWarning:  "warning",
+	common.SeverityCritical: "error",
+}
+
+func (a *Analysis) sarifOutput() ([]byte, error) {
+	rules := map[string]sarifRule{}
+	results := []sarifResult{}
+	incidents := a.incidentOf()
+	for index, result := range a.Results {
+		if _, ok := rules[result.Kind]; !ok {
+			rules[result.Kind] = sarifRule{
+				ID:               result.Kind,
+				Name:             result.Kind,
+				ShortDescription: sarifMessage{Text: fmt.Sprintf("%s analyzer", result.Kind)},
+			}
+		}
+
+		qualifiedName := fmt.Sprintf("%s/%s", result.Kind, result.Name)
+		if result.Cluster != "" {
+			qualifiedName = fmt.Sprintf("%s/%s", result.Cluster, qualifiedName)
+		}
+		for _, failure := range result.Error {
+			sr := sarifResult{
+				RuleID:  result.Kind,
+				Level:   sarifLevels[failure.Level()],
+				Message: sarifMessage{Text: failure.Text},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: sarifArtifactURI(result), URIBaseID: sarifArtifactBase},
+						Region:           sarifRegion{StartLine: 1},
+					},
+					LogicalLocations: []sarifLogicalLocation{{
+						Name:               result.Name,
+						FullyQualifiedName: qualifiedName,
+						Kind:               "resource",
+					}},
+				}},
+				PartialFingerprints: map[string]string{
+					"k8sgpt/v1": result.FailureFingerprint(failure),
+				},
+			}
+			properties := map[string]string{}
+			if result.ParentObject != "" {
+				properties["parentObject"] = result.ParentObject
+			}
+			if result.Details != "" {
+				properties["details"] = result.Details
+			}
+			if failure.KubernetesDoc != "" {
+				properties["kubernetesDoc"] = failure.KubernetesDoc
+			}
+			if id, ok := incidents[index]; ok {
+				properties["incident"] = id
+			}
+			if len(properties) > 0 {
+				sr.Properties = properties
+			}
+			results = append(results, sr)
+		}
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for id := range rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	driver := sarifDriver{
+		Name:           "k8sgpt",
+		InformationURI: "https://k8sgpt.ai",
+		Rules:          []sarifRule{},
+	}
+	for _, id := range ruleIDs {
+		driver.Rules = append(driver.Rules, rules[id])
+	}
+
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	for _, aerror := range a.warnings() {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+			Level:   "warning",
+			Message: sarifMessage{Text: aerror},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:        sarifTool{Driver: driver},
+			Invocations: []sarifInvocation{invocation},
+			Results:     results,
+		}},
+	}
+	output, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling sarif: %v", err)
+	}
+	return output, nil
+}
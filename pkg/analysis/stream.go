@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+)
+
+// unmaskingWriter writes a streamed explanation, replacing the masked values
+// sent to the AI backend with the original ones. A masked value can be split
+// across chunks, so text that may be the start of one is held back until the
+// next chunk or Flush.
+type unmaskingWriter struct {
+	w         io.Writer
+	sensitive []common.Sensitive
+	pending   string
+}
+
+func newUnmaskingWriter(w io.Writer, failures []common.Failure, anonymize bool) *unmaskingWriter {
+	u := &unmaskingWriter{w: w}
+	if anonymize {
+		for _, failure := range failures {
+			for _, s := range failure.Sensitive {
+				if s.Masked != "" {
+					u.sensitive = append(u.sensitive, s)
+				}
+			}
+		}
+	}
+	return u
+}
+
+func (u *unmaskingWriter) Write(chunk string) {
+	u.pending += chunk
+	for _, s := range u.sensitive {
+		u.pending = strings.ReplaceAll(u.pending, s.Masked, s.Unmasked)
+	}
+
+	hold := 0
+	for _, s := range u.sensitive {
+		for n := min(len(s.Masked)-1, len(u.pending)); n > hold; n-- {
+			if strings.HasSuffix(u.pending, s.Masked[:n]) {
+				hold = n
+				break
+			}
+		}
+	}
+	u.write(u.pending[:len(u.pending)-hold])
+	u.pending = u.pending[len(u.pending)-hold:]
+}
+
+// Flush writes the held back text and ends the explanation.
+func (u *unmaskingWriter) Flush() {
+	u.write(u.pending + "\n")
+	u.pending = ""
+}
+
+func (u *unmaskingWriter) write(text string) {
+	if text != "" {
+		fmt.Fprint(u.w, color.GreenString(text))
+	}
+}
+
+// orderedWriter lets concurrent explanations be rendered in the order of the
+// results: the section of the first unfinished result is written through,
+// later sections are buffered until all sections before them are done.
+type orderedWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	current  int
+	buffers  []bytes.Buffer
+	finished []bool
+}
+
+func newOrderedWriter(w io.Writer, sections int) *orderedWriter {
+	return &orderedWriter{
+		w:        w,
+		buffers:  make([]bytes.Buffer, sections),
+		finished: make([]bool, sections),
+	}
+}
+
+// Section returns the writer for section i.
+func (o *orderedWriter) Section(i int) io.Writer {
+	return orderedSection{o: o, i: i}
+}
+
+// Done marks section i as finished and writes out the buffered sections that
+// are next in order.
+func (o *orderedWriter) Done(i int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.finished[i] = true
+	for o.current < len(o.finished) && o.finished[o.current] {
+		o.current++
+		if o.current < len(o.buffers) {
+			_, _ = o.w.Write(o.buffers[o.current].Bytes())
+			o.buffers[o.current].Reset()
+		}
+	}
+}
+
+type orderedSection struct {
+	o *orderedWriter
+	i int
+}
+
+func (s orderedSection) Write(p []byte) (int, error) {
+	s.o.mu.Lock()
+	defer s.o.mu.Unlock()
+	if s.i == s.o.current {
+		return s.o.w.Write(p)
+	}
+	return s.o.buffers[s.i].Write(p)
+}
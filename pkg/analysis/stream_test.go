@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/ai"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingAIClient streams a fixed completion in chunks.
+type streamingAIClient struct {
+	ai.NoOpAIClient
+	chunks []string
+}
+
+func (c *streamingAIClient) StreamCompletion(_ context.Context, _ string) (<-chan ai.StreamChunk, error) {
+	chunks := make(chan ai.StreamChunk, len(c.chunks))
+	for _, chunk := range c.chunks {
+		chunks <- ai.StreamChunk{Content: chunk}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func TestGetAIResultsStream(t *testing.T) {
+	disabledCache := cache.New("disabled-cache")
+	disabledCache.DisableCache()
+
+	var output bytes.Buffer
+	a := Analysis{
+		Context: context.Background(),
+		// The masked name is split across chunks.
+		AIClient:           &streamingAIClient{chunks: []string{"Pod Xy", "Zzy is ", "crashing."}},
+		AnalysisAIProvider: "test",
+		Explain:            true,
+		Cache:              disabledCache,
+		StreamOutput:       &output,
+		Results: []common.Result{
+			{
+				Kind: "Pod",
+				Name: "default/web",
+				Error: []common.Failure{
+					{
+						Text:      "Pod web is crashing",
+						Sensitive: []common.Sensitive{{Unmasked: "web", Masked: "XyZzy"}},
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, a.GetAIResults("text", true))
+	require.Equal(t, "Pod web is crashing.", a.Results[0].Details)
+	require.Contains(t, output.String(), "AI Provider: test")
+	require.Contains(t, output.String(), "0: Pod default/web()\n- Error: [warning] Pod web is crashing\nPod web is crashing.\n")
+}
+
+func TestUnmaskingWriter(t *testing.T) {
+	var output bytes.Buffer
+	u := newUnmaskingWriter(&output, []common.Failure{
+		{Sensitive: []common.Sensitive{{Unmasked: "web", Masked: "XyZzy"}}},
+	}, true)
+
+	u.Write("the Xy")
+	require.Equal(t, "the ", output.String())
+	u.Write("Zzy pod and Xy")
+	require.Equal(t, "the web pod and ", output.String())
+	u.Write("z")
+	u.Flush()
+	require.Equal(t, "the web pod and Xyz\n", output.String())
+}
+
+// delayedAIClient answers prompts mentioning "slow" last.
+type delayedAIClient struct {
+	ai.NoOpAIClient
+}
+
+func (c *delayedAIClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	if strings.Contains(prompt, "slow") {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return c.NoOpAIClient.GetCompletion(ctx, prompt)
+}
+
+func TestGetAIResultsStreamConcurrent(t *testing.T) {
+	disabledCache := cache.New("disabled-cache")
+	disabledCache.DisableCache()
+
+	var output bytes.Buffer
+	a := Analysis{
+		Context:        context.Background(),
+		AIClient:       &delayedAIClient{},
+		Cache:          disabledCache,
+		MaxConcurrency: 3,
+		StreamOutput:   &output,
+		Results: []common.Result{
+			{Kind: "Pod", Name: "default/a", Error: []common.Failure{{Text: "Pod a is slow"}}},
+			{Kind: "Pod", Name: "default/b", Error: []common.Failure{{Text: "Pod b is pending"}}},
+			{Kind: "Pod", Name: "default/c", Error: []common.Failure{{Text: "Pod c is pending"}}},
+		},
+	}
+	require.NoError(t, a.GetAIResults("text", false))
+
+	// The results are rendered in order although the first one finished last.
+	text := output.String()
+	a0, b1, c2 := strings.Index(text, "0: Pod default/a"), strings.Index(text, "1: Pod default/b"), strings.Index(text, "2: Pod default/c")
+	require.True(t, a0 >= 0 && a0 < b1 && b1 < c2, text)
+	require.Contains(t, text, "Pod a is slow\nI am a noop response")
+}
+
+func TestOrderedWriter(t *testing.T) {
+	var output bytes.Buffer
+	o := newOrderedWriter(&output, 3)
+
+	fmt.Fprint(o.Section(1), "b")
+	fmt.Fprint(o.Section(0), "a")
+	require.Equal(t, "a", output.String())
+	o.Done(1)
+	fmt.Fprint(o.Section(2), "c")
+	require.Equal(t, "a", output.String())
+	o.Done(0)
+	require.Equal(t, "abc", output.String())
+	fmt.Fprint(o.Section(2), "!")
+	o.Done(2)
+	require.Equal(t, "abc!", output.String())
+}
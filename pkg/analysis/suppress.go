@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// IgnoreAnnotation suppresses every finding of the annotated object.
+	// Its value is the reason, "true" works as well; "false" has no effect.
+	IgnoreAnnotation = "k8sgpt.ai/ignore"
+
+	// DefaultIgnoreFile is the suppression file read from the working
+	// directory.
+	DefaultIgnoreFile = ".k8sgptignore"
+
+	suppressionDateLayout = "2006-01-02"
+)
+
+// SuppressionRule silences the findings it matches. Empty fields match
+// everything.
+type SuppressionRule struct {
+	// Kind is the kind of the object, e.g. CronJob.
+	Kind string `json:"kind,omitempty"`
+	// Namespace and Name are globs matched against the namespace and the
+	// name of the object.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Text is a regular expression matched against the failure text.
+	Text string `json:"text,omitempty"`
+	// Expires is the last day, as YYYY-MM-DD, on which the rule applies.
+	Expires string `json:"expires,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+
+	source  string
+	text    *regexp.Regexp
+	expires time.Time
+}
+
+// SuppressedFinding is a failure that was not reported.
+type SuppressedFinding struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Cluster string `json:"cluster,omitempty"`
+	Text    string `json:"text"`
+	Reason  string `json:"reason,omitempty"`
+	// Source is the suppression file, "config" or the annotation.
+	Source string `json:"source"`
+}
+
+// LoadSuppressionFile reads the YAML list of rules in path.
+func LoadSuppressionFile(path string) ([]SuppressionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []SuppressionRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// AddSuppressionRules validates rules and applies them to the following
+// runs. Expired rules are reported in Errors and skipped.
+func (a *Analysis) AddSuppressionRules(rules []SuppressionRule, source string) error {
+	now := time.Now()
+	for i, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return fmt.Errorf("suppression rule %d of %s: %w", i+1, source, err)
+		}
+		rule.source = source
+		if !rule.expires.IsZero() && now.After(rule.expires) {
+			a.Errors = append(a.Errors, fmt.Sprintf("[Suppression] rule %d of %s expired on %s, its findings are reported again", i+1, source, rule.Expires))
+			continue
+		}
+		a.Suppressions = append(a.Suppressions, rule)
+	}
+	return nil
+}
+
+func (r *SuppressionRule) compile() error {
+	for _, glob := range []string{r.Namespace, r.Name} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	if r.Text != "" {
+		text, err := regexp.Compile(r.Text)
+		if err != nil {
+			return fmt.Errorf("invalid text pattern: %w", err)
+		}
+		r.text = text
+	}
+	if r.Expires != "" {
+		expires, err := time.Parse(suppressionDateLayout, r.Expires)
+		if err != nil {
+			return fmt.Errorf("invalid expiry date %q, expected YYYY-MM-DD", r.Expires)
+		}
+		// The rule applies until the end of the day.
+		r.expires = expires.Add(24 * time.Hour)
+	}
+	if r.Kind == "" && r.Namespace == "" && r.Name == "" && r.Text == "" {
+		return errors.New("the rule matches every finding")
+	}
+	return nil
+}
+
+func (r SuppressionRule) matches(kind, namespace, name, text string) bool {
+	if r.Kind != "" && !strings.EqualFold(r.Kind, kind) {
+		return false
+	}
+	if r.Namespace != "" {
+		if ok, _ := path.Match(r.Namespace, namespace); !ok {
+			return false
+		}
+	}
+	if r.Name != "" {
+		if ok, _ := path.Match(r.Name, name); !ok {
+			return false
+		}
+	}
+	return r.text == nil || r.text.MatchString(text)
+}
+
+// suppressor drops the failures of one cluster that match a rule or whose
+// object carries IgnoreAnnotation. Annotations are read from the store of
+// the run, so objects the analyzers listed are not fetched again. It is safe
+// for concurrent use.
+type suppressor struct {
+	rules  []SuppressionRule
+	client *kubernetes.Client
+	store  *kubernetes.Store
+
+	once      sync.Once
+	resources map[string]schema.GroupVersionResource // keyed by kind
+
+	mutex   sync.Mutex
+	ignored map[string]string // IgnoreAnnotation by objectKey, "" if not ignored
+}
+
+func (a *Analysis) newSuppressor(store *kubernetes.Store) *suppressor {
+	return &suppressor{
+		rules:   a.Suppressions,
+		client:  a.Client,
+		store:   store,
+		ignored: map[string]string{},
+	}
+}
+
+// apply returns the results without their suppressed failures, dropping
+// results that are left without failures, and the suppressed failures.
+func (s *suppressor) apply(results []common.Result) ([]common.Result, []SuppressedFinding) {
+	kept := make([]common.Result, 0, len(results))
+	var suppressed []SuppressedFinding
+	for _, result := range results {
+		namespace, name, ok := strings.Cut(result.Name, "/")
+		if !ok {
+			namespace, name = "", result.Name
+		}
+
+		if reason, ok := s.annotation(result.Kind, namespace, name); ok {
+			for _, failure := range result.Error {
+				suppressed = append(suppressed, SuppressedFinding{Kind: result.Kind, Name: result.Name, Text: failure.Text, Reason: reason, Source: IgnoreAnnotation})
+			}
+			continue
+		}
+
+		var failures []common.Failure
+	failures:
+		for _, failure := range result.Error {
+			for _, rule := range s.rules {
+				if rule.matches(result.Kind, namespace, name, failure.Text) {
+					suppressed = append(suppressed, SuppressedFinding{Kind: result.Kind, Name: result.Name, Text: failure.Text, Reason: rule.Reason, Source: rule.source})
+					continue failures
+				}
+			}
+			failures = append(failures, failure)
+		}
+		if len(failures) == 0 {
+			continue
+		}
+		result.Error = failures
+		kept = append(kept, result)
+	}
+	return kept, suppressed
+}
+
+// annotation returns the reason given by IgnoreAnnotation on the object, and
+// whether the object is ignored. Objects that cannot be read are not ignored.
+func (s *suppressor) annotation(kind, namespace, name string) (string, bool) {
+	if s.store == nil || s.client == nil || s.client.GetDynamicClient() == nil || s.client.GetClient() == nil {
+		return "", false
+	}
+	key := objectKey(kind, namespace, name)
+	s.mutex.Lock()
+	value, ok := s.ignored[key]
+	s.mutex.Unlock()
+	if !ok {
+		value = s.readAnnotation(kind, namespace, name)
+		s.mutex.Lock()
+		s.ignored[key] = value
+		s.mutex.Unlock()
+	}
+	if value == "" || strings.EqualFold(value, "false") {
+		return "", false
+	}
+	if strings.EqualFold(value, "true") {
+		return "", true
+	}
+	return value, true
+}
+
+func (s *suppressor) readAnnotation(kind, namespace, name string) string {
+	s.once.Do(func() {
+		s.resources = map[string]schema.GroupVersionResource{}
+		// Discovery returns the resources it found along with the
+		// groups it could not read.
+		groups, lists, _ := s.client.GetClient().Discovery().ServerGroupsAndResources()
+		preferred := map[string]bool{}
+		for _, group := range groups {
+			preferred[group.PreferredVersion.GroupVersion] = true
+		}
+		for _, list := range lists {
+			gv, err := schema.ParseGroupVersion(list.GroupVersion)
+			if err != nil || !preferred[list.GroupVersion] {
+				continue
+			}
+			for _, resource := range list.APIResources {
+				if strings.Contains(resource.Name, "/") {
+					continue
+				}
+				if _, ok := s.resources[resource.Kind]; !ok {
+					s.resources[resource.Kind] = gv.WithResource(resource.Name)
+				}
+			}
+		}
+	})
+	gvr, ok := s.resources[kind]
+	if !ok {
+		return ""
+	}
+	object, err := s.store.ObjectMeta(gvr, namespace, name)
+	if err != nil {
+		return ""
+	}
+	return object.GetAnnotations()[IgnoreAnnotation]
+}
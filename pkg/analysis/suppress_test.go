@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAddSuppressionRules(t *testing.T) {
+	for _, rule := range []SuppressionRule{
+		{},
+		{Reason: "matches everything"},
+		{Kind: "Pod", Name: "[a-"},
+		{Kind: "Pod", Text: "(unclosed"},
+		{Kind: "Pod", Expires: "31/12/2024"},
+	} {
+		a := &Analysis{}
+		require.Error(t, a.AddSuppressionRules([]SuppressionRule{rule}, "test"), rule)
+		require.Empty(t, a.Suppressions)
+	}
+
+	a := &Analysis{}
+	require.NoError(t, a.AddSuppressionRules([]SuppressionRule{
+		{Kind: "Pod", Expires: "2020-01-01"},
+		{Kind: "Pod", Expires: time.Now().Format(suppressionDateLayout)},
+	}, "test"))
+	require.Len(t, a.Suppressions, 1)
+	require.Equal(t, []string{"[Suppression] rule 1 of test expired on 2020-01-01, its findings are reported again"}, a.Errors)
+}
+
+func TestLoadSuppressionFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultIgnoreFile)
+	require.NoError(t, os.WriteFile(path, []byte(`
+- kind: CronJob
+  namespace: batch-*
+  name: nightly-*
+  text: "schedule .* is invalid"
+  expires: "2099-12-31"
+  reason: tracked in OPS-42
+`), 0o600))
+
+	rules, err := LoadSuppressionFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []SuppressionRule{{
+		Kind:      "CronJob",
+		Namespace: "batch-*",
+		Name:      "nightly-*",
+		Text:      "schedule .* is invalid",
+		Expires:   "2099-12-31",
+		Reason:    "tracked in OPS-42",
+	}}, rules)
+
+	_, err = LoadSuppressionFile(filepath.Join(t.TempDir(), DefaultIgnoreFile))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSuppressorRules(t *testing.T) {
+	a := &Analysis{}
+	require.NoError(t, a.AddSuppressionRules([]SuppressionRule{
+		{Kind: "cronjob", Namespace: "batch-*", Name: "nightly-*", Text: "schedule", Reason: "known"},
+		{Kind: "Service", Name: "legacy"},
+	}, DefaultIgnoreFile))
+
+	results, suppressed := a.newSuppressor(nil).apply([]common.Result{
+		{Kind: "CronJob", Name: "batch-eu/nightly-report", Error: []common.Failure{
+			{Text: "CronJob nightly-report has an invalid schedule"},
+			{Text: "CronJob nightly-report is suspended"},
+		}},
+		{Kind: "CronJob", Name: "default/nightly-report", Error: failure("CronJob nightly-report has an invalid schedule", common.SeverityWarning)},
+		{Kind: "Service", Name: "default/legacy", Error: failure("Service has no endpoints", common.SeverityWarning)},
+	})
+
+	require.Equal(t, []common.Result{
+		{Kind: "CronJob", Name: "batch-eu/nightly-report", Error: []common.Failure{{Text: "CronJob nightly-report is suspended"}}},
+		{Kind: "CronJob", Name: "default/nightly-report", Error: failure("CronJob nightly-report has an invalid schedule", common.SeverityWarning)},
+	}, results)
+	require.Equal(t, []SuppressedFinding{
+		{Kind: "CronJob", Name: "batch-eu/nightly-report", Text: "CronJob nightly-report has an invalid schedule", Reason: "known", Source: DefaultIgnoreFile},
+		{Kind: "Service", Name: "default/legacy", Text: "Service has no endpoints", Source: DefaultIgnoreFile},
+	}, suppressed)
+}
+
+func TestSuppressorAnnotation(t *testing.T) {
+	pod := func(name, ignore string) *v1.Pod {
+		p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if ignore != "" {
+			p.Annotations = map[string]string{IgnoreAnnotation: ignore}
+		}
+		return p
+	}
+	cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Annotations: map[string]string{IgnoreAnnotation: "true"}}}
+
+	clientset := fake.NewSimpleClientset(pod("migration", "waiting for the database upgrade"), pod("debug", "true"), pod("web", "false"), pod("api", ""))
+	clientset.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true}, {Name: "pods/log", Kind: "Pod", Namespaced: true}},
+	}, {
+		GroupVersion: "batch/v1",
+		APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob", Namespaced: true}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, cronJob, &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "hourly", Namespace: "default"}})
+
+	a := &Analysis{
+		Context: context.Background(),
+		Client:  &kubernetes.Client{Client: clientset, DynamicClient: dynamicClient},
+	}
+	var input []common.Result
+	for _, name := range []string{"migration", "debug", "web", "api", "deleted"} {
+		input = append(input, common.Result{Kind: "Pod", Name: "default/" + name, Error: failure("Pod is not ready", common.SeverityWarning)})
+	}
+	for _, name := range []string{"nightly", "hourly"} {
+		input = append(input, common.Result{Kind: "CronJob", Name: "default/" + name, Error: failure("CronJob is suspended", common.SeverityWarning)})
+	}
+	results, suppressed := a.newSuppressor(a.clusterStore("")).apply(input)
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	require.Equal(t, []string{"default/web", "default/api", "default/deleted", "default/hourly"}, names)
+	require.Equal(t, []SuppressedFinding{
+		{Kind: "Pod", Name: "default/migration", Text: "Pod is not ready", Reason: "waiting for the database upgrade", Source: IgnoreAnnotation},
+		{Kind: "Pod", Name: "default/debug", Text: "Pod is not ready", Source: IgnoreAnnotation},
+		{Kind: "CronJob", Name: "default/nightly", Text: "CronJob is suspended", Source: IgnoreAnnotation},
+	}, suppressed)
+
+	// Discovery is read once and the objects are listed once, not fetched
+	// one by one.
+	countVerbs := func(actions []k8stesting.Action) map[string]int {
+		verbs := map[string]int{}
+		for _, action := range actions {
+			verbs[action.GetVerb()+" "+action.GetResource().Resource]++
+		}
+		return verbs
+	}
+	require.Equal(t, map[string]int{"get group": 1, "get resource": 1, "list pods": 1}, countVerbs(clientset.Actions()))
+	require.Equal(t, map[string]int{"list cronjobs": 1}, countVerbs(dynamicClient.Actions()))
+}
+
+func TestSuppressedOutput(t *testing.T) {
+	a := &Analysis{
+		Results:    []common.Result{{Kind: "Pod", Name: "default/web", Error: failure("Pod is not ready", common.SeverityWarning)}},
+		Suppressed: []SuppressedFinding{{Kind: "Pod", Name: "default/debug"}, {Kind: "Pod", Name: "default/migration"}},
+	}
+
+	output, err := a.PrintOutput("json")
+	require.NoError(t, err)
+	var jsonOutput JsonOutput
+	require.NoError(t, json.Unmarshal(output, &jsonOutput))
+	require.Equal(t, 1, jsonOutput.Problems)
+	require.Equal(t, 2, jsonOutput.Suppressed)
+
+	output, err = a.PrintOutput("text")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "Suppressed: 2 findings")
+
+	output, err = a.PrintOutput("markdown")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "Suppressed: 2 findings")
+}
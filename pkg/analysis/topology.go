@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+)
+
+// topologyNode is an object of the topology. Broken objects have results.
+type topologyNode struct {
+	id       string
+	group    string // cluster and namespace of the object
+	kind     string
+	name     string
+	broken   bool
+	severity common.Severity
+	details  string // explanation of the results of the object
+}
+
+// topologyEdge is drawn from the dependent object to the object it depends
+// on, e.g. from a Deployment to its ReplicaSet.
+type topologyEdge struct {
+	from     string
+	to       string
+	relation string
+}
+
+type topology struct {
+	nodes []topologyNode
+	edges []topologyEdge
+}
+
+// severityStyles are the fill and stroke colors of broken objects.
+var severityStyles = map[common.Severity][2]string{
+	common.SeverityCritical: {"#f8d7da", "#c62828"},
+	common.SeverityWarning:  {"#fff3cd", "#f9a825"},
+	common.SeverityInfo:     {"#d1ecf1", "#0277bd"},
+}
+
+// newTopology returns the objects with results and the objects connected to
+// them, which is the blast radius of the problems found.
+func (a *Analysis) newTopology() topology {
+	var t topology
+	byCluster := map[string]map[string]common.Severity{}
+	details := map[string]map[string]string{}
+	for _, result := range a.Results {
+		broken := byCluster[result.Cluster]
+		if broken == nil {
+			broken = map[string]common.Severity{}
+			byCluster[result.Cluster] = broken
+			details[result.Cluster] = map[string]string{}
+		}
+		key := resultKey(result)
+		severity := result.MaxSeverity()
+		if current, ok := broken[key]; !ok || severity.AtLeast(current) {
+			broken[key] = severity
+		}
+		if text := strings.TrimSpace(result.Details); text != "" && details[result.Cluster][key] == "" {
+			details[result.Cluster][key] = text
+		}
+	}
+
+	for _, cluster := range sortedMapKeys(byCluster) {
+		broken := byCluster[cluster]
+		graph, _ := a.clusterGraph(cluster)
+
+		// Walk the graph in both directions from every broken object.
+		neighbours := map[string][]string{}
+		for cause, edges := range graph {
+			for _, edge := range edges {
+				neighbours[cause] = append(neighbours[cause], edge.to)
+				neighbours[edge.to] = append(neighbours[edge.to], cause)
+			}
+		}
+		visited := map[string]bool{}
+		var queue []string
+		for key := range broken {
+			visited[key] = true
+			queue = append(queue, key)
+		}
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+			for _, next := range neighbours[key] {
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		keys := sortedMapKeys(visited)
+		sort.SliceStable(keys, func(i, j int) bool {
+			_, ni, _ := splitObjectKey(keys[i])
+			_, nj, _ := splitObjectKey(keys[j])
+			return ni < nj
+		})
+		ids := map[string]string{}
+		for _, key := range keys {
+			kind, namespace, name := splitObjectKey(key)
+			if namespace == "" {
+				namespace = clusterScope
+			}
+			group := namespace
+			if cluster != "" {
+				group = cluster + "/" + namespace
+			}
+			severity, isBroken := broken[key]
+			node := topologyNode{
+				id:       fmt.Sprintf("n%d", len(t.nodes)),
+				group:    group,
+				kind:     kind,
+				name:     name,
+				broken:   isBroken,
+				severity: severity,
+				details:  details[cluster][key],
+			}
+			ids[key] = node.id
+			t.nodes = append(t.nodes, node)
+		}
+
+		var edges []topologyEdge
+		for cause, causeEdges := range graph {
+			if !visited[cause] {
+				continue
+			}
+			for _, edge := range causeEdges {
+				if visited[edge.to] {
+					edges = append(edges, topologyEdge{from: ids[edge.to], to: ids[cause], relation: edge.relation})
+				}
+			}
+		}
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].from != edges[j].from {
+				return edges[i].from < edges[j].from
+			}
+			if edges[i].to != edges[j].to {
+				return edges[i].to < edges[j].to
+			}
+			return edges[i].relation < edges[j].relation
+		})
+		// The same relation may be found twice, e.g. a Service behind two
+		// paths of an Ingress.
+		for i, edge := range edges {
+			if i == 0 || edge != edges[i-1] {
+				t.edges = append(t.edges, edge)
+			}
+		}
+	}
+	return t
+}
+
+// groups returns the nodes of t by group, in order of their first node.
+func (t topology) groups() ([]string, map[string][]topologyNode) {
+	var names []string
+	nodes := map[string][]topologyNode{}
+	for _, node := range t.nodes {
+		if _, ok := nodes[node.group]; !ok {
+			names = append(names, node.group)
+		}
+		nodes[node.group] = append(nodes[node.group], node)
+	}
+	return names, nodes
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+func (a *Analysis) mermaidOutput() ([]byte, error) {
+	t := a.newTopology()
+	var output strings.Builder
+	output.WriteString("flowchart LR\n")
+	names, nodes := t.groups()
+	for i, name := range names {
+		output.WriteString(fmt.Sprintf("  subgraph g%d[\"%s\"]\n", i, mermaidEscaper.Replace(name)))
+		for _, node := range nodes[name] {
+			output.WriteString(fmt.Sprintf("    %s[\"%s<br/>%s\"]\n", node.id, mermaidEscaper.Replace(node.kind), mermaidEscaper.Replace(node.name)))
+		}
+		output.WriteString("  end\n")
+	}
+	for _, edge := range t.edges {
+		output.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", edge.from, edge.relation, edge.to))
+	}
+	// Mermaid has no tooltips without links, the explanations are comments.
+	for _, node := range t.nodes {
+		if node.details == "" {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("  %%%% %s %s %s:\n", node.id, node.kind, node.name))
+		for _, line := range strings.Split(node.details, "\n") {
+			output.WriteString(fmt.Sprintf("  %%%% %s\n", line))
+		}
+	}
+
+	broken := map[common.Severity][]string{}
+	for _, node := range t.nodes {
+		if node.broken {
+			broken[node.severity] = append(broken[node.severity], node.id)
+		}
+	}
+	for _, severity := range []common.Severity{common.SeverityCritical, common.SeverityWarning, common.SeverityInfo} {
+		if len(broken[severity]) == 0 {
+			continue
+		}
+		style := severityStyles[severity]
+		output.WriteString(fmt.Sprintf("  classDef %s fill:%s,stroke:%s,stroke-width:2px\n", severity, style[0], style[1]))
+		output.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(broken[severity], ","), severity))
+	}
+	return []byte(output.String()), nil
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (a *Analysis) dotOutput() ([]byte, error) {
+	t := a.newTopology()
+	var output strings.Builder
+	output.WriteString("digraph k8sgpt {\n")
+	output.WriteString("  rankdir=LR;\n")
+	output.WriteString("  node [shape=box, style=rounded];\n")
+	names, nodes := t.groups()
+	for i, name := range names {
+		output.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		output.WriteString(fmt.Sprintf("    label=\"%s\";\n", dotEscaper.Replace(name)))
+		for _, node := range nodes[name] {
+			attributes := fmt.Sprintf("label=\"%s\\n%s\"", dotEscaper.Replace(node.kind), dotEscaper.Replace(node.name))
+			if node.broken {
+				style := severityStyles[node.severity]
+				attributes += fmt.Sprintf(", style=\"rounded,filled\", fillcolor=\"%s\", color=\"%s\", penwidth=2", style[0], style[1])
+			}
+			if node.details != "" {
+				attributes += fmt.Sprintf(", tooltip=\"%s\"", dotEscaper.Replace(node.details))
+			}
+			output.WriteString(fmt.Sprintf("    %s [%s];\n", node.id, attributes))
+		}
+		output.WriteString("  }\n")
+	}
+	for _, edge := range t.edges {
+		output.WriteString(fmt.Sprintf("  %s -> %s [label=\"%s\"];\n", edge.from, edge.to, edge.relation))
+	}
+	output.WriteString("}\n")
+	return []byte(output.String()), nil
+}
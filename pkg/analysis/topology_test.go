@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func topologyAnalysis() *Analysis {
+	storageClass := "fast"
+	return &Analysis{
+		Context: context.Background(),
+		Client: &kubernetes.Client{Client: fake.NewSimpleClientset(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "db-5f", Namespace: "default", OwnerReferences: ownedBy("Deployment", "db")}},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-5f-a", Namespace: "default", Labels: map[string]string{"app": "db"}, OwnerReferences: ownedBy("ReplicaSet", "db-5f")},
+				Spec: v1.PodSpec{Volumes: []v1.Volume{{
+					Name:         "data",
+					VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+				}}},
+			},
+			&v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"}, Spec: v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}},
+			&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}, Spec: v1.ServiceSpec{Selector: map[string]string{"app": "db"}}},
+			&autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "db"}},
+			},
+			&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"}},
+		)},
+		Results: []common.Result{
+			{Kind: "PersistentVolumeClaim", Name: "default/data", Error: failure("storageclass.storage.k8s.io \"fast\" not found", common.SeverityCritical)},
+			{Kind: "Service", Name: "default/db", Error: failure("Service has no ready endpoints", common.SeverityWarning)},
+		},
+	}
+}
+
+func TestTopology(t *testing.T) {
+	topology := topologyAnalysis().newTopology()
+
+	labels := map[string]string{}
+	broken := map[string]common.Severity{}
+	for _, node := range topology.nodes {
+		require.NotContains(t, node.name, "unrelated")
+		labels[node.id] = node.kind + " " + node.name
+		if node.broken {
+			broken[node.kind] = node.severity
+		}
+	}
+	require.Len(t, topology.nodes, 7)
+	require.Equal(t, map[string]common.Severity{
+		"PersistentVolumeClaim": common.SeverityCritical,
+		"Service":               common.SeverityWarning,
+	}, broken)
+
+	var edges []string
+	for _, edge := range topology.edges {
+		edges = append(edges, labels[edge.from]+" "+edge.relation+" "+labels[edge.to])
+	}
+	require.ElementsMatch(t, []string{
+		"Deployment db owns ReplicaSet db-5f",
+		"ReplicaSet db-5f owns Pod db-5f-a",
+		"Service db selects Pod db-5f-a",
+		"Pod db-5f-a mounts PersistentVolumeClaim data",
+		"PersistentVolumeClaim data uses StorageClass fast",
+		"HorizontalPodAutoscaler db scales Deployment db",
+	}, edges)
+}
+
+func TestTopologyOutput(t *testing.T) {
+	output, err := topologyAnalysis().PrintOutput("mermaid")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "flowchart LR\n")
+	require.Contains(t, string(output), "subgraph g0[\"(cluster-scoped)\"]")
+	require.Contains(t, string(output), "[\"PersistentVolumeClaim<br/>data\"]")
+	require.Contains(t, string(output), "-->|uses|")
+	require.Contains(t, string(output), "classDef critical fill:#f8d7da")
+
+	output, err = topologyAnalysis().PrintOutput("dot")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "digraph k8sgpt {\n")
+	require.Contains(t, string(output), "label=\"default\";")
+	require.Contains(t, string(output), "label=\"Service\\ndb\", style=\"rounded,filled\", fillcolor=\"#fff3cd\"")
+	require.Contains(t, string(output), "[label=\"scales\"];")
+
+	// Without a cluster only the broken objects are drawn.
+	a := &Analysis{Results: []common.Result{{Kind: "Node", Name: "worker-1", Error: failure("Node is not ready", common.SeverityCritical)}}}
+	output, err = a.PrintOutput("mermaid")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "n0[\"Node<br/>worker-1\"]")
+	require.Contains(t, string(output), "class n0 critical")
+}
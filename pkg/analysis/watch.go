@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/informers"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+type WatchEventType string
+
+const (
+	WatchEventAdded    WatchEventType = "Added"
+	WatchEventResolved WatchEventType = "Resolved"
+)
+
+// WatchEvent reports a single failure that appeared or resolved since the
+// previous run of its analyzer.
+type WatchEvent struct {
+	Type     WatchEventType `json:"type"`
+	Analyzer string         `json:"analyzer"`
+	Result   common.Result  `json:"result"`
+}
+
+// watchDebounce is the time to wait after an informer notification before
+// re-running the affected analyzers, so a burst of updates triggers one run.
+var watchDebounce = 2 * time.Second
+
+// watchTriggers maps an analyzer to the resources whose changes should re-run
+// it. Analyzers without an entry, such as the Gateway API, integration, rule
+// and generic condition analyzers, are only re-run on the resync interval.
+var watchTriggers = map[string][]string{
+	"Pod":                            {"pods"},
+	"Log":                            {"pods"},
+	"Deployment":                     {"deployments"},
+	"ReplicaSet":                     {"replicasets"},
+	"StatefulSet":                    {"statefulsets", "services"},
+	"PersistentVolumeClaim":          {"persistentvolumeclaims"},
+	"Service":                        {"endpoints"},
+	"Ingress":                        {"ingresses", "services"},
+	"CronJob":                        {"cronjobs"},
+	"Node":                           {"nodes"},
+	"ValidatingWebhookConfiguration": {"validatingwebhookconfigurations", "pods"},
+	"MutatingWebhookConfiguration":   {"mutatingwebhookconfigurations", "pods"},
+	"HorizontalPodAutoScaler":        {"horizontalpodautoscalers"},
+	"PodDisruptionBudget":            {"poddisruptionbudgets"},
+	"NetworkPolicy":                  {"networkpolicies", "pods"},
+}
+
+func watchInformer(f informers.SharedInformerFactory, resource string) toolscache.SharedIndexInformer {
+	switch resource {
+	case "pods":
+		return f.Core().V1().Pods().Informer()
+	case "services":
+		return f.Core().V1().Services().Informer()
+	case "endpoints":
+		return f.Core().V1().Endpoints().Informer()
+	case "nodes":
+		return f.Core().V1().Nodes().Informer()
+	case "persistentvolumeclaims":
+		return f.Core().V1().PersistentVolumeClaims().Informer()
+	case "deployments":
+		return f.Apps().V1().Deployments().Informer()
+	case "replicasets":
+		return f.Apps().V1().ReplicaSets().Informer()
+	case "statefulsets":
+		return f.Apps().V1().StatefulSets().Informer()
+	case "ingresses":
+		return f.Networking().V1().Ingresses().Informer()
+	case "networkpolicies":
+		return f.Networking().V1().NetworkPolicies().Informer()
+	case "cronjobs":
+		return f.Batch().V1().CronJobs().Informer()
+	case "validatingwebhookconfigurations":
+		return f.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer()
+	case "mutatingwebhookconfigurations":
+		return f.Admissionregistration().V1().MutatingWebhookConfigurations().Informer()
+	case "horizontalpodautoscalers":
+		return f.Autoscaling().V2().HorizontalPodAutoscalers().Informer()
+	case "poddisruptionbudgets":
+		return f.Policy().V1().PodDisruptionBudgets().Informer()
+	}
+	return nil
+}
+
+// Watch keeps shared informers running for the resources the selected
+// analyzers depend on and re-runs only the analyzers affected by a change.
+// Every failure that appears or resolves is passed to handler. Watch returns
+// when a.Context is cancelled.
+func (a *Analysis) Watch(resync time.Duration, anonymize bool, handler func(WatchEvent)) error {
+	if resync <= 0 {
+		return errors.New("watch resync interval must be positive")
+	}
+
+	analyzers := a.selectAnalyzers()
+	if len(analyzers) == 0 {
+		return errors.New("no analyzers selected to watch")
+	}
+	analyzerConfig := a.newAnalyzerConfig()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(a.Client.GetClient(), resync, informers.WithNamespace(a.Namespace))
+
+	dirty := newDirtySet()
+	for name := range analyzers {
+		for _, resource := range watchTriggers[name] {
+			name := name
+			_, err := watchInformer(factory, resource).AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+				AddFunc: func(interface{}) { dirty.mark(name) },
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					if !resourceVersionChanged(oldObj, newObj) {
+						return
+					}
+					dirty.mark(name)
+				},
+				DeleteFunc: func(interface{}) { dirty.mark(name) },
+			})
+			if err != nil {
+				return fmt.Errorf("registering %s watch: %w", name, err)
+			}
+		}
+	}
+
+	// The informers, including those the analyzers start through the store,
+	// must stop before Shutdown waits for them.
+	ctx, cancel := context.WithCancel(a.Context)
+	analyzerConfig.Context = ctx
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+	defer cancel()
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync informer cache for %v", informerType)
+		}
+	}
+
+	// The initial sync replays every object as an add; everything is about to
+	// be analyzed anyway, so drop those notifications.
+	dirty.take()
+
+	known := make(map[string]map[string]common.Result)
+	all := make(map[string]struct{}, len(analyzers))
+	for name := range analyzers {
+		all[name] = struct{}{}
+	}
+	a.runWatchedAnalyzers(analyzers, all, analyzerConfig, factory, known, anonymize, handler)
+
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-a.Context.Done():
+			return nil
+		case <-dirty.notify:
+			if debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+		case <-debounce:
+			debounce = nil
+			a.runWatchedAnalyzers(analyzers, dirty.take(), analyzerConfig, factory, known, anonymize, handler)
+		case <-ticker.C:
+			a.runWatchedAnalyzers(analyzers, all, analyzerConfig, factory, known, anonymize, handler)
+		}
+	}
+}
+
+// runWatchedAnalyzers re-runs the named analyzers and emits the difference
+// between their new failures and the ones recorded in known. The analyzers
+// read the objects from the caches of the informers of factory.
+func (a *Analysis) runWatchedAnalyzers(analyzers map[string]common.IAnalyzer, names map[string]struct{}, analyzerConfig common.Analyzer, factory informers.SharedInformerFactory, known map[string]map[string]common.Result, anonymize bool, handler func(WatchEvent)) {
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	// The informer caches are up to date; a new store per pass only drops
+	// the copies of the previous pass.
+	analyzerConfig.Store = kubernetes.NewInformerStore(analyzerConfig.Context, a.Client, a.Namespace, factory)
+	suppressor := a.newSuppressor(analyzerConfig.Store)
+	var events []WatchEvent
+	for _, name := range sortedNames {
+		results, err := analyzers[name].Analyze(analyzerConfig)
+		if err != nil {
+			// Keep the previous findings; an API hiccup is not a resolution.
+			color.Red("Error: [%s] %s", name, err)
+			continue
+		}
+
+		results, _ = suppressor.apply(a.applySeverity(results))
+		current := splitFailures(results)
+		previous := known[name]
+		for _, key := range sortedKeys(current) {
+			if _, ok := previous[key]; !ok {
+				events = append(events, WatchEvent{Type: WatchEventAdded, Analyzer: name, Result: current[key]})
+			}
+		}
+		for _, key := range sortedKeys(previous) {
+			if _, ok := current[key]; !ok {
+				events = append(events, WatchEvent{Type: WatchEventResolved, Analyzer: name, Result: previous[key]})
+			}
+		}
+		known[name] = current
+	}
+
+	if a.Explain {
+		a.explainWatchEvents(events, anonymize)
+	}
+	for _, event := range events {
+		handler(event)
+	}
+}
+
+// explainWatchEvents fills in the AI details of newly added findings.
+func (a *Analysis) explainWatchEvents(events []WatchEvent, anonymize bool) {
+	var added []int
+	a.Results = nil
+	for i, event := range events {
+		if event.Type != WatchEventAdded {
+			continue
+		}
+		added = append(added, i)
+		a.Results = append(a.Results, event.Result)
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	// The json output mode keeps the progress bar from interleaving with events.
+	if err := a.GetAIResults("json", anonymize); err != nil {
+		color.Red("Error: %v", err)
+		return
+	}
+	for n, i := range added {
+		events[i].Result.Details = a.Results[n].Details
+	}
+}
+
+// dirtySet collects the names of analyzers affected by informer
+// notifications until the watch loop picks them up.
+type dirtySet struct {
+	mu     sync.Mutex
+	names  map[string]struct{}
+	notify chan struct{}
+}
+
+func newDirtySet() *dirtySet {
+	return &dirtySet{
+		names:  make(map[string]struct{}),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (d *dirtySet) mark(name string) {
+	d.mu.Lock()
+	d.names[name] = struct{}{}
+	d.mu.Unlock()
+
+	select {
+	case d.notify <- struct{}{}:
+	default:
+		// A notification is already pending.
+	}
+}
+
+func (d *dirtySet) take() map[string]struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	names := d.names
+	d.names = make(map[string]struct{})
+	return names
+}
+
+func resourceVersionChanged(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return true
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return true
+	}
+	return oldMeta.GetResourceVersion() != newMeta.GetResourceVersion()
+}
+
+// WatchOutputFormats are the output formats supported by PrintWatchEvent.
+var WatchOutputFormats = []string{"json", "text"}
+
+// PrintWatchEvent renders a single watch event in the given output format.
+func PrintWatchEvent(format string, event WatchEvent) ([]byte, error) {
+	switch format {
+	case "json":
+		output, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling json: %v", err)
+		}
+		return output, nil
+	case "text":
+		var output strings.Builder
+		status := color.RedString("[%s]", event.Type)
+		if event.Type == WatchEventResolved {
+			status = color.GreenString("[%s]", event.Type)
+		}
+		output.WriteString(fmt.Sprintf("%s %s %s %s(%s)\n", time.Now().Format(time.RFC3339), status,
+			color.HiYellowString(event.Result.Kind),
+			color.YellowString(event.Result.Name),
+			color.CyanString(event.Result.ParentObject)))
+		for _, err := range event.Result.Error {
+			output.WriteString(fmt.Sprintf("- %s %s %s\n", color.RedString("Error:"), severityString(err.Level()), color.RedString(err.Text)))
+		}
+		if event.Result.Details != "" {
+			output.WriteString(color.GreenString(event.Result.Details + "\n"))
+		}
+		return []byte(output.String()), nil
+	default:
+		return nil, fmt.Errorf("unsupported watch output format: %s. Available format %s", format, strings.Join(WatchOutputFormats, ","))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func unschedulablePod(name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{
+				{
+					Type:    v1.PodScheduled,
+					Reason:  "Unschedulable",
+					Message: "0/1 nodes are available",
+				},
+			},
+		},
+	}
+}
+
+func TestWatch(t *testing.T) {
+	watchDebounce = 10 * time.Millisecond
+
+	clientset := fake.NewSimpleClientset(unschedulablePod("existing"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := Analysis{
+		Context:   ctx,
+		Filters:   []string{"Pod"},
+		Namespace: "default",
+		Client: &kubernetes.Client{
+			Client: clientset,
+		},
+	}
+
+	events := make(chan WatchEvent, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- a.Watch(time.Hour, false, func(event WatchEvent) {
+			events <- event
+		})
+	}()
+
+	next := func() WatchEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for watch event")
+			return WatchEvent{}
+		}
+	}
+
+	event := next()
+	require.Equal(t, WatchEventAdded, event.Type)
+	require.Equal(t, "default/existing", event.Result.Name)
+
+	_, err := clientset.CoreV1().Pods("default").Create(ctx, unschedulablePod("new"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	event = next()
+	require.Equal(t, WatchEventAdded, event.Type)
+	require.Equal(t, "default/new", event.Result.Name)
+
+	err = clientset.CoreV1().Pods("default").Delete(ctx, "existing", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	event = next()
+	require.Equal(t, WatchEventResolved, event.Type)
+	require.Equal(t, "default/existing", event.Result.Name)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestPrintWatchEvent(t *testing.T) {
+	event := WatchEvent{
+		Type:     WatchEventResolved,
+		Analyzer: "Pod",
+		Result:   common.Result{Kind: "Pod", Name: "default/example"},
+	}
+
+	output, err := PrintWatchEvent("json", event)
+	require.NoError(t, err)
+	require.Contains(t, string(output), `"type":"Resolved"`)
+
+	_, err = PrintWatchEvent("unsupported", event)
+	require.ErrorContains(t, err, "unsupported watch output format")
+}
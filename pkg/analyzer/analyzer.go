@@ -15,13 +15,17 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/integration"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -53,6 +57,7 @@ var additionalAnalyzerMap = map[string]common.IAnalyzer{
 	"GatewayClass":            GatewayClassAnalyzer{},
 	"Gateway":                 GatewayAnalyzer{},
 	"HTTPRoute":               HTTPRouteAnalyzer{},
+	"GenericCondition":        GenericConditionAnalyzer{},
 }
 
 func ListFilters() ([]string, []string, []string) {
@@ -81,9 +86,65 @@ func ListFilters() ([]string, []string, []string) {
 		}
 	}
 
+	// rule analyzers run by default, like the core analyzers
+	for _, rule := range ruleAnalyzers() {
+		coreKeys = append(coreKeys, rule.Rule.Name)
+	}
+
 	return coreKeys, additionalKeys, integrationAnalyzers
 }
 
+// loadedRules holds the rule analyzers of the last rule_analyzers
+// configuration, so that rules are compiled and their problems reported once.
+var loadedRules struct {
+	sync.Mutex
+	loaded    bool
+	config    interface{}
+	analyzers []RuleAnalyzer
+}
+
+// ruleProblems receives the problems of the rule analyzers. It is stderr so
+// they do not end up in the json, sarif or junit output of an analysis.
+var ruleProblems io.Writer = os.Stderr
+
+func reportRuleProblem(format string, a ...interface{}) {
+	fmt.Fprintln(ruleProblems, color.RedString(format, a...))
+}
+
+// ruleAnalyzers returns the compiled rule analyzers of the configuration.
+// Invalid rules and rules whose names conflict with a built-in analyzer are
+// reported and ignored.
+func ruleAnalyzers() []RuleAnalyzer {
+	loadedRules.Lock()
+	defer loadedRules.Unlock()
+	config := viper.Get("rule_analyzers")
+	if loadedRules.loaded && reflect.DeepEqual(config, loadedRules.config) {
+		return loadedRules.analyzers
+	}
+	loadedRules.loaded, loadedRules.config, loadedRules.analyzers = true, config, nil
+
+	rules, err := LoadRules()
+	if err != nil {
+		reportRuleProblem("%v", err)
+		return nil
+	}
+	for _, rule := range rules {
+		_, core := coreAnalyzerMap[rule.Name]
+		_, additional := additionalAnalyzerMap[rule.Name]
+		if core || additional {
+			reportRuleProblem("rule analyzer %s conflicts with a built-in analyzer and is ignored", rule.Name)
+			continue
+		}
+		analyzer, err := NewRuleAnalyzer(rule)
+		if err != nil {
+			reportRuleProblem("%v, the rule analyzer is ignored", err)
+			continue
+		}
+		loadedRules.analyzers = append(loadedRules.analyzers, analyzer)
+	}
+	return loadedRules.analyzers
+}
+
 func GetAnalyzerMap() (map[string]common.IAnalyzer, map[string]common.IAnalyzer) {
 
 	coreAnalyzer := make(map[string]common.IAnalyzer)
@@ -100,6 +161,12 @@ func GetAnalyzerMap() (map[string]common.IAnalyzer, map[string]common.IAnalyzer)
 		mergedAnalyzerMap[key] = value
 	}
 
+	// add rule analyzer
+	for _, rule := range ruleAnalyzers() {
+		coreAnalyzer[rule.Rule.Name] = rule
+		mergedAnalyzerMap[rule.Rule.Name] = rule
+	}
+
 	integrationProvider := integration.NewIntegration()
 
 	for _, i := range integrationProvider.List() {
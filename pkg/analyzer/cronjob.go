@@ -21,7 +21,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	cron "github.com/robfig/cron/v3"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -43,14 +42,14 @@ func (analyzer CronJobAnalyzer) Analyze(a common.Analyzer) ([]common.Result, err
 		"analyzer_name": kind,
 	})
 
-	cronJobList, err := a.Client.GetClient().BatchV1().CronJobs(a.Namespace).List(a.Context, v1.ListOptions{LabelSelector: a.LabelSelector})
+	cronJobList, err := a.GetStore().CronJobs(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, cronJob := range cronJobList.Items {
+	for _, cronJob := range cronJobList {
 		var failures []common.Failure
 		if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
 			doc := apiDoc.GetApiDocV2("spec.suspend")
@@ -68,6 +67,7 @@ func (analyzer CronJobAnalyzer) Analyze(a common.Analyzer) ([]common.Result, err
 						Masked:   util.MaskString(cronJob.Name),
 					},
 				},
+				Severity: common.SeverityInfo,
 			})
 		} else {
 			// check the schedule format
@@ -87,6 +87,7 @@ func (analyzer CronJobAnalyzer) Analyze(a common.Analyzer) ([]common.Result, err
 							Masked:   util.MaskString(cronJob.Name),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 			}
 
@@ -109,6 +110,7 @@ func (analyzer CronJobAnalyzer) Analyze(a common.Analyzer) ([]common.Result, err
 								Masked:   util.MaskString(cronJob.Name),
 							},
 						},
+						Severity: common.SeverityWarning,
 					})
 
 				}
@@ -144,16 +144,20 @@ func TestCronJobAnalyzer(t *testing.T) {
 		return results[i].Name < results[j].Name
 	})
 
-	expectations := []string{
-		"default/CJ2",
-		"default/CJ3",
-		"default/CJ4",
+	expectations := []struct {
+		name     string
+		severity common.Severity
+	}{
+		{"default/CJ2", common.SeverityInfo},
+		{"default/CJ3", common.SeverityWarning},
+		{"default/CJ4", common.SeverityCritical},
 	}
 
 	require.Equal(t, len(expectations), len(results))
 
 	for i, result := range results {
-		require.Equal(t, expectations[i], result.Name)
+		require.Equal(t, expectations[i].name, result.Name)
+		require.Equal(t, expectations[i].severity, result.MaxSeverity())
 	}
 }
 
@@ -14,10 +14,8 @@ limitations under the License.
 package analyzer
 
 import (
-	"context"
 	"fmt"
 
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
@@ -46,13 +44,13 @@ func (d DeploymentAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 		"analyzer_name": kind,
 	})
 
-	deployments, err := a.Client.GetClient().AppsV1().Deployments(a.Namespace).List(context.Background(), v1.ListOptions{LabelSelector: a.LabelSelector})
+	deployments, err := a.GetStore().Deployments(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, deployment := range deployments.Items {
+	for _, deployment := range deployments {
 		var failures []common.Failure
 		if *deployment.Spec.Replicas != deployment.Status.Replicas {
 			doc := apiDoc.GetApiDocV2("spec.replicas")
@@ -69,7 +67,9 @@ func (d DeploymentAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 						Unmasked: deployment.Name,
 						Masked:   util.MaskString(deployment.Name),
 					},
-				}})
+				},
+				Severity: common.SeverityWarning,
+			})
 		}
 		if len(failures) > 0 {
 			preAnalysis[fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name)] = common.PreAnalysis{
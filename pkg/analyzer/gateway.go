@@ -69,6 +69,7 @@ func (GatewayAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 						Masked:   util.MaskString(string(gtw.Spec.GatewayClassName)),
 					},
 				},
+				Severity: common.SeverityCritical,
 			})
 		}
 
@@ -91,6 +92,7 @@ func (GatewayAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 						Masked:   util.MaskString(gtwName),
 					},
 				},
+				Severity: common.SeverityCritical,
 			})
 		}
 		if len(failures) > 0 {
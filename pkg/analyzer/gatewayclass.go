@@ -67,6 +67,7 @@ func (GatewayClassAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 						Masked:   util.MaskString(gcName),
 					},
 				},
+				Severity: common.SeverityCritical,
 			})
 		}
 		if len(failures) > 0 {
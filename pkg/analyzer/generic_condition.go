@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const defaultConditionStaleAfter = 10 * time.Minute
+
+// GenericConditionConfiguration is read from generic_condition in the
+// configuration.
+type GenericConditionConfiguration struct {
+	Resources []GenericConditionResource `mapstructure:"resources" yaml:"resources"`
+	// Conditions are the condition types that must be True; defaults to
+	// Ready and Available.
+	Conditions []string `mapstructure:"conditions" yaml:"conditions,omitempty"`
+	// StaleAfter is how long a condition may stay Unknown, or behind the
+	// generation of the object, before it is reported; defaults to 10m.
+	StaleAfter string `mapstructure:"staleafter" yaml:"staleAfter,omitempty"`
+}
+
+// GenericConditionResource is a resource whose status.conditions are
+// analyzed.
+type GenericConditionResource struct {
+	Group         string `mapstructure:"group" yaml:"group,omitempty"`
+	Version       string `mapstructure:"version" yaml:"version"`
+	Resource      string `mapstructure:"resource" yaml:"resource"`
+	ClusterScoped bool   `mapstructure:"clusterscoped" yaml:"clusterScoped,omitempty"`
+}
+
+func (r GenericConditionResource) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+// GenericConditionAnalyzer reports objects of the configured resources whose
+// Ready or Available conditions are False or stale.
+type GenericConditionAnalyzer struct{}
+
+func (GenericConditionAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
+
+	kind := "GenericCondition"
+	AnalyzerErrorsMetric.DeletePartialMatch(map[string]string{
+		"analyzer_name": kind,
+	})
+
+	var config GenericConditionConfiguration
+	if err := viper.UnmarshalKey("generic_condition", &config); err != nil {
+		return nil, fmt.Errorf("reading generic_condition: %w", err)
+	}
+	if len(config.Resources) == 0 {
+		return nil, nil
+	}
+	conditionTypes := config.Conditions
+	if len(conditionTypes) == 0 {
+		conditionTypes = []string{"Ready", "Available"}
+	}
+	staleAfter := defaultConditionStaleAfter
+	if config.StaleAfter != "" {
+		var err error
+		if staleAfter, err = time.ParseDuration(config.StaleAfter); err != nil {
+			return nil, fmt.Errorf("generic_condition: invalid staleAfter: %w", err)
+		}
+	}
+
+	if a.Client.GetDynamicClient() == nil {
+		return nil, errors.New("the GenericCondition analyzer needs a dynamic client")
+	}
+	store := a.GetStore()
+
+	for _, resource := range config.Resources {
+		if resource.Version == "" || resource.Resource == "" {
+			return nil, fmt.Errorf("generic_condition: resource %q needs a version and a resource", resource.gvr())
+		}
+		namespace := a.Namespace
+		if resource.ClusterScoped {
+			namespace = ""
+		}
+		list, err := store.Objects(resource.gvr(), namespace, a.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("generic_condition: listing %s: %w", resource.gvr(), err)
+		}
+
+		for _, item := range list {
+			failures := conditionFailures(item, conditionTypes, staleAfter)
+			if len(failures) == 0 {
+				continue
+			}
+
+			name := item.GetName()
+			if item.GetNamespace() != "" {
+				name = fmt.Sprintf("%s/%s", item.GetNamespace(), item.GetName())
+			}
+			currentAnalysis := common.Result{
+				Kind:  item.GetKind(),
+				Name:  name,
+				Error: failures,
+			}
+			if parent, found := conditionParent(a, item); found {
+				currentAnalysis.ParentObject = parent
+			}
+			a.Results = append(a.Results, currentAnalysis)
+			AnalyzerErrorsMetric.WithLabelValues(kind, item.GetName(), item.GetNamespace()).Set(float64(len(failures)))
+		}
+	}
+	return a.Results, nil
+}
+
+// conditionFailures checks the conditions of the given types on item.
+// Objects without any of them are not reported.
+func conditionFailures(item unstructured.Unstructured, conditionTypes []string, staleAfter time.Duration) []common.Failure {
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	generation := item.GetGeneration()
+	statusGeneration, _, _ := unstructured.NestedInt64(item.Object, "status", "observedGeneration")
+
+	sensitive := []common.Sensitive{
+		{
+			Unmasked: item.GetName(),
+			Masked:   util.MaskString(item.GetName()),
+		},
+	}
+	if item.GetNamespace() != "" {
+		sensitive = append(sensitive, common.Sensitive{
+			Unmasked: item.GetNamespace(),
+			Masked:   util.MaskString(item.GetNamespace()),
+		})
+	}
+
+	var failures []common.Failure
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		if !slices.Contains(conditionTypes, conditionType) {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		observedGeneration, found, _ := unstructured.NestedInt64(condition, "observedGeneration")
+		if !found {
+			observedGeneration = statusGeneration
+		}
+		// Without a transition time a condition is never considered stale.
+		age := time.Duration(0)
+		if value, _, _ := unstructured.NestedString(condition, "lastTransitionTime"); value != "" {
+			if transition, err := time.Parse(time.RFC3339, value); err == nil {
+				age = time.Since(transition)
+			}
+		}
+
+		var text string
+		severity := common.SeverityWarning
+		switch {
+		case status == string(metav1.ConditionFalse):
+			text = fmt.Sprintf("%s %s is not %s: %s", item.GetKind(), item.GetName(), conditionType, conditionReason(reason, message))
+			severity = common.SeverityCritical
+		case status != string(metav1.ConditionTrue) && age > staleAfter:
+			text = fmt.Sprintf("%s %s has reported %s as %s for %s: %s", item.GetKind(), item.GetName(), conditionType, status, age.Round(time.Second), conditionReason(reason, message))
+		case observedGeneration > 0 && observedGeneration < generation && age > staleAfter:
+			text = fmt.Sprintf("The %s condition of %s %s was observed at generation %d, but the object is at generation %d; its controller may not be reconciling it", conditionType, item.GetKind(), item.GetName(), observedGeneration, generation)
+		default:
+			continue
+		}
+		failures = append(failures, common.Failure{
+			Text:      text,
+			Sensitive: sensitive,
+			Severity:  severity,
+		})
+	}
+	return failures
+}
+
+func conditionReason(reason, message string) string {
+	switch {
+	case reason == "" && message == "":
+		return "no reason given"
+	case reason == "":
+		return message
+	case message == "":
+		return reason
+	}
+	return fmt.Sprintf("%s: %s", reason, message)
+}
+
+// conditionParent follows the owner references of item: owners of the core
+// kinds are resolved to their own parents, others are returned as is.
+func conditionParent(a common.Analyzer, item unstructured.Unstructured) (string, bool) {
+	owners := item.GetOwnerReferences()
+	if len(owners) == 0 {
+		return "", false
+	}
+	if a.Client.GetClient() != nil {
+		parent, found := util.GetParentFromStore(a.GetStore(), metav1.ObjectMeta{
+			Namespace:       item.GetNamespace(),
+			OwnerReferences: owners,
+		})
+		if found {
+			return parent, true
+		}
+	}
+	owner := owners[0]
+	for _, o := range owners {
+		if o.Controller != nil && *o.Controller {
+			owner = o
+			break
+		}
+	}
+	return fmt.Sprintf("%s/%s", owner.Kind, owner.Name), true
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func certificate(name string, generation, observedGeneration int64, owners []interface{}, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	items := make([]interface{}, 0, len(conditions))
+	for _, condition := range conditions {
+		items = append(items, condition)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       "default",
+			"generation":      generation,
+			"ownerReferences": owners,
+		},
+		"status": map[string]interface{}{
+			"observedGeneration": observedGeneration,
+			"conditions":         items,
+		},
+	}}
+}
+
+func condition(conditionType, status string, age time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             status,
+		"reason":             "Pending",
+		"message":            "issuer not found",
+		"lastTransitionTime": time.Now().Add(-age).UTC().Format(time.RFC3339),
+	}
+}
+
+func newGenericConditionConfig() common.Analyzer {
+	controller := true
+	objects := []runtime.Object{
+		certificate("healthy", 1, 1, nil, condition("Ready", "True", time.Hour)),
+		certificate("failing", 1, 1, []interface{}{
+			map[string]interface{}{"apiVersion": "networking.k8s.io/v1", "kind": "Ingress", "name": "web", "uid": "1", "controller": controller},
+		}, condition("Ready", "False", time.Minute)),
+		certificate("unknown", 1, 1, nil, condition("Ready", "Unknown", time.Hour)),
+		certificate("pending", 1, 1, nil, condition("Ready", "Unknown", time.Minute)),
+		certificate("outdated", 3, 2, []interface{}{
+			map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-1", "uid": "2", "controller": controller},
+		}, condition("Ready", "True", time.Hour)),
+		certificate("other", 1, 1, nil, condition("Issuing", "False", time.Hour)),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}: "CertificateList",
+	}, objects...)
+	clientset := fake.NewSimpleClientset(&appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "web"},
+			},
+		},
+	}, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+	})
+	return common.Analyzer{
+		Client:    &kubernetes.Client{Client: clientset, DynamicClient: client},
+		Context:   context.Background(),
+		Namespace: "default",
+	}
+}
+
+func TestGenericConditionAnalyzer(t *testing.T) {
+	viper.Set("generic_condition", map[string]interface{}{
+		"resources": []map[string]interface{}{
+			{"group": "cert-manager.io", "version": "v1", "resource": "certificates"},
+		},
+		"staleAfter": "30m",
+	})
+	defer viper.Set("generic_condition", nil)
+
+	results, err := GenericConditionAnalyzer{}.Analyze(newGenericConditionConfig())
+	require.NoError(t, err)
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	require.Len(t, results, 3)
+
+	require.Equal(t, "default/failing", results[0].Name)
+	require.Equal(t, "Certificate", results[0].Kind)
+	require.Equal(t, "Ingress/web", results[0].ParentObject)
+	require.Equal(t, "Certificate failing is not Ready: Pending: issuer not found", results[0].Error[0].Text)
+	require.Equal(t, common.SeverityCritical, results[0].Error[0].Severity)
+	require.Equal(t, "failing", results[0].Error[0].Sensitive[0].Unmasked)
+
+	require.Equal(t, "default/outdated", results[1].Name)
+	require.Equal(t, "Deployment/web", results[1].ParentObject)
+	require.Contains(t, results[1].Error[0].Text, "observed at generation 2")
+
+	require.Equal(t, "default/unknown", results[2].Name)
+	require.Empty(t, results[2].ParentObject)
+	require.Equal(t, common.SeverityWarning, results[2].Error[0].Severity)
+}
+
+func TestGenericConditionAnalyzerConditions(t *testing.T) {
+	viper.Set("generic_condition", map[string]interface{}{
+		"resources": []map[string]interface{}{
+			{"group": "cert-manager.io", "version": "v1", "resource": "certificates"},
+		},
+		"conditions": []string{"Issuing"},
+	})
+	defer viper.Set("generic_condition", nil)
+
+	results, err := GenericConditionAnalyzer{}.Analyze(newGenericConditionConfig())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "default/other", results[0].Name)
+}
+
+func TestGenericConditionAnalyzerNotConfigured(t *testing.T) {
+	results, err := GenericConditionAnalyzer{}.Analyze(newGenericConditionConfig())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	viper.Set("generic_condition", map[string]interface{}{
+		"resources":  []map[string]interface{}{{"resource": "certificates"}},
+		"staleAfter": "soon",
+	})
+	defer viper.Set("generic_condition", nil)
+	_, err = GenericConditionAnalyzer{}.Analyze(newGenericConditionConfig())
+	require.ErrorContains(t, err, "invalid staleAfter")
+}
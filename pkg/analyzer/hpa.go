@@ -21,7 +21,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -43,17 +42,17 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		"analyzer_name": kind,
 	})
 
-	list, err := a.Client.GetClient().AutoscalingV2().HorizontalPodAutoscalers(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	list, err := store.HorizontalPodAutoscalers(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, hpa := range list.Items {
+	for _, hpa := range list {
 		var failures []common.Failure
 
-	
 		//check the error from status field
 		conditions := hpa.Status.Conditions
 		for _, condition := range conditions {
@@ -61,6 +60,7 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 				failures = append(failures, common.Failure{
 					Text:      condition.Message,
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityWarning,
 				})
 			}
 		}
@@ -71,22 +71,22 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 
 		switch scaleTargetRef.Kind {
 		case "Deployment":
-			deployment, err := a.Client.GetClient().AppsV1().Deployments(hpa.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			deployment, err := store.Deployment(hpa.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = DeploymentInfo{deployment}
 			}
 		case "ReplicationController":
-			rc, err := a.Client.GetClient().CoreV1().ReplicationControllers(hpa.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			rc, err := store.ReplicationController(hpa.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = ReplicationControllerInfo{rc}
 			}
 		case "ReplicaSet":
-			rs, err := a.Client.GetClient().AppsV1().ReplicaSets(hpa.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			rs, err := store.ReplicaSet(hpa.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = ReplicaSetInfo{rs}
 			}
 		case "StatefulSet":
-			ss, err := a.Client.GetClient().AppsV1().StatefulSets(hpa.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			ss, err := store.StatefulSet(hpa.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = StatefulSetInfo{ss}
 			}
@@ -94,6 +94,7 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			failures = append(failures, common.Failure{
 				Text:      fmt.Sprintf("HorizontalPodAutoscaler uses %s as ScaleTargetRef which is not an option.", scaleTargetRef.Kind),
 				Sensitive: []common.Sensitive{},
+				Severity:  common.SeverityCritical,
 			})
 		}
 
@@ -109,6 +110,7 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 						Masked:   util.MaskString(scaleTargetRef.Name),
 					},
 				},
+				Severity: common.SeverityCritical,
 			})
 		} else {
 			containers := len(podInfo.GetPodSpec().Containers)
@@ -130,6 +132,7 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(scaleTargetRef.Name),
 						},
 					},
+					Severity: common.SeverityWarning,
 				})
 			}
 
@@ -152,7 +155,7 @@ func (HpaAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.HorizontalPodAutoscalers.ObjectMeta)
+		parent, found := util.GetParentFromStore(store, value.HorizontalPodAutoscalers.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -79,6 +79,7 @@ func (HTTPRouteAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(gtw.Name),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 			} else {
 				// Check if the aforementioned Gateway allows the HTTPRoutes from the route's namespace
@@ -113,6 +114,7 @@ func (HTTPRouteAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 											Masked:   util.MaskString(gtw.Name),
 										},
 									},
+									Severity: common.SeverityCritical,
 								})
 							}
 						case *allow == gtwapi.NamespacesFromSelector:
@@ -144,6 +146,7 @@ func (HTTPRouteAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 											Masked:   util.MaskString(gtw.Name),
 										},
 									},
+									Severity: common.SeverityCritical,
 								})
 
 							}
@@ -175,6 +178,7 @@ func (HTTPRouteAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 								Masked:   util.MaskString(service.Name),
 							},
 						},
+						Severity: common.SeverityCritical,
 					})
 				} else {
 					portMatch := false
@@ -206,6 +210,7 @@ func (HTTPRouteAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 									Masked:   service.Namespace,
 								},
 							},
+							Severity: common.SeverityCritical,
 						})
 					}
 				}
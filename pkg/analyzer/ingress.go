@@ -41,14 +41,15 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		"analyzer_name": kind,
 	})
 
-	list, err := a.Client.GetClient().NetworkingV1().Ingresses(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	list, err := store.Ingresses(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, ing := range list.Items {
+	for _, ing := range list {
 		var failures []common.Failure
 
 		// get ingressClassName
@@ -71,6 +72,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(ing.Name),
 						},
 					},
+					Severity: common.SeverityWarning,
 				})
 			} else {
 				ingressClassName = &ingClassValue
@@ -79,7 +81,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 
 		// check if ingressclass exist
 		if ingressClassName != nil {
-			_, err := a.Client.GetClient().NetworkingV1().IngressClasses().Get(a.Context, *ingressClassName, metav1.GetOptions{})
+			_, err := store.IngressClass(*ingressClassName)
 			if err != nil {
 				doc := apiDoc.GetApiDocV2("spec.ingressClassName")
 
@@ -92,6 +94,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(*ingressClassName),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 			}
 		}
@@ -101,7 +104,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			// loop over HTTP paths
 			if rule.HTTP != nil {
 				for _, path := range rule.HTTP.Paths {
-					_, err := a.Client.GetClient().CoreV1().Services(ing.Namespace).Get(a.Context, path.Backend.Service.Name, metav1.GetOptions{})
+					_, err := store.Service(ing.Namespace, path.Backend.Service.Name)
 					if err != nil {
 						doc := apiDoc.GetApiDocV2("spec.rules.http.paths.backend.service")
 
@@ -118,12 +121,15 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 									Masked:   util.MaskString(path.Backend.Service.Name),
 								},
 							},
+							Severity: common.SeverityCritical,
 						})
 					}
 				}
 			}
 		}
 
+		// Secrets are fetched one by one rather than listed into the store,
+		// which would read the data of every secret of the namespace.
 		for _, tls := range ing.Spec.TLS {
 			_, err := a.Client.GetClient().CoreV1().Secrets(ing.Namespace).Get(a.Context, tls.SecretName, metav1.GetOptions{})
 			if err != nil {
@@ -142,6 +148,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(tls.SecretName),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 			}
 		}
@@ -163,7 +170,7 @@ func (IngressAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.Ingress.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.Ingress.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
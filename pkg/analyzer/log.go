@@ -21,7 +21,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -41,14 +40,14 @@ func (LogAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	})
 
 	// search all namespaces for pods that are not running
-	list, err := a.Client.GetClient().CoreV1().Pods(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	list, err := a.GetStore().Pods(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 	// Iterate through each pod
 
-	for _, pod := range list.Items {
+	for _, pod := range list {
 		podName := pod.Name
 		for _, c := range pod.Spec.Containers {
 			var failures []common.Failure
@@ -66,6 +65,7 @@ func (LogAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(pod.Name),
 						},
 					},
+					Severity: common.SeverityWarning,
 				})
 			} else {
 				rawlogs := string(podLogs)
@@ -78,6 +78,7 @@ func (LogAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 								Masked:   util.MaskString(pod.Name),
 							},
 						},
+						Severity: common.SeverityWarning,
 					})
 				}
 			}
@@ -96,7 +97,7 @@ func (LogAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Name:  key,
 			Error: value.FailureDetails,
 		}
-		parent, found := util.GetParent(a.Client, value.Pod.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.Pod.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -14,13 +14,11 @@ limitations under the License.
 package analyzer
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -42,14 +40,15 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 		"analyzer_name": kind,
 	})
 
-	mutatingWebhooks, err := a.Client.GetClient().AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.Background(), v1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	mutatingWebhooks, err := store.MutatingWebhookConfigurations(a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, webhookConfig := range mutatingWebhooks.Items {
+	for _, webhookConfig := range mutatingWebhooks {
 		for _, webhook := range webhookConfig.Webhooks {
 			var failures []common.Failure
 
@@ -58,7 +57,7 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 			}
 			svc := webhook.ClientConfig.Service
 			// Get the service
-			service, err := a.Client.GetClient().CoreV1().Services(svc.Namespace).Get(context.Background(), svc.Name, v1.GetOptions{})
+			service, err := store.Service(svc.Namespace, svc.Name)
 			if err != nil {
 				// If the service is not found, we can't check the pods
 				failures = append(failures, common.Failure{
@@ -74,6 +73,7 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 							Masked:   util.MaskString(svc.Name),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 				preAnalysis[fmt.Sprintf("%s/%s", webhookConfig.Namespace, webhook.Name)] = common.PreAnalysis{
 					MutatingWebhook: webhookConfig,
@@ -88,14 +88,12 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 				continue
 			}
 			// Get pods within service
-			pods, err := a.Client.GetClient().CoreV1().Pods(svc.Namespace).List(context.Background(), v1.ListOptions{
-				LabelSelector: util.MapToString(service.Spec.Selector),
-			})
+			pods, err := store.Pods(svc.Namespace, util.MapToString(service.Spec.Selector))
 			if err != nil {
 				return nil, err
 			}
 
-			if len(pods.Items) == 0 {
+			if len(pods) == 0 {
 				failures = append(failures, common.Failure{
 					Text:          fmt.Sprintf("No active pods found within service %s as mapped to by Mutating Webhook %s", svc.Name, webhook.Name),
 					KubernetesDoc: apiDoc.GetApiDocV2("spec.webhook.clientConfig.service"),
@@ -105,10 +103,11 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 							Masked:   util.MaskString(webhookConfig.Namespace),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 
 			}
-			for _, pod := range pods.Items {
+			for _, pod := range pods {
 				if pod.Status.Phase != "Running" {
 					doc := apiDoc.GetApiDocV2("spec.webhook")
 					failures = append(failures, common.Failure{
@@ -132,6 +131,7 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 								Masked:   util.MaskString(pod.Name),
 							},
 						},
+						Severity: common.SeverityCritical,
 					})
 				}
 			}
@@ -151,7 +151,7 @@ func (MutatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.MutatingWebhook.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.MutatingWebhook.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
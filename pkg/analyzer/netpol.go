@@ -19,7 +19,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -42,15 +41,14 @@ func (NetworkPolicyAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 	})
 
 	// get all network policies in the namespace
-	policies, err := a.Client.GetClient().NetworkingV1().
-		NetworkPolicies(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	policies, err := a.GetStore().NetworkPolicies(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, policy := range policies.Items {
+	for _, policy := range policies {
 		var failures []common.Failure
 
 		// Check if policy allows traffic to all pods in the namespace
@@ -66,14 +64,15 @@ func (NetworkPolicyAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 						Masked:   util.MaskString(policy.Name),
 					},
 				},
+				Severity: common.SeverityInfo,
 			})
 		} else {
 			// Check if policy is not applied to any pods
-			podList, err := util.GetPodListByLabels(a.Client.GetClient(), a.Namespace, policy.Spec.PodSelector.MatchLabels)
+			podList, err := a.GetStore().Pods(a.Namespace, util.MapToString(policy.Spec.PodSelector.MatchLabels))
 			if err != nil {
 				return nil, err
 			}
-			if len(podList.Items) == 0 {
+			if len(podList) == 0 {
 				failures = append(failures, common.Failure{
 					Text: fmt.Sprintf("Network policy is not applied to any pods: %s", policy.Name),
 					Sensitive: []common.Sensitive{
@@ -82,6 +81,7 @@ func (NetworkPolicyAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error)
 							Masked:   util.MaskString(policy.Name),
 						},
 					},
+					Severity: common.SeverityInfo,
 				})
 			}
 		}
@@ -20,7 +20,6 @@ import (
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type NodeAnalyzer struct{}
@@ -33,14 +32,14 @@ func (NodeAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		"analyzer_name": kind,
 	})
 
-	list, err := a.Client.GetClient().CoreV1().Nodes().List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	list, err := a.GetStore().Nodes(a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, node := range list.Items {
+	for _, node := range list {
 		var failures []common.Failure
 		for _, nodeCondition := range node.Status.Conditions {
 			// https://kubernetes.io/docs/concepts/architecture/nodes/#condition
@@ -49,13 +48,13 @@ func (NodeAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 				if nodeCondition.Status == v1.ConditionTrue {
 					break
 				}
-				failures = addNodeConditionFailure(failures, node.Name, nodeCondition)
+				failures = addNodeConditionFailure(failures, node.Name, nodeCondition, common.SeverityCritical)
 			// k3s `EtcdIsVoter`` should not be reported as an error
 			case v1.NodeConditionType("EtcdIsVoter"):
 				break
 			default:
 				if nodeCondition.Status != v1.ConditionFalse {
-					failures = addNodeConditionFailure(failures, node.Name, nodeCondition)
+					failures = addNodeConditionFailure(failures, node.Name, nodeCondition, common.SeverityWarning)
 				}
 			}
 		}
@@ -77,7 +76,7 @@ func (NodeAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.Node.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.Node.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -87,7 +86,7 @@ func (NodeAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	return a.Results, err
 }
 
-func addNodeConditionFailure(failures []common.Failure, nodeName string, nodeCondition v1.NodeCondition) []common.Failure {
+func addNodeConditionFailure(failures []common.Failure, nodeName string, nodeCondition v1.NodeCondition, severity common.Severity) []common.Failure {
 	failures = append(failures, common.Failure{
 		Text: fmt.Sprintf("%s has condition of type %s, reason %s: %s", nodeName, nodeCondition.Type, nodeCondition.Reason, nodeCondition.Message),
 		Sensitive: []common.Sensitive{
@@ -96,6 +95,7 @@ func addNodeConditionFailure(failures []common.Failure, nodeName string, nodeCon
 				Masked:   util.MaskString(nodeName),
 			},
 		},
+		Severity: severity,
 	})
 	return failures
 }
@@ -19,7 +19,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -41,14 +40,14 @@ func (PdbAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		"analyzer_name": kind,
 	})
 
-	list, err := a.Client.GetClient().PolicyV1().PodDisruptionBudgets(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	list, err := a.GetStore().PodDisruptionBudgets(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, pdb := range list.Items {
+	for _, pdb := range list {
 		var failures []common.Failure
 
 		// Before accessing the Conditions, check if they exist or not.
@@ -78,6 +77,7 @@ func (PdbAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 								Masked:   util.MaskString(v),
 							},
 						},
+						Severity: common.SeverityWarning,
 					})
 				}
 			}
@@ -99,7 +99,7 @@ func (PdbAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.PodDisruptionBudget.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.PodDisruptionBudget.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -19,7 +19,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type PodAnalyzer struct {
@@ -34,15 +33,13 @@ func (PodAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	})
 
 	// search all namespaces for pods that are not running
-	list, err := a.Client.GetClient().CoreV1().Pods(a.Namespace).List(a.Context, metav1.ListOptions{
-		LabelSelector: a.LabelSelector,
-	})
+	list, err := a.GetStore().Pods(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, pod := range list.Items {
+	for _, pod := range list {
 		var failures []common.Failure
 
 		// Check for pending pods
@@ -54,6 +51,7 @@ func (PodAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 						failures = append(failures, common.Failure{
 							Text:      containerStatus.Message,
 							Sensitive: []common.Sensitive{},
+							Severity:  common.SeverityCritical,
 						})
 					}
 				}
@@ -82,7 +80,7 @@ func (PodAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.Pod.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.Pod.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -101,7 +99,7 @@ func analyzeContainerStatusFailures(a common.Analyzer, statuses []v1.ContainerSt
 			if containerStatus.State.Waiting.Reason == "ContainerCreating" && statusPhase == "Pending" {
 				// This represents a container that is still being created or blocked due to conditions such as OOMKilled
 				// parse the event log and append details
-				evt, err := util.FetchLatestEvent(a.Context, a.Client, namespace, name)
+				evt, err := a.GetStore().LatestEvent(namespace, name)
 				if err != nil || evt == nil {
 					continue
 				}
@@ -109,6 +107,7 @@ func analyzeContainerStatusFailures(a common.Analyzer, statuses []v1.ContainerSt
 					failures = append(failures, common.Failure{
 						Text:      evt.Message,
 						Sensitive: []common.Sensitive{},
+						Severity:  common.SeverityCritical,
 					})
 				}
 			} else if containerStatus.State.Waiting.Reason == "CrashLoopBackOff" && containerStatus.LastTerminationState.Terminated != nil {
@@ -116,18 +115,20 @@ func analyzeContainerStatusFailures(a common.Analyzer, statuses []v1.ContainerSt
 				failures = append(failures, common.Failure{
 					Text:      fmt.Sprintf("the last termination reason is %s container=%s pod=%s", containerStatus.LastTerminationState.Terminated.Reason, containerStatus.Name, name),
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityCritical,
 				})
 			} else if isErrorReason(containerStatus.State.Waiting.Reason) && containerStatus.State.Waiting.Message != "" {
 				failures = append(failures, common.Failure{
 					Text:      containerStatus.State.Waiting.Message,
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityCritical,
 				})
 			}
 		} else {
 			// when pod is Running but its ReadinessProbe fails
 			if !containerStatus.Ready && statusPhase == "Running" {
 				// parse the event log and append details
-				evt, err := util.FetchLatestEvent(a.Context, a.Client, namespace, name)
+				evt, err := a.GetStore().LatestEvent(namespace, name)
 				if err != nil || evt == nil {
 					continue
 				}
@@ -135,6 +136,7 @@ func analyzeContainerStatusFailures(a common.Analyzer, statuses []v1.ContainerSt
 					failures = append(failures, common.Failure{
 						Text:      evt.Message,
 						Sensitive: []common.Sensitive{},
+						Severity:  common.SeverityWarning,
 					})
 				}
 			}
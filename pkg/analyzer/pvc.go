@@ -19,7 +19,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	appsv1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type PvcAnalyzer struct{}
@@ -33,21 +32,21 @@ func (PvcAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	})
 
 	// search all namespaces for pods that are not running
-	list, err := a.Client.GetClient().CoreV1().PersistentVolumeClaims(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	list, err := a.GetStore().PersistentVolumeClaims(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, pvc := range list.Items {
+	for _, pvc := range list {
 		var failures []common.Failure
 
 		// Check for empty rs
 		if pvc.Status.Phase == appsv1.ClaimPending {
 
 			// parse the event log and append details
-			evt, err := util.FetchLatestEvent(a.Context, a.Client, pvc.Namespace, pvc.Name)
+			evt, err := a.GetStore().LatestEvent(pvc.Namespace, pvc.Name)
 			if err != nil || evt == nil {
 				continue
 			}
@@ -55,6 +54,7 @@ func (PvcAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 				failures = append(failures, common.Failure{
 					Text:      evt.Message,
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityCritical,
 				})
 			}
 		}
@@ -74,7 +74,7 @@ func (PvcAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.PersistentVolumeClaim.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.PersistentVolumeClaim.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -43,6 +43,10 @@ func TestPersistentVolumeClaimAnalyzer(t *testing.T) {
 								Name:      "Event1",
 								Namespace: "default",
 							},
+							InvolvedObject: appsv1.ObjectReference{
+								Kind: "PersistentVolumeClaim",
+								Name: "PVC1",
+							},
 							LastTimestamp: metav1.Time{
 								Time: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
 							},
@@ -57,11 +61,14 @@ func TestPersistentVolumeClaimAnalyzer(t *testing.T) {
 							},
 						},
 						&appsv1.Event{
-							// This is the latest event.
 							ObjectMeta: metav1.ObjectMeta{
 								Name:      "Event3",
 								Namespace: "default",
 							},
+							InvolvedObject: appsv1.ObjectReference{
+								Kind: "PersistentVolumeClaim",
+								Name: "PVC5",
+							},
 							LastTimestamp: metav1.Time{
 								Time: time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC),
 							},
@@ -157,6 +164,10 @@ func TestPersistentVolumeClaimAnalyzer(t *testing.T) {
 								Name:      "Event1",
 								Namespace: "default",
 							},
+							InvolvedObject: appsv1.ObjectReference{
+								Kind: "PersistentVolumeClaim",
+								Name: "PVC1",
+							},
 							// Any reason other than ProvisioningFailed won't result in failure.
 							Reason: "UnknownReason",
 						},
@@ -185,6 +196,10 @@ func TestPersistentVolumeClaimAnalyzer(t *testing.T) {
 								Name:      "Event1",
 								Namespace: "default",
 							},
+							InvolvedObject: appsv1.ObjectReference{
+								Kind: "PersistentVolumeClaim",
+								Name: "PVC1",
+							},
 							// Event without any error message won't result in failure.
 							Reason: "ProvisioningFailed",
 						},
@@ -238,6 +253,10 @@ func TestPvcAnalyzerLabelSelectorFiltering(t *testing.T) {
 						Name:      "Event1",
 						Namespace: "default",
 					},
+					InvolvedObject: appsv1.ObjectReference{
+						Kind: "PersistentVolumeClaim",
+						Name: "PVC1",
+					},
 					LastTimestamp: metav1.Time{
 						Time: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
 					},
@@ -18,7 +18,6 @@ import (
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ReplicaSetAnalyzer struct{}
@@ -32,14 +31,14 @@ func (ReplicaSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	})
 
 	// search all namespaces for pods that are not running
-	list, err := a.Client.GetClient().AppsV1().ReplicaSets(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	list, err := a.GetStore().ReplicaSets(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, rs := range list.Items {
+	for _, rs := range list {
 		var failures []common.Failure
 
 		// Check for empty rs
@@ -51,6 +50,7 @@ func (ReplicaSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 					failures = append(failures, common.Failure{
 						Text:      rsStatus.Message,
 						Sensitive: []common.Sensitive{},
+						Severity:  common.SeverityCritical,
 					})
 
 				}
@@ -72,7 +72,7 @@ func (ReplicaSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.ReplicaSet.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.ReplicaSet.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
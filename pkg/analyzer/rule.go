@@ -0,0 +1,311 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Rule is a custom analyzer declared in the configuration under
+// rule_analyzers. Objects of the target kind are reported when all of its
+// CEL conditions evaluate to true.
+type Rule struct {
+	// Name is the filter name of the analyzer.
+	Name       string `mapstructure:"name" yaml:"name"`
+	APIVersion string `mapstructure:"apiversion" yaml:"apiVersion"`
+	Kind       string `mapstructure:"kind" yaml:"kind"`
+	// Resource is the plural resource of Kind; it is guessed from Kind when
+	// empty.
+	Resource string `mapstructure:"resource" yaml:"resource,omitempty"`
+	// ClusterScoped must be set for kinds that are not namespaced.
+	ClusterScoped bool   `mapstructure:"clusterscoped" yaml:"clusterScoped,omitempty"`
+	LabelSelector string `mapstructure:"labelselector" yaml:"labelSelector,omitempty"`
+	// Conditions are CEL expressions over the variable object. Conditions
+	// that cannot be evaluated, e.g. because a field is missing, do not
+	// match; use has() to test for optional fields.
+	Conditions []string `mapstructure:"conditions" yaml:"conditions"`
+	// Message is a text/template over the object, e.g.
+	// "{{ .metadata.name }} has no team label".
+	Message  string `mapstructure:"message" yaml:"message"`
+	Severity string `mapstructure:"severity" yaml:"severity,omitempty"`
+}
+
+// LoadRules reads the rule analyzers from the configuration.
+func LoadRules() ([]Rule, error) {
+	var rules []Rule
+	if err := viper.UnmarshalKey("rule_analyzers", &rules); err != nil {
+		return nil, fmt.Errorf("reading rule_analyzers: %w", err)
+	}
+	return rules, nil
+}
+
+// Validate checks the fields of r, without compiling its conditions.
+func (r Rule) Validate() error {
+	switch {
+	case r.Name == "":
+		return errors.New("rule analyzer has no name")
+	case r.APIVersion == "" || r.Kind == "":
+		return fmt.Errorf("rule analyzer %s has no apiVersion or kind", r.Name)
+	case len(r.Conditions) == 0:
+		return fmt.Errorf("rule analyzer %s has no conditions", r.Name)
+	case r.Message == "":
+		return fmt.Errorf("rule analyzer %s has no message", r.Name)
+	}
+	if r.Severity != "" {
+		if _, err := common.ParseSeverity(r.Severity); err != nil {
+			return fmt.Errorf("rule analyzer %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) resource() (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(r.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("rule analyzer %s: %w", r.Name, err)
+	}
+	if r.Resource != "" {
+		return gv.WithResource(r.Resource), nil
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(r.Kind))
+	return gvr, nil
+}
+
+// RuleAnalyzer evaluates a Rule in-process through the dynamic client.
+type RuleAnalyzer struct {
+	Rule Rule
+
+	// The checked conditions, message and resource of Rule, set by
+	// NewRuleAnalyzer. Analyzers built without it compile Rule on every run.
+	conditions []*cel.Ast
+	message    *template.Template
+	gvr        schema.GroupVersionResource
+}
+
+// NewRuleAnalyzer validates rule and compiles its conditions and message.
+func NewRuleAnalyzer(rule Rule) (RuleAnalyzer, error) {
+	if err := rule.Validate(); err != nil {
+		return RuleAnalyzer{}, err
+	}
+	conditions, err := compileConditions(rule)
+	if err != nil {
+		return RuleAnalyzer{}, err
+	}
+	message, err := template.New(rule.Name).Parse(rule.Message)
+	if err != nil {
+		return RuleAnalyzer{}, fmt.Errorf("rule analyzer %s: parsing message: %w", rule.Name, err)
+	}
+	gvr, err := rule.resource()
+	if err != nil {
+		return RuleAnalyzer{}, err
+	}
+	return RuleAnalyzer{Rule: rule, conditions: conditions, message: message, gvr: gvr}, nil
+}
+
+func (r RuleAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
+	if r.conditions == nil {
+		compiled, err := NewRuleAnalyzer(r.Rule)
+		if err != nil {
+			return nil, err
+		}
+		r = compiled
+	}
+	rule, message, gvr := r.Rule, r.message, r.gvr
+	if a.Client.GetDynamicClient() == nil {
+		return nil, fmt.Errorf("rule analyzer %s needs a dynamic client", rule.Name)
+	}
+	store := a.GetStore()
+
+	// Programs are bound to the lister of this run, which reads each kind
+	// and namespace from the store of the run.
+	lister := &objectLister{store: store, objects: map[string][]interface{}{}}
+	env, err := conditionEnv(lister)
+	if err != nil {
+		return nil, err
+	}
+	programs := make([]cel.Program, 0, len(r.conditions))
+	for _, ast := range r.conditions {
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("rule analyzer %s: %w", rule.Name, err)
+		}
+		programs = append(programs, program)
+	}
+
+	AnalyzerErrorsMetric.DeletePartialMatch(map[string]string{
+		"analyzer_name": rule.Name,
+	})
+
+	// The label selectors of the rule and of the analysis must both match.
+	selectors := []string{}
+	for _, selector := range []string{rule.LabelSelector, a.LabelSelector} {
+		if selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+	namespace := a.Namespace
+	if rule.ClusterScoped {
+		namespace = ""
+	}
+	list, err := store.Objects(gvr, namespace, strings.Join(selectors, ","))
+	if err != nil {
+		return nil, fmt.Errorf("rule analyzer %s: listing %s: %w", rule.Name, gvr.Resource, err)
+	}
+
+	var results []common.Result
+	for _, item := range list {
+		if !matchesAll(programs, item.Object) {
+			continue
+		}
+
+		var text bytes.Buffer
+		if err := message.Execute(&text, item.Object); err != nil {
+			text.Reset()
+			fmt.Fprintf(&text, "%s %s matches rule %s", rule.Kind, item.GetName(), rule.Name)
+		}
+		failure := common.Failure{
+			Text:     text.String(),
+			Severity: common.Severity(strings.ToLower(rule.Severity)),
+			Sensitive: []common.Sensitive{
+				{
+					Unmasked: item.GetName(),
+					Masked:   util.MaskString(item.GetName()),
+				},
+			},
+		}
+		name := item.GetName()
+		if item.GetNamespace() != "" {
+			name = fmt.Sprintf("%s/%s", item.GetNamespace(), item.GetName())
+			failure.Sensitive = append(failure.Sensitive, common.Sensitive{
+				Unmasked: item.GetNamespace(),
+				Masked:   util.MaskString(item.GetNamespace()),
+			})
+		}
+		results = append(results, common.Result{
+			Kind:  rule.Kind,
+			Name:  name,
+			Error: []common.Failure{failure},
+		})
+		AnalyzerErrorsMetric.WithLabelValues(rule.Name, item.GetName(), item.GetNamespace()).Set(1)
+	}
+	return results, nil
+}
+
+// conditionEnv declares the variables and functions of conditions. Besides
+// the variable object, they may call list(apiVersion, kind, namespace) to
+// look up other objects, e.g. the PodDisruptionBudgets of a namespace; an
+// empty namespace lists every namespace. The list function is only bound to
+// lister when it is set, which is enough to check conditions.
+func conditionEnv(lister *objectLister) (*cel.Env, error) {
+	var bindings []cel.OverloadOpt
+	if lister != nil {
+		bindings = append(bindings, cel.FunctionBinding(lister.list))
+	}
+	return cel.NewEnv(
+		cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Function("list",
+			cel.Overload("list_string_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType, cel.StringType},
+				cel.ListType(cel.DynType),
+				bindings...,
+			),
+		),
+	)
+}
+
+// compileConditions parses and type-checks the conditions of rule.
+func compileConditions(rule Rule) ([]*cel.Ast, error) {
+	env, err := conditionEnv(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]*cel.Ast, 0, len(rule.Conditions))
+	for _, condition := range rule.Conditions {
+		ast, issues := env.Compile(condition)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("rule analyzer %s: compiling %q: %w", rule.Name, condition, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("rule analyzer %s: condition %q must be a boolean, not %s", rule.Name, condition, ast.OutputType())
+		}
+		conditions = append(conditions, ast)
+	}
+	return conditions, nil
+}
+
+func matchesAll(programs []cel.Program, object map[string]interface{}) bool {
+	for _, program := range programs {
+		out, _, err := program.Eval(map[string]interface{}{"object": object})
+		if err != nil {
+			return false
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// objectLister serves the list function of conditions, listing each kind
+// and namespace once per analysis.
+type objectLister struct {
+	store   *kubernetes.Store
+	mu      sync.Mutex
+	objects map[string][]interface{}
+}
+
+func (l *objectLister) list(args ...ref.Val) ref.Val {
+	apiVersion, _ := args[0].Value().(string)
+	kind, _ := args[1].Value().(string)
+	namespace, _ := args[2].Value().(string)
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return types.NewErr("list: %v", err)
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(kind))
+	key := gvr.String() + "/" + namespace
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	objects, ok := l.objects[key]
+	if !ok {
+		list, err := l.store.Objects(gvr, namespace, "")
+		if err != nil {
+			return types.NewErr("list %s %s: %v", apiVersion, kind, err)
+		}
+		objects = make([]interface{}, 0, len(list))
+		for _, item := range list {
+			objects = append(objects, item.Object)
+		}
+		l.objects[key] = objects
+	}
+	return types.DefaultTypeAdapter.NativeToValue(objects)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func newRuleAnalyzerConfig() common.Analyzer {
+	labels := map[string]string{"app": "web"}
+	objects := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"team": "a"}},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}}},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "other"},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}:            "DeploymentList",
+		{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}: "PodDisruptionBudgetList",
+	}, objects...)
+	return common.Analyzer{
+		Client:    &kubernetes.Client{DynamicClient: client},
+		Context:   context.Background(),
+		Namespace: "default",
+	}
+}
+
+func TestRuleAnalyzer(t *testing.T) {
+	results, err := RuleAnalyzer{Rule: Rule{
+		Name:       "RequireTeamLabel",
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Conditions: []string{`!has(object.metadata.labels) || !("team" in object.metadata.labels)`},
+		Message:    "Deployment {{ .metadata.name }} has no team label",
+		Severity:   "Critical",
+	}}.Analyze(newRuleAnalyzerConfig())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "default/api", results[0].Name)
+	require.Equal(t, "Deployment", results[0].Kind)
+	require.Equal(t, "Deployment api has no team label", results[0].Error[0].Text)
+	require.Equal(t, common.SeverityCritical, results[0].Error[0].Severity)
+	require.Equal(t, "api", results[0].Error[0].Sensitive[0].Unmasked)
+}
+
+func TestRuleAnalyzerLookup(t *testing.T) {
+	results, err := RuleAnalyzer{Rule: Rule{
+		Name:       "MissingPodDisruptionBudget",
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Conditions: []string{`!list("policy/v1", "PodDisruptionBudget", object.metadata.namespace).exists(pdb,
+			pdb.spec.selector.matchLabels.all(k, k in object.spec.template.metadata.labels &&
+				object.spec.template.metadata.labels[k] == pdb.spec.selector.matchLabels[k]))`},
+		Message: "Deployment {{ .metadata.name }} has no PodDisruptionBudget",
+	}}.Analyze(newRuleAnalyzerConfig())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "default/api", results[0].Name)
+}
+
+func TestRuleAnalyzerSelectors(t *testing.T) {
+	config := newRuleAnalyzerConfig()
+	config.Namespace = ""
+	rule := Rule{
+		Name:          "Teams",
+		APIVersion:    "apps/v1",
+		Kind:          "Deployment",
+		LabelSelector: "team=a",
+		Conditions:    []string{"true"},
+		Message:       "{{ .metadata.name }}",
+	}
+	results, err := RuleAnalyzer{Rule: rule}.Analyze(config)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "default/web", results[0].Name)
+
+	// Conditions that cannot be evaluated do not match.
+	rule.LabelSelector = ""
+	rule.Conditions = []string{"object.spec.replicas > 0"}
+	results, err = RuleAnalyzer{Rule: rule}.Analyze(config)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestRuleAnalyzerInvalid(t *testing.T) {
+	valid := Rule{Name: "Rule", APIVersion: "apps/v1", Kind: "Deployment", Conditions: []string{"true"}, Message: "m"}
+
+	for name, tc := range map[string]struct {
+		update func(*Rule)
+		err    string
+	}{
+		"syntax":   {func(r *Rule) { r.Conditions = []string{"object.metadata.("} }, "compiling"},
+		"type":     {func(r *Rule) { r.Conditions = []string{`"yes"`} }, "must be a boolean"},
+		"severity": {func(r *Rule) { r.Severity = "urgent" }, "unknown severity"},
+		"message":  {func(r *Rule) { r.Message = "{{ .metadata" }, "parsing message"},
+		"kind":     {func(r *Rule) { r.Kind = "" }, "no apiVersion or kind"},
+		"version":  {func(r *Rule) { r.APIVersion = "apps/v1/beta" }, "unexpected GroupVersion"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := valid
+			tc.update(&rule)
+			_, err := NewRuleAnalyzer(rule)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestGetAnalyzerMapRules(t *testing.T) {
+	viper.Set("rule_analyzers", []map[string]interface{}{
+		{"name": "RequireTeamLabel", "apiVersion": "apps/v1", "kind": "Deployment", "conditions": []string{"true"}, "message": "m"},
+		{"name": "Pod", "apiVersion": "v1", "kind": "Pod", "conditions": []string{"true"}, "message": "m"},
+		{"name": "Invalid", "apiVersion": "v1", "kind": "Pod", "conditions": []string{"object.("}, "message": "m"},
+	})
+	defer viper.Set("rule_analyzers", nil)
+	var problems bytes.Buffer
+	ruleProblems = &problems
+	defer func() { ruleProblems = os.Stderr }()
+
+	core, merged := GetAnalyzerMap()
+	require.IsType(t, RuleAnalyzer{}, core["RequireTeamLabel"])
+	require.IsType(t, RuleAnalyzer{}, merged["RequireTeamLabel"])
+	require.IsType(t, PodAnalyzer{}, core["Pod"], "built-in analyzers cannot be replaced")
+	require.NotContains(t, merged, "Invalid", "invalid rules are ignored when loaded")
+	require.Contains(t, problems.String(), "rule analyzer Pod conflicts with a built-in analyzer")
+	require.Contains(t, problems.String(), "the rule analyzer is ignored")
+
+	coreKeys, _, _ := ListFilters()
+	require.Contains(t, coreKeys, "RequireTeamLabel")
+	require.NotContains(t, coreKeys, "Invalid")
+
+	// The rules are compiled once per configuration.
+	rules := ruleAnalyzers()
+	require.Len(t, rules, 1)
+	require.Same(t, &rules[0], &ruleAnalyzers()[0])
+}
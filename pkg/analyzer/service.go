@@ -17,7 +17,6 @@ import (
 	"fmt"
 
 	"github.com/fatih/color"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
@@ -45,14 +44,15 @@ func (ServiceAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	})
 
 	// search all namespaces for pods that are not running
-	list, err := a.Client.GetClient().CoreV1().Endpoints(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	list, err := store.Endpoints(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, ep := range list.Items {
+	for _, ep := range list {
 		var failures []common.Failure
 
 		// Check for empty service
@@ -61,7 +61,7 @@ func (ServiceAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 				continue
 			}
 
-			svc, err := a.Client.GetClient().CoreV1().Services(ep.Namespace).Get(a.Context, ep.Name, metav1.GetOptions{})
+			svc, err := store.Service(ep.Namespace, ep.Name)
 			if err != nil {
 				color.Yellow("Service %s/%s does not exist", ep.Namespace, ep.Name)
 				continue
@@ -83,6 +83,7 @@ func (ServiceAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 							Masked:   util.MaskString(v),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 			}
 		} else {
@@ -108,22 +109,20 @@ func (ServiceAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 					Text:          fmt.Sprintf("Service has not ready endpoints, pods: %s, expected %d", pods, count),
 					KubernetesDoc: doc,
 					Sensitive:     []common.Sensitive{},
+					Severity:      common.SeverityWarning,
 				})
 			}
 		}
 		// fetch event
-		events, err := a.Client.GetClient().CoreV1().Events(a.Namespace).List(a.Context,
-			metav1.ListOptions{
-				FieldSelector: "involvedObject.name=" + ep.Name,
-			})
-
+		events, err := store.Events(ep.Namespace, ep.Name)
 		if err != nil {
 			return nil, err
 		}
-		for _, event := range events.Items {
+		for _, event := range events {
 			if event.Type != "Normal" {
 				failures = append(failures, common.Failure{
-					Text: fmt.Sprintf("Service %s/%s has event %s", ep.Namespace, ep.Name, event.Message),
+					Text:     fmt.Sprintf("Service %s/%s has event %s", ep.Namespace, ep.Name, event.Message),
+					Severity: common.SeverityWarning,
 				})
 			}
 		}
@@ -143,7 +142,7 @@ func (ServiceAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.Endpoint.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.Endpoint.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -20,7 +20,6 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -42,18 +41,19 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		"analyzer_name": kind,
 	})
 
-	list, err := a.Client.GetClient().AppsV1().StatefulSets(a.Namespace).List(a.Context, metav1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	list, err := store.StatefulSets(a.Namespace, a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, sts := range list.Items {
+	for _, sts := range list {
 		var failures []common.Failure
 
 		// get serviceName
 		serviceName := sts.Spec.ServiceName
-		_, err := a.Client.GetClient().CoreV1().Services(sts.Namespace).Get(a.Context, serviceName, metav1.GetOptions{})
+		_, err := store.Service(sts.Namespace, serviceName)
 		if err != nil {
 			doc := apiDoc.GetApiDocV2("spec.serviceName")
 
@@ -74,12 +74,13 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 						Masked:   util.MaskString(serviceName),
 					},
 				},
+				Severity: common.SeverityWarning,
 			})
 		}
 		if len(sts.Spec.VolumeClaimTemplates) > 0 {
 			for _, volumeClaimTemplate := range sts.Spec.VolumeClaimTemplates {
 				if volumeClaimTemplate.Spec.StorageClassName != nil {
-					_, err := a.Client.GetClient().StorageV1().StorageClasses().Get(a.Context, *volumeClaimTemplate.Spec.StorageClassName, metav1.GetOptions{})
+					_, err := store.StorageClass(*volumeClaimTemplate.Spec.StorageClassName)
 					if err != nil {
 						failures = append(failures, common.Failure{
 							Text: fmt.Sprintf("StatefulSet uses the storage class %s which does not exist.", *volumeClaimTemplate.Spec.StorageClassName),
@@ -89,6 +90,7 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 									Masked:   util.MaskString(*volumeClaimTemplate.Spec.StorageClassName),
 								},
 							},
+							Severity: common.SeverityCritical,
 						})
 					}
 				}
@@ -97,16 +99,17 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		if sts.Spec.Replicas != nil && *(sts.Spec.Replicas) != sts.Status.AvailableReplicas {
 			for i := int32(0); i < *(sts.Spec.Replicas); i++ {
 				podName := sts.Name + "-" + fmt.Sprint(i)
-				pod, err := a.Client.GetClient().CoreV1().Pods(sts.Namespace).Get(a.Context, podName, metav1.GetOptions{})
+				pod, err := store.Pod(sts.Namespace, podName)
 				if err != nil {
 					if errors.IsNotFound(err) && i == 0 {
-						evt, err := util.FetchLatestEvent(a.Context, a.Client, sts.Namespace, sts.Name)
+						evt, err := store.LatestEvent(sts.Namespace, sts.Name)
 						if err != nil || evt == nil || evt.Type == "Normal" {
 							break
 						}
 						failures = append(failures, common.Failure{
 							Text:      evt.Message,
 							Sensitive: []common.Sensitive{},
+							Severity:  common.SeverityWarning,
 						})
 					}
 					break
@@ -124,6 +127,7 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 								Masked:   util.MaskString(pod.Namespace),
 							},
 						},
+						Severity: common.SeverityCritical,
 					})
 					break
 				}
@@ -145,7 +149,7 @@ func (StatefulSetAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.StatefulSet.ObjectMeta)
+		parent, found := util.GetParentFromStore(store, value.StatefulSet.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -14,13 +14,11 @@ limitations under the License.
 package analyzer
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -42,13 +40,14 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 		"analyzer_name": kind,
 	})
 
-	validatingWebhooks, err := a.Client.GetClient().AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context.Background(), v1.ListOptions{LabelSelector: a.LabelSelector})
+	store := a.GetStore()
+	validatingWebhooks, err := store.ValidatingWebhookConfigurations(a.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 
-	for _, webhookConfig := range validatingWebhooks.Items {
+	for _, webhookConfig := range validatingWebhooks {
 		for _, webhook := range webhookConfig.Webhooks {
 			var failures []common.Failure
 			if webhook.ClientConfig.Service == nil {
@@ -56,7 +55,7 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 			}
 			svc := webhook.ClientConfig.Service
 			// Get the service
-			service, err := a.Client.GetClient().CoreV1().Services(svc.Namespace).Get(context.Background(), svc.Name, v1.GetOptions{})
+			service, err := store.Service(svc.Namespace, svc.Name)
 			if err != nil {
 				// If the service is not found, we can't check the pods
 				failures = append(failures, common.Failure{
@@ -72,6 +71,7 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 							Masked:   util.MaskString(svc.Name),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 				preAnalysis[fmt.Sprintf("%s/%s", webhookConfig.Namespace, webhook.Name)] = common.PreAnalysis{
 					ValidatingWebhook: webhookConfig,
@@ -86,14 +86,12 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 				continue
 			}
 			// Get pods within service
-			pods, err := a.Client.GetClient().CoreV1().Pods(svc.Namespace).List(context.Background(), v1.ListOptions{
-				LabelSelector: util.MapToString(service.Spec.Selector),
-			})
+			pods, err := store.Pods(svc.Namespace, util.MapToString(service.Spec.Selector))
 			if err != nil {
 				return nil, err
 			}
 
-			if len(pods.Items) == 0 {
+			if len(pods) == 0 {
 				failures = append(failures, common.Failure{
 					Text:          fmt.Sprintf("No active pods found within service %s as mapped to by Validating Webhook %s", svc.Name, webhook.Name),
 					KubernetesDoc: apiDoc.GetApiDocV2("spec.webhook.clientConfig.service"),
@@ -103,10 +101,11 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 							Masked:   util.MaskString(webhookConfig.Namespace),
 						},
 					},
+					Severity: common.SeverityCritical,
 				})
 
 			}
-			for _, pod := range pods.Items {
+			for _, pod := range pods {
 				if pod.Status.Phase != "Running" {
 					doc := apiDoc.GetApiDocV2("spec.webhook")
 					failures = append(failures, common.Failure{
@@ -130,6 +129,7 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 								Masked:   util.MaskString(pod.Name),
 							},
 						},
+						Severity: common.SeverityCritical,
 					})
 				}
 			}
@@ -149,7 +149,7 @@ func (ValidatingWebhookAnalyzer) Analyze(a common.Analyzer) ([]common.Result, er
 			Error: value.FailureDetails,
 		}
 
-		parent, found := util.GetParent(a.Client, value.ValidatingWebhook.ObjectMeta)
+		parent, found := util.GetParentFromStore(a.GetStore(), value.ValidatingWebhook.ObjectMeta)
 		if found {
 			currentAnalysis.ParentObject = parent
 		}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export writes every entry of c to w as a gzip compressed tar archive with
+// one file per entry, named after its key. Values are written as Load returns
+// them, i.e. decrypted. Expired entries are skipped. It returns the number of
+// entries written.
+func Export(c ICache, w io.Writer) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	exported := 0
+	for _, entry := range entries {
+		data, err := c.Load(entry.Name)
+		if IsStale(err) {
+			continue
+		}
+		if err != nil {
+			return exported, fmt.Errorf("loading %s: %w", entry.Name, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     entry.Name,
+			Mode:     0o600,
+			Size:     int64(len(data)),
+			ModTime:  entry.UpdatedAt,
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return exported, err
+		}
+		if _, err := io.WriteString(tw, data); err != nil {
+			return exported, err
+		}
+		exported++
+	}
+	if err := tw.Close(); err != nil {
+		return exported, err
+	}
+	return exported, gz.Close()
+}
+
+// Import stores the entries of an archive written by Export in c. Entries
+// whose key already exists are kept unless overwrite is set. It returns the
+// number of entries stored.
+func Import(c ICache, r io.Reader, overwrite bool) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	imported := 0
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, fmt.Errorf("reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Keys become file names in the file based cache.
+		key := header.Name
+		if key != path.Base(key) || key == "." || key == ".." || strings.Contains(key, "\\") {
+			return imported, fmt.Errorf("invalid cache key %q in archive", key)
+		}
+		if !overwrite && c.Exists(key) {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return imported, fmt.Errorf("reading %s from archive: %w", key, err)
+		}
+		if err := c.Store(key, string(data)); err != nil {
+			return imported, fmt.Errorf("storing %s: %w", key, err)
+		}
+		imported++
+	}
+}
+
+// GC removes the entries of c last updated before olderThan ago. It returns
+// the number of entries removed.
+func GC(c ICache, olderThan time.Duration) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for _, entry := range entries {
+		if !entry.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		if err := c.Remove(entry.Name); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", entry.Name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ParseAge parses a duration such as "30d" or "12h". Days are supported in
+// addition to the units of time.ParseDuration.
+func ParseAge(age string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(age)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q: use a positive duration such as 30d or 12h", age)
+	}
+	return d, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	source, dir := newTestFileCache(t, CacheProvider{})
+	require.NoError(t, source.Store("a", "explanation a"))
+	require.NoError(t, source.Store("b", "explanation b"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "expired"), []byte(expiresAtHeader+"1\nold"), 0600))
+
+	var archive bytes.Buffer
+	exported, err := Export(source, &archive)
+	require.NoError(t, err)
+	require.Equal(t, 2, exported, "expired entries are skipped")
+
+	target := newMemoryCache()
+	require.NoError(t, target.Store("a", "kept"))
+	imported, err := Import(target, bytes.NewReader(archive.Bytes()), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, imported)
+	require.Equal(t, map[string]string{"a": "kept", "b": "explanation b"}, target.entries)
+
+	imported, err = Import(target, bytes.NewReader(archive.Bytes()), true)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+	require.Equal(t, "explanation a", target.entries["a"])
+}
+
+func TestImportRejectsPaths(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0o600, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	_, err = Import(newMemoryCache(), &archive, false)
+	require.ErrorContains(t, err, "invalid cache key")
+}
+
+func TestGC(t *testing.T) {
+	c, dir := newTestFileCache(t, CacheProvider{})
+	require.NoError(t, c.Store("old", "data"))
+	require.NoError(t, c.Store("new", "data"))
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "old"), old, old))
+
+	removed, err := GC(c, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.False(t, c.Exists("old"))
+	require.True(t, c.Exists("new"))
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := ParseAge("30d")
+	require.NoError(t, err)
+	require.Equal(t, 30*24*time.Hour, age)
+
+	age, err = ParseAge("90m")
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, age)
+
+	for _, invalid := range []string{"", "d", "-1d", "0h", "soon"} {
+		_, err := ParseAge(invalid)
+		require.Error(t, err, invalid)
+	}
+}
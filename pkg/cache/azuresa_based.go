@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
@@ -17,6 +18,7 @@ type AzureCache struct {
 	ctx           context.Context
 	noCache       bool
 	containerName string
+	ttl           time.Duration
 	session       *azblob.Client
 }
 
@@ -55,7 +57,12 @@ func (s *AzureCache) Configure(cacheInfo CacheProvider) error {
 			return err
 		}
 	}
+	ttl, err := ParseTTL(cacheInfo.TTL)
+	if err != nil {
+		return err
+	}
 	s.containerName = cacheInfo.Azure.ContainerName
+	s.ttl = ttl
 	s.session = client
 
 	return nil
@@ -65,7 +72,11 @@ func (s *AzureCache) Configure(cacheInfo CacheProvider) error {
 func (s *AzureCache) Store(key string, data string) error {
 	// Store the object as a new file in the Azure blob storage with data as the content
 	cacheData := []byte(data)
-	_, err := s.session.UploadBuffer(s.ctx, s.containerName, key, cacheData, &azblob.UploadBufferOptions{})
+	options := &azblob.UploadBufferOptions{}
+	if expiry := expiresAt(s.ttl); expiry != "" {
+		options.Metadata = map[string]*string{azureExpiresAtMetadata: &expiry}
+	}
+	_, err := s.session.UploadBuffer(s.ctx, s.containerName, key, cacheData, options)
 	return err
 }
 
@@ -75,6 +86,11 @@ func (s *AzureCache) Load(key string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if isExpired(metadataValue(load.Metadata, azureExpiresAtMetadata)) {
+		load.Body.Close()
+		_ = s.Remove(key)
+		return "", ErrExpired
+	}
 	data := bytes.Buffer{}
 	retryReader := load.NewRetryReader(s.ctx, &azblob.RetryReaderOptions{})
 	_, err = data.ReadFrom(retryReader)
@@ -122,24 +138,15 @@ func (s *AzureCache) Remove(key string) error {
 
 func (s *AzureCache) Exists(key string) bool {
 	// Check if the object exists in the blob storage
-	pager := s.session.NewListBlobsFlatPager(s.containerName, &azblob.ListBlobsFlatOptions{
-		Include: azblob.ListBlobsInclude{Snapshots: false, Versions: false},
-	})
-
-	for pager.More() {
-		resp, err := pager.NextPage(s.ctx)
-		if err != nil {
-			return false
-		}
-
-		for _, blob := range resp.Segment.BlobItems {
-			if *blob.Name == key {
-				return true
-			}
-		}
+	props, err := s.session.ServiceClient().NewContainerClient(s.containerName).NewBlobClient(key).GetProperties(s.ctx, nil)
+	if err != nil {
+		return false
 	}
-
-	return false
+	if isExpired(metadataValue(props.Metadata, azureExpiresAtMetadata)) {
+		_ = s.Remove(key)
+		return false
+	}
+	return true
 }
 
 func (s *AzureCache) IsCacheDisabled() bool {
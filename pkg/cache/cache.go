@@ -14,6 +14,7 @@ var (
 		&FileBasedCache{},
 		&GCSCache{},
 		&S3Cache{},
+		&RedisCache{},
 	}
 )
 
@@ -47,22 +48,68 @@ func ParseCacheConfiguration() (CacheProvider, error) {
 	return cacheInfo, nil
 }
 
+// CacheProviderOptions holds the settings of a remote cache. Only the
+// settings of the chosen cache type are used.
+type CacheProviderOptions struct {
+	// BucketName is the S3 or GCS bucket.
+	BucketName string
+	// Region is the S3 or GCS region.
+	Region string
+	// Endpoint is the S3 or minio endpoint, or the address of the Redis server.
+	Endpoint string
+	// StorageAccount and ContainerName locate the Azure container.
+	StorageAccount string
+	ContainerName  string
+	// ProjectId is the GCP project.
+	ProjectId string
+	// Insecure skips TLS verification for S3 and Redis.
+	Insecure bool
+	// RedisDB, RedisTLS and RedisPrefix configure the Redis database, TLS and
+	// key prefix.
+	RedisDB     int
+	RedisTLS    bool
+	RedisPrefix string
+}
+
+// NewCacheProvider builds the configuration of an Azure, GCS or S3 cache.
+//
+// Deprecated: use NewCacheProviderWithOptions, which also supports Redis.
 func NewCacheProvider(cacheType, bucketname, region, endpoint, storageAccount, containerName, projectId string, insecure bool) (CacheProvider, error) {
+	return NewCacheProviderWithOptions(cacheType, CacheProviderOptions{
+		BucketName:     bucketname,
+		Region:         region,
+		Endpoint:       endpoint,
+		StorageAccount: storageAccount,
+		ContainerName:  containerName,
+		ProjectId:      projectId,
+		Insecure:       insecure,
+	})
+}
+
+// NewCacheProviderWithOptions builds and checks the configuration of a remote
+// cache.
+func NewCacheProviderWithOptions(cacheType string, options CacheProviderOptions) (CacheProvider, error) {
 	cProvider := CacheProvider{}
 
 	switch {
 	case cacheType == "azure":
-		cProvider.Azure.ContainerName = containerName
-		cProvider.Azure.StorageAccount = storageAccount
+		cProvider.Azure.ContainerName = options.ContainerName
+		cProvider.Azure.StorageAccount = options.StorageAccount
 	case cacheType == "gcs":
-		cProvider.GCS.BucketName = bucketname
-		cProvider.GCS.ProjectId = projectId
-		cProvider.GCS.Region = region
+		cProvider.GCS.BucketName = options.BucketName
+		cProvider.GCS.ProjectId = options.ProjectId
+		cProvider.GCS.Region = options.Region
 	case cacheType == "s3":
-		cProvider.S3.BucketName = bucketname
-		cProvider.S3.Region = region
-		cProvider.S3.Endpoint = endpoint
-		cProvider.S3.InsecureSkipVerify = insecure
+		cProvider.S3.BucketName = options.BucketName
+		cProvider.S3.Region = options.Region
+		cProvider.S3.Endpoint = options.Endpoint
+		cProvider.S3.InsecureSkipVerify = options.Insecure
+	case cacheType == "redis":
+		cProvider.Redis.Address = options.Endpoint
+		cProvider.Redis.DB = options.RedisDB
+		cProvider.Redis.TLS = options.RedisTLS
+		cProvider.Redis.InsecureSkipVerify = options.Insecure
+		cProvider.Redis.Prefix = options.RedisPrefix
 	default:
 		return CacheProvider{}, status.Error(codes.Internal, fmt.Sprintf("%s is not a valid option", cacheType))
 	}
@@ -91,17 +138,60 @@ func GetCacheConfiguration() (ICache, error) {
 		cache = &AzureCache{}
 	case cacheInfo.S3 != S3CacheConfiguration{}:
 		cache = &S3Cache{}
+	case cacheInfo.Redis != RedisCacheConfiguration{}:
+		cache = &RedisCache{}
 	default:
 		cache = &FileBasedCache{}
 	}
 
 	err_config := cache.Configure(cacheInfo)
+	if err_config != nil {
+		return cache, err_config
+	}
+
+	// Record the hits, misses and latency of every cache type.
+	if _, isFile := cache.(*FileBasedCache); cacheInfo.Tiered && !isFile {
+		local := &FileBasedCache{}
+		if err := local.Configure(cacheInfo); err != nil {
+			return nil, err
+		}
+		cache = NewTieredCache(local, cache)
+	} else {
+		cache = &statsCache{ICache: cache, tier: cache.GetName()}
+	}
+	if !cacheInfo.Encryption.Enabled() {
+		return cache, nil
+	}
 
-	return cache, err_config
+	// Encrypt the values of every cache type.
+	key, err := LoadEncryptionKey(cacheInfo.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedCache(cache, key)
 }
 
 func AddRemoteCache(cacheInfo CacheProvider) error {
+	// Keep the expiry, size, encryption and tiering settings of the previous
+	// cache.
+	if previous, err := ParseCacheConfiguration(); err == nil {
+		if cacheInfo.TTL == "" {
+			cacheInfo.TTL = previous.TTL
+		}
+		if cacheInfo.MaxSize == "" {
+			cacheInfo.MaxSize = previous.MaxSize
+		}
+		if !cacheInfo.Encryption.Enabled() {
+			cacheInfo.Encryption = previous.Encryption
+		}
+		cacheInfo.Tiered = cacheInfo.Tiered || previous.Tiered
+	}
+
+	return UpdateCacheConfiguration(cacheInfo)
+}
 
+// UpdateCacheConfiguration replaces the stored cache configuration.
+func UpdateCacheConfiguration(cacheInfo CacheProvider) error {
 	viper.Set("cache", cacheInfo)
 
 	err := viper.WriteConfig()
@@ -118,7 +208,7 @@ func RemoveRemoteCache() error {
 		return status.Error(codes.Internal, "cache unmarshal")
 	}
 
-	cacheInfo = CacheProvider{}
+	cacheInfo = CacheProvider{TTL: cacheInfo.TTL, MaxSize: cacheInfo.MaxSize, Encryption: cacheInfo.Encryption, Tiered: cacheInfo.Tiered}
 	viper.Set("cache", cacheInfo)
 	err = viper.WriteConfig()
 	if err != nil {
@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// encryptedPrefix marks values encrypted by EncryptedCache and the
+	// version of their layout.
+	encryptedPrefix = "k8sgpt-enc:v1:"
+	// dataKeySize is the size of the AES-256 key generated for every value.
+	dataKeySize = 32
+	// defaultSecretKey is the data key of the Secret holding the key.
+	defaultSecretKey = "key"
+)
+
+var (
+	// ErrUnencrypted is returned when loading a value that was stored before
+	// encryption was enabled.
+	ErrUnencrypted = errors.New("cache entry is not encrypted")
+	// ErrUndecryptable is returned when loading a value encrypted with
+	// another key.
+	ErrUndecryptable = errors.New("cache entry cannot be decrypted with the configured key")
+)
+
+type CacheEncryptionConfiguration struct {
+	// KeyFile is a file holding the key.
+	KeyFile string `mapstructure:"keyfile" yaml:"keyfile,omitempty"`
+	// KeyEnv is an environment variable holding the key.
+	KeyEnv string `mapstructure:"keyenv" yaml:"keyenv,omitempty"`
+	// Secret is a Kubernetes Secret holding the key, as namespace/name.
+	Secret string `mapstructure:"secret" yaml:"secret,omitempty"`
+	// SecretKey is the data key of the key in Secret, "key" by default.
+	SecretKey string `mapstructure:"secretkey" yaml:"secretkey,omitempty"`
+}
+
+// Enabled reports whether a key source is configured.
+func (c CacheEncryptionConfiguration) Enabled() bool {
+	return c.KeyFile != "" || c.KeyEnv != "" || c.Secret != ""
+}
+
+// newSecretClient returns the client used to read the key from a Secret.
+var newSecretClient = func() (k8s.Interface, error) {
+	client, err := kubernetes.NewClient(viper.GetString("kubecontext"), viper.GetString("kubeconfig"))
+	if err != nil {
+		return nil, err
+	}
+	return client.GetClient(), nil
+}
+
+// LoadEncryptionKey reads the key configured in c. Keys are 16, 24 or 32
+// bytes for AES-128, AES-192 or AES-256, either raw or base64 encoded.
+func LoadEncryptionKey(c CacheEncryptionConfiguration) ([]byte, error) {
+	var raw []byte
+	switch {
+	case c.KeyFile != "":
+		data, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading cache encryption key: %w", err)
+		}
+		raw = data
+	case c.KeyEnv != "":
+		value, ok := os.LookupEnv(c.KeyEnv)
+		if !ok {
+			return nil, fmt.Errorf("cache encryption key variable %s is not set", c.KeyEnv)
+		}
+		raw = []byte(value)
+	case c.Secret != "":
+		namespace, name, ok := strings.Cut(c.Secret, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("cache encryption key secret %q must be namespace/name", c.Secret)
+		}
+		client, err := newSecretClient()
+		if err != nil {
+			return nil, fmt.Errorf("reading cache encryption key: %w", err)
+		}
+		secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("reading cache encryption key: %w", err)
+		}
+		secretKey := c.SecretKey
+		if secretKey == "" {
+			secretKey = defaultSecretKey
+		}
+		data, ok := secret.Data[secretKey]
+		if !ok {
+			return nil, fmt.Errorf("secret %s has no key %s", c.Secret, secretKey)
+		}
+		raw = data
+	default:
+		return nil, errors.New("no cache encryption key configured")
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if decoded, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil && validKeySize(len(decoded)) {
+		return decoded, nil
+	}
+	// Raw keys may contain whitespace bytes, so they are only trimmed when
+	// they do not fit otherwise, e.g. a file ending with a newline.
+	for _, key := range [][]byte{raw, trimmed} {
+		if validKeySize(len(key)) {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("cache encryption key must be 16, 24 or 32 bytes, got %d", len(trimmed))
+}
+
+func validKeySize(size int) bool {
+	return size == 16 || size == 24 || size == 32
+}
+
+var _ (ICache) = (*EncryptedCache)(nil)
+
+// EncryptedCache encrypts the values of another cache with envelope
+// encryption: every value is sealed with a fresh data key, which is stored
+// next to it sealed with the configured key. Both use AES-GCM.
+type EncryptedCache struct {
+	ICache
+	keyEncryption cipher.AEAD
+}
+
+// NewEncryptedCache wraps cache so its values are encrypted with key.
+func NewEncryptedCache(cache ICache, key []byte) (*EncryptedCache, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedCache{ICache: cache, keyEncryption: aead}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with aead, prefixing the random nonce it used.
+// additionalData binds the result to the entry it is stored in.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func unseal(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("cache entry is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// Store stores the sealed data key and the sealed value, both base64 encoded
+// and separated by a dot.
+func (e *EncryptedCache) Store(key string, data string) error {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return err
+	}
+	dataEncryption, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+	sealedKey, err := seal(e.keyEncryption, dataKey, []byte(key))
+	if err != nil {
+		return err
+	}
+	sealedData, err := seal(dataEncryption, []byte(data), []byte(key))
+	if err != nil {
+		return err
+	}
+	return e.ICache.Store(key, encryptedPrefix+
+		base64.StdEncoding.EncodeToString(sealedKey)+"."+
+		base64.StdEncoding.EncodeToString(sealedData))
+}
+
+func (e *EncryptedCache) Load(key string) (string, error) {
+	stored, err := e.ICache.Load(key)
+	if err != nil {
+		return "", err
+	}
+	envelope, ok := strings.CutPrefix(stored, encryptedPrefix)
+	if !ok {
+		return "", ErrUnencrypted
+	}
+	encodedKey, encodedData, ok := strings.Cut(envelope, ".")
+	if !ok {
+		return "", errors.New("malformed encrypted cache entry")
+	}
+	sealedKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted cache entry: %w", err)
+	}
+	sealedData, err := base64.StdEncoding.DecodeString(encodedData)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted cache entry: %w", err)
+	}
+
+	dataKey, err := unseal(e.keyEncryption, sealedKey, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("decrypting cache entry %s: %w", key, ErrUndecryptable)
+	}
+	dataEncryption, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := unseal(dataEncryption, sealedData, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("decrypting cache entry %s: %w", key, ErrUndecryptable)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEncryptedCache(t *testing.T) {
+	fileCache, dir := newTestFileCache(t, CacheProvider{})
+	key := bytes.Repeat([]byte{1}, 32)
+	c, err := NewEncryptedCache(fileCache, key)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Store("key", "Pod default/web is pending"))
+	stored, err := os.ReadFile(filepath.Join(dir, "key"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(stored), encryptedPrefix))
+	require.NotContains(t, string(stored), "default/web")
+
+	data, err := c.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, "Pod default/web is pending", data)
+	require.True(t, c.Exists("key"), "other methods use the wrapped cache")
+
+	// A value is bound to its key.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy"), stored, 0600))
+	_, err = c.Load("copy")
+	require.ErrorIs(t, err, ErrUndecryptable)
+
+	other, err := NewEncryptedCache(fileCache, bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	_, err = other.Load("key")
+	require.ErrorIs(t, err, ErrUndecryptable)
+	require.True(t, IsStale(err))
+
+	require.NoError(t, fileCache.Store("plain", "data"))
+	_, err = c.Load("plain")
+	require.ErrorIs(t, err, ErrUnencrypted)
+	require.True(t, IsStale(err))
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(encoded+"\n"), 0600))
+	loaded, err := LoadEncryptionKey(CacheEncryptionConfiguration{KeyFile: keyFile})
+	require.NoError(t, err)
+	require.Equal(t, key, loaded)
+
+	t.Setenv("K8SGPT_TEST_CACHE_KEY", "0123456789abcdef")
+	loaded, err = LoadEncryptionKey(CacheEncryptionConfiguration{KeyEnv: "K8SGPT_TEST_CACHE_KEY"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("0123456789abcdef"), loaded, "raw keys are used as is")
+
+	_, err = LoadEncryptionKey(CacheEncryptionConfiguration{KeyEnv: "K8SGPT_TEST_CACHE_KEY_MISSING"})
+	require.ErrorContains(t, err, "is not set")
+
+	t.Setenv("K8SGPT_TEST_CACHE_KEY", "short")
+	_, err = LoadEncryptionKey(CacheEncryptionConfiguration{KeyEnv: "K8SGPT_TEST_CACHE_KEY"})
+	require.ErrorContains(t, err, "must be 16, 24 or 32 bytes")
+}
+
+func TestLoadEncryptionKeyFromSecret(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 24)
+	clientset := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cache-key", Namespace: "k8sgpt"},
+		Data:       map[string][]byte{"key": key, "other": []byte("short")},
+	})
+	previous := newSecretClient
+	newSecretClient = func() (k8s.Interface, error) { return clientset, nil }
+	defer func() { newSecretClient = previous }()
+
+	loaded, err := LoadEncryptionKey(CacheEncryptionConfiguration{Secret: "k8sgpt/cache-key"})
+	require.NoError(t, err)
+	require.Equal(t, key, loaded)
+
+	_, err = LoadEncryptionKey(CacheEncryptionConfiguration{Secret: "k8sgpt/cache-key", SecretKey: "missing"})
+	require.ErrorContains(t, err, "has no key missing")
+	_, err = LoadEncryptionKey(CacheEncryptionConfiguration{Secret: "cache-key"})
+	require.ErrorContains(t, err, "must be namespace/name")
+	_, err = LoadEncryptionKey(CacheEncryptionConfiguration{Secret: "k8sgpt/absent"})
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// expiresAtMetadata is the object metadata holding the expiry of an
+	// entry in remote caches, as unix seconds.
+	expiresAtMetadata = "k8sgpt-expires-at"
+	// azureExpiresAtMetadata is expiresAtMetadata for Azure, whose metadata
+	// names must be C# identifiers.
+	azureExpiresAtMetadata = "k8sgpt_expires_at"
+)
+
+// ErrExpired is returned when loading an entry whose TTL has passed.
+var ErrExpired = errors.New("cache entry expired")
+
+// IsStale reports whether err means a stored entry can no longer be used and
+// should be regenerated.
+func IsStale(err error) bool {
+	return errors.Is(err, ErrExpired) || errors.Is(err, ErrUnencrypted) || errors.Is(err, ErrUndecryptable)
+}
+
+// ParseTTL parses the TTL of a cache configuration; empty means no expiry.
+func ParseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache ttl %q: %w", ttl, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid cache ttl %q: must not be negative", ttl)
+	}
+	return d, nil
+}
+
+// ParseMaxSize parses the size bound of a cache configuration, e.g. "100Mi";
+// empty means unbounded.
+func ParseMaxSize(maxSize string) (int64, error) {
+	if maxSize == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(maxSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache max size %q: %w", maxSize, err)
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("invalid cache max size %q: must not be negative", maxSize)
+	}
+	return q.Value(), nil
+}
+
+// expiresAt returns the expiry of an entry stored now, formatted as unix
+// seconds, or an empty string if entries do not expire.
+func expiresAt(ttl time.Duration) string {
+	if ttl <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+}
+
+// isExpired reports whether the expiry written by expiresAt has passed.
+// Entries stored without an expiry never expire.
+func isExpired(expiry string) bool {
+	if expiry == "" {
+		return false
+	}
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return false
+	}
+	return time.Now().After(time.Unix(seconds, 0))
+}
+
+// metadataValue looks up a metadata value case-insensitively, as the storage
+// APIs return metadata names with different casing than they were stored.
+func metadataValue[V string | *string](metadata map[string]V, name string) string {
+	for key, value := range metadata {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		switch v := any(value).(type) {
+		case string:
+			return v
+		case *string:
+			if v != nil {
+				return *v
+			}
+		}
+	}
+	return ""
+}
@@ -1,9 +1,16 @@
 package cache
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
@@ -11,11 +18,39 @@ import (
 
 var _ (ICache) = (*FileBasedCache)(nil)
 
+// expiresAtHeader prefixes the first line of entries stored with a TTL.
+// Entries written without it never expire.
+const expiresAtHeader = expiresAtMetadata + ":"
+
 type FileBasedCache struct {
 	noCache bool
+	ttl     time.Duration
+	maxSize int64
+	// mu serializes eviction with the writes of this process.
+	mu sync.Mutex
+	// size is the running size of the entries, measured by the last eviction
+	// pass and updated by every write, so that the directory is only scanned
+	// again once it looks full. Entries removed by other means make it an
+	// overestimate, which the next scan corrects.
+	size  int64
+	sized bool
 }
 
+// evictionTarget is the share of maxSize an eviction pass frees the cache
+// down to, so that a full cache is not rescanned on every write.
+const evictionTarget = 0.9
+
 func (f *FileBasedCache) Configure(cacheInfo CacheProvider) error {
+	ttl, err := ParseTTL(cacheInfo.TTL)
+	if err != nil {
+		return err
+	}
+	maxSize, err := ParseMaxSize(cacheInfo.MaxSize)
+	if err != nil {
+		return err
+	}
+	f.ttl = ttl
+	f.maxSize = maxSize
 	return nil
 }
 
@@ -49,7 +84,7 @@ func (*FileBasedCache) List() ([]CacheObjectDetails, error) {
 	return result, nil
 }
 
-func (*FileBasedCache) Exists(key string) bool {
+func (f *FileBasedCache) Exists(key string) bool {
 	path, err := xdg.CacheFile(filepath.Join("k8sgpt", key))
 
 	if err != nil {
@@ -63,24 +98,46 @@ func (*FileBasedCache) Exists(key string) bool {
 		fmt.Fprintln(os.Stderr, "warning: error while testing if cache key exists:", err)
 		return false
 	}
+	if !exists {
+		return false
+	}
 
-	return exists
+	expiry, err := readExpiry(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: error while testing if cache key exists:", err)
+		return false
+	}
+	if isExpired(expiry) {
+		_ = os.Remove(path)
+		return false
+	}
+	return true
 }
 
-func (*FileBasedCache) Load(key string) (string, error) {
+func (f *FileBasedCache) Load(key string) (string, error) {
 	path, err := xdg.CacheFile(filepath.Join("k8sgpt", key))
 
 	if err != nil {
 		return "", err
 	}
 
-	data, err := os.ReadFile(path)
+	raw, err := os.ReadFile(path)
 
 	if err != nil {
 		return "", err
 	}
 
-	return string(data), nil
+	expiry, data := splitExpiry(string(raw))
+	if isExpired(expiry) {
+		_ = os.Remove(path)
+		return "", ErrExpired
+	}
+
+	// The modification time records the last use of an entry for eviction.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return data, nil
 }
 
 func (*FileBasedCache) Remove(key string) error {
@@ -97,14 +154,94 @@ func (*FileBasedCache) Remove(key string) error {
 	return nil
 }
 
-func (*FileBasedCache) Store(key string, data string) error {
+func (f *FileBasedCache) Store(key string, data string) error {
 	path, err := xdg.CacheFile(filepath.Join("k8sgpt", key))
 
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, []byte(data), 0600)
+	if expiry := expiresAt(f.ttl); expiry != "" {
+		data = expiresAtHeader + expiry + "\n" + data
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		return err
+	}
+	if f.maxSize <= 0 {
+		return nil
+	}
+	f.size += int64(len(data)) - replaced
+	if f.sized && f.size <= f.maxSize {
+		return nil
+	}
+	return f.evict(filepath.Dir(path))
+}
+
+// evict removes expired entries from dir, then the least recently used ones
+// until the entries fit in evictionTarget of maxSize, and records their size.
+func (f *FileBasedCache) evict(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		path    string
+		size    int64
+		usedAt  time.Time
+		expired bool
+	}
+	var entries []entry
+	var total int64
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			// The entry was removed concurrently.
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		expiry, err := readExpiry(path)
+		if err != nil {
+			continue
+		}
+		if isExpired(expiry) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), usedAt: info.ModTime()})
+		total += info.Size()
+	}
+
+	f.size, f.sized = total, true
+	if total <= f.maxSize {
+		return nil
+	}
+
+	target := int64(float64(f.maxSize) * evictionTarget)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].usedAt.Before(entries[j].usedAt) })
+	for _, e := range entries {
+		if total <= target {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= e.size
+		f.size = total
+	}
+	return nil
 }
 
 func (s *FileBasedCache) GetName() string {
@@ -114,3 +251,29 @@ func (s *FileBasedCache) GetName() string {
 func (s *FileBasedCache) DisableCache() {
 	s.noCache = true
 }
+
+// splitExpiry separates the expiry header of an entry from its data.
+func splitExpiry(raw string) (string, string) {
+	if !strings.HasPrefix(raw, expiresAtHeader) {
+		return "", raw
+	}
+	header, data, _ := strings.Cut(raw, "\n")
+	return strings.TrimPrefix(header, expiresAtHeader), data
+}
+
+// readExpiry reads the expiry header of the entry at path without reading
+// the whole entry.
+func readExpiry(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	expiry, _ := splitExpiry(line)
+	return strings.TrimSuffix(expiry, "\n"), nil
+}
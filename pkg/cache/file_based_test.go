@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileCache(t *testing.T, cacheInfo CacheProvider) (*FileBasedCache, string) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	c := &FileBasedCache{}
+	require.NoError(t, c.Configure(cacheInfo))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "k8sgpt"), 0o700))
+	return c, filepath.Join(dir, "k8sgpt")
+}
+
+func TestFileBasedCacheTTL(t *testing.T) {
+	c, dir := newTestFileCache(t, CacheProvider{TTL: "1h"})
+
+	require.NoError(t, c.Store("fresh", "data"))
+	require.True(t, c.Exists("fresh"))
+	data, err := c.Load("fresh")
+	require.NoError(t, err)
+	require.Equal(t, "data", data)
+
+	expired := expiresAtHeader + "1\ndata"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "expired"), []byte(expired), 0600))
+	_, err = c.Load("expired")
+	require.ErrorIs(t, err, ErrExpired)
+	require.False(t, c.Exists("expired"), "expired entries are removed")
+	_, err = os.Stat(filepath.Join(dir, "expired"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFileBasedCacheWithoutExpiry(t *testing.T) {
+	c, dir := newTestFileCache(t, CacheProvider{TTL: "1h"})
+
+	// Entries written before TTLs existed have no expiry header.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old"), []byte("data"), 0600))
+	require.True(t, c.Exists("old"))
+	data, err := c.Load("old")
+	require.NoError(t, err)
+	require.Equal(t, "data", data)
+}
+
+func TestFileBasedCacheEviction(t *testing.T) {
+	c, dir := newTestFileCache(t, CacheProvider{MaxSize: "20"})
+
+	past := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a", "b"} {
+		require.NoError(t, c.Store(key, "12345678"))
+		used := past.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, key), used, used))
+	}
+
+	// Loading a marks it as recently used, so b is evicted first.
+	_, err := c.Load("a")
+	require.NoError(t, err)
+	require.NoError(t, c.Store("c", "12345678"))
+
+	require.True(t, c.Exists("a"))
+	require.False(t, c.Exists("b"))
+	require.True(t, c.Exists("c"))
+	require.Equal(t, int64(16), c.size)
+
+	// Writes below the limit only update the running size.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("12345678"), 0600))
+	require.NoError(t, c.Store("a", "1234"))
+	require.Equal(t, int64(12), c.size)
+	require.True(t, c.Exists("other"))
+
+	// Once the cache looks full the directory is scanned again, and entries
+	// are evicted below the limit.
+	for i, key := range []string{"other", "c"} {
+		used := past.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, key), used, used))
+	}
+	require.NoError(t, c.Store("d", "123456789"))
+	require.False(t, c.Exists("other"))
+	require.False(t, c.Exists("c"))
+	require.Equal(t, int64(13), c.size)
+}
+
+func TestParseLimits(t *testing.T) {
+	ttl, err := ParseTTL("")
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+	_, err = ParseTTL("-1h")
+	require.Error(t, err)
+
+	size, err := ParseMaxSize("1Mi")
+	require.NoError(t, err)
+	require.Equal(t, int64(1<<20), size)
+	_, err = ParseMaxSize("lots")
+	require.Error(t, err)
+
+	require.Error(t, (&FileBasedCache{}).Configure(CacheProvider{TTL: "soon"}))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/iterator"
@@ -15,6 +16,7 @@ type GCSCache struct {
 	bucketName string
 	projectId  string
 	region     string
+	ttl        time.Duration
 	session    *storage.Client
 }
 
@@ -38,6 +40,11 @@ func (s *GCSCache) Configure(cacheInfo CacheProvider) error {
 	s.bucketName = cacheInfo.GCS.BucketName
 	s.projectId = cacheInfo.GCS.ProjectId
 	s.region = cacheInfo.GCS.Region
+	ttl, err := ParseTTL(cacheInfo.TTL)
+	if err != nil {
+		return err
+	}
+	s.ttl = ttl
 	storageClient, err := storage.NewClient(s.ctx)
 	if err != nil {
 		log.Fatal(err)
@@ -58,6 +65,9 @@ func (s *GCSCache) Configure(cacheInfo CacheProvider) error {
 
 func (s *GCSCache) Store(key string, data string) error {
 	wc := s.session.Bucket(s.bucketName).Object(key).NewWriter(s.ctx)
+	if expiry := expiresAt(s.ttl); expiry != "" {
+		wc.Metadata = map[string]string{expiresAtMetadata: expiry}
+	}
 
 	if _, err := wc.Write([]byte(data)); err != nil {
 		return err
@@ -71,7 +81,17 @@ func (s *GCSCache) Store(key string, data string) error {
 }
 
 func (s *GCSCache) Load(key string) (string, error) {
-	reader, err := s.session.Bucket(s.bucketName).Object(key).NewReader(s.ctx)
+	obj := s.session.Bucket(s.bucketName).Object(key)
+	attrs, err := obj.Attrs(s.ctx)
+	if err != nil {
+		return "", err
+	}
+	if isExpired(metadataValue(attrs.Metadata, expiresAtMetadata)) {
+		_ = s.Remove(key)
+		return "", ErrExpired
+	}
+
+	reader, err := obj.NewReader(s.ctx)
 	if err != nil {
 		return "", err
 	}
@@ -116,8 +136,15 @@ func (s *GCSCache) List() ([]CacheObjectDetails, error) {
 
 func (s *GCSCache) Exists(key string) bool {
 	obj := s.session.Bucket(s.bucketName).Object(key)
-	_, err := obj.Attrs(s.ctx)
-	return err == nil
+	attrs, err := obj.Attrs(s.ctx)
+	if err != nil {
+		return false
+	}
+	if isExpired(metadataValue(attrs.Metadata, expiresAtMetadata)) {
+		_ = s.Remove(key)
+		return false
+	}
+	return true
 }
 
 func (s *GCSCache) IsCacheDisabled() bool {
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultRedisPrefix namespaces the keys of the cache in a shared Redis.
+	defaultRedisPrefix = "k8sgpt:"
+	// redisScanCount is the number of keys requested per SCAN call.
+	redisScanCount = 100
+)
+
+// Generate ICache implementation
+type RedisCache struct {
+	ctx     context.Context
+	noCache bool
+	prefix  string
+	ttl     time.Duration
+	session *redis.Client
+}
+
+type RedisCacheConfiguration struct {
+	Address            string `mapstructure:"address" yaml:"address,omitempty"`
+	DB                 int    `mapstructure:"db" yaml:"db,omitempty"`
+	TLS                bool   `mapstructure:"tls" yaml:"tls,omitempty"`
+	InsecureSkipVerify bool   `mapstructure:"insecure" yaml:"insecure,omitempty"`
+	Prefix             string `mapstructure:"prefix" yaml:"prefix,omitempty"`
+}
+
+func (s *RedisCache) Configure(cacheInfo CacheProvider) error {
+	s.ctx = context.Background()
+	if cacheInfo.Redis.Address == "" {
+		return errors.New("redis address not configured")
+	}
+	ttl, err := ParseTTL(cacheInfo.TTL)
+	if err != nil {
+		return err
+	}
+
+	// Credentials are read from the environment so they are never written
+	// to the configuration file.
+	options := &redis.Options{
+		Addr:     cacheInfo.Redis.Address,
+		DB:       cacheInfo.Redis.DB,
+		Username: os.Getenv("REDIS_USERNAME"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	}
+	if cacheInfo.Redis.TLS {
+		options.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cacheInfo.Redis.InsecureSkipVerify,
+		}
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping(s.ctx).Err(); err != nil {
+		client.Close()
+		return fmt.Errorf("connecting to redis at %s: %w", cacheInfo.Redis.Address, err)
+	}
+
+	s.prefix = cacheInfo.Redis.Prefix
+	if s.prefix == "" {
+		s.prefix = defaultRedisPrefix
+	}
+	s.ttl = ttl
+	s.session = client
+	return nil
+}
+
+// Entries are hashes holding the data and the time it was stored, as Redis
+// keeps no modification time.
+const (
+	redisDataField      = "data"
+	redisUpdatedAtField = "updated_at"
+)
+
+func (s *RedisCache) Store(key string, data string) error {
+	// Expiry is left to Redis.
+	_, err := s.session.Pipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(s.ctx, s.prefix+key, redisDataField, data, redisUpdatedAtField, time.Now().Unix())
+		if s.ttl > 0 {
+			pipe.Expire(s.ctx, s.prefix+key, s.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisCache) Load(key string) (string, error) {
+	data, err := s.session.HGet(s.ctx, s.prefix+key, redisDataField).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("cache key %s not found", key)
+	}
+	return data, err
+}
+
+func (s *RedisCache) List() ([]CacheObjectDetails, error) {
+	var keys []string
+	iter := s.session.Scan(s.ctx, 0, s.prefix+"*", redisScanCount).Iterator()
+	for iter.Next(s.ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	cmds, err := s.session.Pipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.HGet(s.ctx, key, redisUpdatedAtField)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var files []CacheObjectDetails
+	for i, cmd := range cmds {
+		updatedAt, err := cmd.(*redis.StringCmd).Result()
+		if errors.Is(err, redis.Nil) {
+			// The entry expired while listing.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		seconds, _ := strconv.ParseInt(updatedAt, 10, 64)
+		files = append(files, CacheObjectDetails{
+			Name:      keys[i][len(s.prefix):],
+			UpdatedAt: time.Unix(seconds, 0),
+		})
+	}
+	return files, nil
+}
+
+func (s *RedisCache) Remove(key string) error {
+	removed, err := s.session.Del(s.ctx, s.prefix+key).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("cache key %s not found", key)
+	}
+	return nil
+}
+
+func (s *RedisCache) Exists(key string) bool {
+	exists, err := s.session.Exists(s.ctx, s.prefix+key).Result()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: error while testing if cache key exists:", err)
+		return false
+	}
+	return exists == 1
+}
+
+func (s *RedisCache) IsCacheDisabled() bool {
+	return s.noCache
+}
+
+func (s *RedisCache) GetName() string {
+	return "redis"
+}
+
+func (s *RedisCache) DisableCache() {
+	s.noCache = true
+}
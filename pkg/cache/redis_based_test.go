@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in-process stand-in for the subset of Redis the cache uses.
+type fakeRedis struct {
+	mu      sync.Mutex
+	hashes  map[string]map[string]string
+	expires map[string]time.Time
+	dbs     map[int]bool
+}
+
+func startFakeRedis(t *testing.T, listener net.Listener) *fakeRedis {
+	r := &fakeRedis{
+		hashes:  map[string]map[string]string{},
+		expires: map[string]time.Time{},
+		dbs:     map[int]bool{},
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return r
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, r.handle(args)); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, errors.New("expected array")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// expire removes key if its TTL has passed; callers hold mu.
+func (r *fakeRedis) expire(key string) {
+	if at, ok := r.expires[key]; ok && time.Now().After(at) {
+		delete(r.hashes, key)
+		delete(r.expires, key)
+	}
+}
+
+func (r *fakeRedis) handle(args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		db, _ := strconv.Atoi(args[1])
+		r.dbs[db] = true
+		return "+OK\r\n"
+	case "HSET":
+		key := args[1]
+		r.expire(key)
+		if r.hashes[key] == nil {
+			r.hashes[key] = map[string]string{}
+		}
+		for i := 2; i+1 < len(args); i += 2 {
+			r.hashes[key][args[i]] = args[i+1]
+		}
+		return fmt.Sprintf(":%d\r\n", (len(args)-2)/2)
+	case "HGET":
+		r.expire(args[1])
+		value, ok := r.hashes[args[1]][args[2]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "EXPIRE":
+		seconds, _ := strconv.Atoi(args[2])
+		if _, ok := r.hashes[args[1]]; !ok {
+			return ":0\r\n"
+		}
+		r.expires[args[1]] = time.Now().Add(time.Duration(seconds) * time.Second)
+		return ":1\r\n"
+	case "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			r.expire(key)
+			if _, ok := r.hashes[key]; ok {
+				count++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "DEL":
+		count := 0
+		for _, key := range args[1:] {
+			r.expire(key)
+			if _, ok := r.hashes[key]; ok {
+				delete(r.hashes, key)
+				delete(r.expires, key)
+				count++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "SCAN":
+		// Every key is returned in a single page.
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.EqualFold(args[i], "MATCH") {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range r.hashes {
+			r.expire(key)
+			if _, ok := r.hashes[key]; !ok {
+				continue
+			}
+			if matched, _ := path.Match(pattern, key); matched {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		reply := "*2\r\n" + bulk("0") + fmt.Sprintf("*%d\r\n", len(keys))
+		for _, key := range keys {
+			reply += bulk(key)
+		}
+		return reply
+	default:
+		// Clients fall back to RESP2 when HELLO is unknown.
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func newTestRedisCache(t *testing.T, cacheInfo CacheProvider) (*RedisCache, *fakeRedis) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := startFakeRedis(t, listener)
+
+	cacheInfo.Redis.Address = listener.Addr().String()
+	c := &RedisCache{}
+	require.NoError(t, c.Configure(cacheInfo))
+	t.Cleanup(func() { c.session.Close() })
+	return c, server
+}
+
+func TestRedisCache(t *testing.T) {
+	c, server := newTestRedisCache(t, CacheProvider{Redis: RedisCacheConfiguration{DB: 2, Prefix: "test:"}})
+	require.True(t, server.dbs[2], "the configured database is selected")
+
+	require.False(t, c.Exists("key"))
+	require.NoError(t, c.Store("key", "data"))
+	require.True(t, c.Exists("key"))
+	data, err := c.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, "data", data)
+
+	server.mu.Lock()
+	_, ok := server.hashes["test:key"]
+	server.mu.Unlock()
+	require.True(t, ok, "keys are prefixed")
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "key", entries[0].Name)
+	require.WithinDuration(t, time.Now(), entries[0].UpdatedAt, time.Minute)
+
+	require.NoError(t, c.Remove("key"))
+	require.False(t, c.Exists("key"))
+	_, err = c.Load("key")
+	require.ErrorContains(t, err, "not found")
+	require.ErrorContains(t, c.Remove("key"), "not found")
+}
+
+func TestRedisCacheTTL(t *testing.T) {
+	c, server := newTestRedisCache(t, CacheProvider{TTL: "1h"})
+
+	require.NoError(t, c.Store("key", "data"))
+	server.mu.Lock()
+	expiry := server.expires[defaultRedisPrefix+"key"]
+	server.mu.Unlock()
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+
+	server.mu.Lock()
+	server.expires[defaultRedisPrefix+"key"] = time.Now().Add(-time.Second)
+	server.mu.Unlock()
+	require.False(t, c.Exists("key"))
+}
+
+func TestRedisCacheTLS(t *testing.T) {
+	// Reuse the self-signed certificate of httptest.
+	ts := httptest.NewTLSServer(nil)
+	ts.Close()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: ts.TLS.Certificates})
+	require.NoError(t, err)
+	startFakeRedis(t, listener)
+
+	cacheInfo := CacheProvider{Redis: RedisCacheConfiguration{Address: listener.Addr().String(), TLS: true}}
+	require.ErrorContains(t, (&RedisCache{}).Configure(cacheInfo), "certificate")
+
+	cacheInfo.Redis.InsecureSkipVerify = true
+	c := &RedisCache{}
+	require.NoError(t, c.Configure(cacheInfo))
+	defer c.session.Close()
+	require.NoError(t, c.Store("key", "data"))
+	require.True(t, c.Exists("key"))
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	err = (&RedisCache{}).Configure(CacheProvider{Redis: RedisCacheConfiguration{Address: address}})
+	require.ErrorContains(t, err, "connecting to redis")
+	require.ErrorContains(t, (&RedisCache{}).Configure(CacheProvider{}), "address not configured")
+}
+
+func TestNewCacheProviderRedis(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	startFakeRedis(t, listener)
+
+	provider, err := NewCacheProviderWithOptions("redis", CacheProviderOptions{Endpoint: listener.Addr().String(), RedisDB: 1, RedisPrefix: "team-a:"})
+	require.NoError(t, err)
+	require.Equal(t, RedisCacheConfiguration{Address: listener.Addr().String(), DB: 1, Prefix: "team-a:"}, provider.Redis)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -15,6 +16,7 @@ import (
 type S3Cache struct {
 	noCache    bool
 	bucketName string
+	ttl        time.Duration
 	session    *s3.S3
 }
 
@@ -30,6 +32,11 @@ func (s *S3Cache) Configure(cacheInfo CacheProvider) error {
 		log.Fatal("Bucket name not configured")
 	}
 	s.bucketName = cacheInfo.S3.BucketName
+	ttl, err := ParseTTL(cacheInfo.TTL)
+	if err != nil {
+		return err
+	}
+	s.ttl = ttl
 
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
@@ -50,7 +57,7 @@ func (s *S3Cache) Configure(cacheInfo CacheProvider) error {
 	s3Client := s3.New(sess)
 
 	// Check if the bucket exists, if not create it
-	_, err := s3Client.HeadBucket(&s3.HeadBucketInput{
+	_, err = s3Client.HeadBucket(&s3.HeadBucketInput{
 		Bucket: aws.String(cacheInfo.S3.BucketName),
 	})
 	if err != nil {
@@ -67,11 +74,15 @@ func (s *S3Cache) Configure(cacheInfo CacheProvider) error {
 
 func (s *S3Cache) Store(key string, data string) error {
 	// Store the object as a new file in the bucket with data as the content
-	_, err := s.session.PutObject(&s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Body:   aws.ReadSeekCloser(bytes.NewReader([]byte(data))),
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(key),
-	})
+	}
+	if expiry := expiresAt(s.ttl); expiry != "" {
+		input.Metadata = map[string]*string{expiresAtMetadata: aws.String(expiry)}
+	}
+	_, err := s.session.PutObject(input)
 	return err
 
 }
@@ -98,6 +109,11 @@ func (s *S3Cache) Load(key string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if isExpired(metadataValue(result.Metadata, expiresAtMetadata)) {
+		result.Body.Close()
+		_ = s.Remove(key)
+		return "", ErrExpired
+	}
 
 	buf := new(bytes.Buffer)
 	_, err_read := buf.ReadFrom(result.Body)
@@ -126,11 +142,18 @@ func (s *S3Cache) List() ([]CacheObjectDetails, error) {
 
 func (s *S3Cache) Exists(key string) bool {
 	// Check if the object exists in the bucket
-	_, err := s.session.HeadObject(&s3.HeadObjectInput{
+	head, err := s.session.HeadObject(&s3.HeadObjectInput{
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(key),
 	})
-	return err == nil
+	if err != nil {
+		return false
+	}
+	if isExpired(metadataValue(head.Metadata, expiresAtMetadata)) {
+		_ = s.Remove(key)
+		return false
+	}
+	return true
 
 }
 
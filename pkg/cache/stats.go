@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/gofrs/flock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	CacheLookupsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Number of cache lookups by tier and result (hit or miss)",
+	}, []string{"tier", "result"})
+	CacheErrorsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_errors_total",
+		Help: "Number of failed cache operations by tier and operation",
+	}, []string{"tier", "operation"})
+	CacheLatencyMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cache_operation_duration_seconds",
+		Help:    "Latency of cache operations by tier and operation",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 4, 8),
+	}, []string{"tier", "operation"})
+)
+
+const (
+	operationLookup = "lookup"
+	operationLoad   = "load"
+	operationStore  = "store"
+)
+
+// CacheStats are the counters of one cache tier.
+type CacheStats struct {
+	Tier   string `json:"tier"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
+	Loads  uint64 `json:"loads"`
+	Stores uint64 `json:"stores"`
+	// LoadTime and StoreTime are the total time spent loading and storing.
+	LoadTime  time.Duration `json:"loadTime"`
+	StoreTime time.Duration `json:"storeTime"`
+}
+
+// HitRatio is the share of lookups that were hits.
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// AverageLoadTime is the mean latency of a load.
+func (s CacheStats) AverageLoadTime() time.Duration {
+	if s.Loads == 0 {
+		return 0
+	}
+	return s.LoadTime / time.Duration(s.Loads)
+}
+
+// AverageStoreTime is the mean latency of a store.
+func (s CacheStats) AverageStoreTime() time.Duration {
+	if s.Stores == 0 {
+		return 0
+	}
+	return s.StoreTime / time.Duration(s.Stores)
+}
+
+func (s *CacheStats) add(other CacheStats) {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Errors += other.Errors
+	s.Loads += other.Loads
+	s.Stores += other.Stores
+	s.LoadTime += other.LoadTime
+	s.StoreTime += other.StoreTime
+}
+
+var (
+	// pendingStats are the counters of this process not yet saved by
+	// SaveStats, by tier.
+	pendingStats   = map[string]*CacheStats{}
+	pendingStatsMu sync.Mutex
+)
+
+func recordStats(tier string, update func(*CacheStats)) {
+	pendingStatsMu.Lock()
+	defer pendingStatsMu.Unlock()
+	stats, ok := pendingStats[tier]
+	if !ok {
+		stats = &CacheStats{Tier: tier}
+		pendingStats[tier] = stats
+	}
+	update(stats)
+}
+
+func recordLookup(tier string, hit bool, latency time.Duration) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheLookupsMetric.WithLabelValues(tier, result).Inc()
+	CacheLatencyMetric.WithLabelValues(tier, operationLookup).Observe(latency.Seconds())
+	recordStats(tier, func(s *CacheStats) {
+		if hit {
+			s.Hits++
+		} else {
+			s.Misses++
+		}
+	})
+}
+
+func recordOperation(tier, operation string, latency time.Duration, err error) {
+	CacheLatencyMetric.WithLabelValues(tier, operation).Observe(latency.Seconds())
+	// Stale entries are regenerated, they are not failures of the cache.
+	failed := err != nil && !IsStale(err)
+	if failed {
+		CacheErrorsMetric.WithLabelValues(tier, operation).Inc()
+	}
+	recordStats(tier, func(s *CacheStats) {
+		if failed {
+			s.Errors++
+		}
+		switch operation {
+		case operationLoad:
+			s.Loads++
+			s.LoadTime += latency
+		case operationStore:
+			s.Stores++
+			s.StoreTime += latency
+		}
+	})
+}
+
+// statsFile is where the counters of all runs are accumulated. It is kept
+// outside the directory of the file based cache so it is not listed as an
+// entry.
+func statsFile() (string, error) {
+	return xdg.CacheFile("k8sgpt-cache-stats.json")
+}
+
+func readStats(path string) (map[string]*CacheStats, error) {
+	stats := map[string]*CacheStats{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SaveStats adds the counters of this process to the saved ones. The file is
+// locked while it is read and rewritten, so concurrent runs add up rather than
+// overwrite each other.
+func SaveStats() error {
+	pendingStatsMu.Lock()
+	defer pendingStatsMu.Unlock()
+	if len(pendingStats) == 0 {
+		return nil
+	}
+
+	path, err := statsFile()
+	if err != nil {
+		return err
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	saved, err := readStats(path)
+	if err != nil {
+		return err
+	}
+	for tier, pending := range pendingStats {
+		if saved[tier] == nil {
+			saved[tier] = &CacheStats{Tier: tier}
+		}
+		saved[tier].add(*pending)
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	// Replace the file atomically so concurrent runs never read it partly
+	// written.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	pendingStats = map[string]*CacheStats{}
+	return nil
+}
+
+// GetStats returns the saved counters plus those of this process, sorted by
+// tier.
+func GetStats() ([]CacheStats, error) {
+	path, err := statsFile()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := readStats(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingStatsMu.Lock()
+	for tier, pending := range pendingStats {
+		if stats[tier] == nil {
+			stats[tier] = &CacheStats{Tier: tier}
+		}
+		stats[tier].add(*pending)
+	}
+	pendingStatsMu.Unlock()
+
+	result := make([]CacheStats, 0, len(stats))
+	for tier, s := range stats {
+		s.Tier = tier
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tier < result[j].Tier })
+	return result, nil
+}
+
+var _ (ICache) = (*statsCache)(nil)
+
+// statsCache records the lookups, loads and stores of a single cache under
+// tier.
+type statsCache struct {
+	ICache
+	tier string
+}
+
+func (s *statsCache) Exists(key string) bool {
+	start := time.Now()
+	exists := s.ICache.Exists(key)
+	recordLookup(s.tier, exists, time.Since(start))
+	return exists
+}
+
+func (s *statsCache) Load(key string) (string, error) {
+	start := time.Now()
+	data, err := s.ICache.Load(key)
+	recordOperation(s.tier, operationLoad, time.Since(start), err)
+	return data, err
+}
+
+func (s *statsCache) Store(key string, data string) error {
+	start := time.Now()
+	err := s.ICache.Store(key, data)
+	recordOperation(s.tier, operationStore, time.Since(start), err)
+	return err
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	localTier  = "local"
+	remoteTier = "remote"
+)
+
+var _ (ICache) = (*TieredCache)(nil)
+
+// TieredCache reads through a local cache in front of a remote one and
+// writes to both, so repeated lookups avoid a network round trip.
+type TieredCache struct {
+	noCache bool
+	local   ICache
+	remote  ICache
+}
+
+// NewTieredCache returns a cache reading through local in front of remote.
+// Both must be configured.
+func NewTieredCache(local, remote ICache) *TieredCache {
+	return &TieredCache{local: local, remote: remote}
+}
+
+func (t *TieredCache) Configure(cacheInfo CacheProvider) error {
+	if err := t.local.Configure(cacheInfo); err != nil {
+		return err
+	}
+	return t.remote.Configure(cacheInfo)
+}
+
+// Exists records a lookup of each tier it has to consult.
+func (t *TieredCache) Exists(key string) bool {
+	start := time.Now()
+	exists := t.local.Exists(key)
+	recordLookup(localTier, exists, time.Since(start))
+	if exists {
+		return true
+	}
+
+	start = time.Now()
+	exists = t.remote.Exists(key)
+	recordLookup(remoteTier, exists, time.Since(start))
+	return exists
+}
+
+func (t *TieredCache) Load(key string) (string, error) {
+	if t.local.Exists(key) {
+		start := time.Now()
+		data, err := t.local.Load(key)
+		recordOperation(localTier, operationLoad, time.Since(start), err)
+		if err == nil {
+			return data, nil
+		}
+	}
+
+	start := time.Now()
+	data, err := t.remote.Load(key)
+	recordOperation(remoteTier, operationLoad, time.Since(start), err)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep a local copy for the next lookup.
+	if err := t.storeTier(localTier, t.local, key, data); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: error while populating the local cache:", err)
+	}
+	return data, nil
+}
+
+func (t *TieredCache) Store(key string, data string) error {
+	return errors.Join(
+		t.storeTier(localTier, t.local, key, data),
+		t.storeTier(remoteTier, t.remote, key, data),
+	)
+}
+
+func (t *TieredCache) storeTier(tier string, cache ICache, key, data string) error {
+	start := time.Now()
+	err := cache.Store(key, data)
+	recordOperation(tier, operationStore, time.Since(start), err)
+	return err
+}
+
+// List lists the remote cache, which holds every entry.
+func (t *TieredCache) List() ([]CacheObjectDetails, error) {
+	return t.remote.List()
+}
+
+func (t *TieredCache) Remove(key string) error {
+	if t.local.Exists(key) {
+		if err := t.local.Remove(key); err != nil {
+			return err
+		}
+	}
+	return t.remote.Remove(key)
+}
+
+func (t *TieredCache) IsCacheDisabled() bool {
+	return t.noCache
+}
+
+func (t *TieredCache) GetName() string {
+	return fmt.Sprintf("%s+%s", t.local.GetName(), t.remote.GetName())
+}
+
+func (t *TieredCache) DisableCache() {
+	t.noCache = true
+}
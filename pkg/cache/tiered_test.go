@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache is a remote cache kept in memory.
+type memoryCache struct {
+	FileBasedCache
+	entries map[string]string
+	loads   int
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: map[string]string{}}
+}
+
+func (m *memoryCache) Configure(CacheProvider) error { return nil }
+
+func (m *memoryCache) Store(key string, data string) error {
+	m.entries[key] = data
+	return nil
+}
+
+func (m *memoryCache) Load(key string) (string, error) {
+	m.loads++
+	data, ok := m.entries[key]
+	if !ok {
+		return "", fmt.Errorf("%s not found", key)
+	}
+	return data, nil
+}
+
+func (m *memoryCache) Exists(key string) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+func (m *memoryCache) Remove(key string) error {
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *memoryCache) List() ([]CacheObjectDetails, error) {
+	var details []CacheObjectDetails
+	for key := range m.entries {
+		details = append(details, CacheObjectDetails{Name: key})
+	}
+	return details, nil
+}
+
+func (m *memoryCache) GetName() string { return "memory" }
+
+func resetStats() {
+	pendingStatsMu.Lock()
+	pendingStats = map[string]*CacheStats{}
+	pendingStatsMu.Unlock()
+}
+
+func statsByTier(t *testing.T) map[string]CacheStats {
+	stats, err := GetStats()
+	require.NoError(t, err)
+	byTier := map[string]CacheStats{}
+	for _, s := range stats {
+		byTier[s.Tier] = s
+	}
+	return byTier
+}
+
+func TestTieredCache(t *testing.T) {
+	local, _ := newTestFileCache(t, CacheProvider{})
+	resetStats()
+	remote := newMemoryCache()
+	c := NewTieredCache(local, remote)
+	require.Equal(t, "file+memory", c.GetName())
+
+	require.NoError(t, c.Store("key", "data"))
+	require.True(t, local.Exists("key"))
+	require.True(t, remote.Exists("key"))
+
+	// Reads are served locally.
+	require.True(t, c.Exists("key"))
+	data, err := c.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, "data", data)
+	require.Zero(t, remote.loads)
+
+	// Entries only in the remote cache are copied locally.
+	require.NoError(t, local.Remove("key"))
+	require.True(t, c.Exists("key"))
+	data, err = c.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, "data", data)
+	require.Equal(t, 1, remote.loads)
+	require.True(t, local.Exists("key"))
+
+	require.False(t, c.Exists("missing"))
+
+	stats := statsByTier(t)
+	require.Equal(t, uint64(1), stats[localTier].Hits)
+	require.Equal(t, uint64(2), stats[localTier].Misses)
+	require.Equal(t, uint64(1), stats[remoteTier].Hits)
+	require.Equal(t, uint64(1), stats[remoteTier].Misses)
+	require.Equal(t, uint64(2), stats[localTier].Stores, "the initial store and the copy")
+	require.Equal(t, uint64(1), stats[remoteTier].Loads)
+
+	require.NoError(t, c.Remove("key"))
+	require.False(t, local.Exists("key"))
+	require.False(t, remote.Exists("key"))
+}
+
+func TestCacheStats(t *testing.T) {
+	_, _ = newTestFileCache(t, CacheProvider{})
+	resetStats()
+	c := &statsCache{ICache: newMemoryCache(), tier: "memory"}
+	hits := testutil.ToFloat64(CacheLookupsMetric.WithLabelValues("memory", "hit"))
+	errors := testutil.ToFloat64(CacheErrorsMetric.WithLabelValues("memory", operationLoad))
+
+	require.NoError(t, c.Store("key", "data"))
+	require.True(t, c.Exists("key"))
+	require.False(t, c.Exists("missing"))
+	_, err := c.Load("missing")
+	require.Error(t, err)
+
+	require.Equal(t, hits+1, testutil.ToFloat64(CacheLookupsMetric.WithLabelValues("memory", "hit")))
+	require.Equal(t, errors+1, testutil.ToFloat64(CacheErrorsMetric.WithLabelValues("memory", operationLoad)))
+
+	// Saved statistics accumulate across runs.
+	require.NoError(t, SaveStats())
+	require.True(t, c.Exists("key"))
+	require.NoError(t, SaveStats())
+
+	stats := statsByTier(t)["memory"]
+	require.Equal(t, uint64(2), stats.Hits)
+	require.Equal(t, uint64(1), stats.Misses)
+	require.Equal(t, uint64(1), stats.Errors)
+	require.Equal(t, uint64(1), stats.Stores)
+	require.InDelta(t, 2.0/3.0, stats.HitRatio(), 0.001)
+}
+
+func TestSaveStatsLocked(t *testing.T) {
+	_, _ = newTestFileCache(t, CacheProvider{})
+	resetStats()
+	recordLookup("memory", true, 0)
+
+	// Another run holds the lock of the statistics file.
+	path, err := statsFile()
+	require.NoError(t, err)
+	lock := flock.New(path + ".lock")
+	require.NoError(t, lock.Lock())
+
+	saved := make(chan error, 1)
+	go func() { saved <- SaveStats() }()
+	select {
+	case <-saved:
+		t.Fatal("SaveStats did not wait for the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoError(t, lock.Unlock())
+	require.NoError(t, <-saved)
+	require.Equal(t, uint64(1), statsByTier(t)["memory"].Hits)
+}
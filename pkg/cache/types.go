@@ -6,6 +6,19 @@ type CacheProvider struct {
 	GCS   GCSCacheConfiguration   `mapstructucre:"gcs" yaml:"gcs,omitempty"`
 	Azure AzureCacheConfiguration `mapstructucre:"azure" yaml:"azure,omitempty"`
 	S3    S3CacheConfiguration    `mapstructucre:"s3" yaml:"s3,omitempty"`
+	Redis RedisCacheConfiguration `mapstructure:"redis" yaml:"redis,omitempty"`
+	// TTL is how long an entry is kept after it is stored, e.g. "168h".
+	// Entries never expire when it is empty.
+	TTL string `mapstructure:"ttl" yaml:"ttl,omitempty"`
+	// MaxSize bounds the size of the file based cache, e.g. "100Mi". The
+	// least recently used entries are evicted first.
+	MaxSize string `mapstructure:"maxsize" yaml:"maxsize,omitempty"`
+	// Encryption configures where the key encrypting cache values is read
+	// from. Values are stored in plain text when it is empty.
+	Encryption CacheEncryptionConfiguration `mapstructure:"encryption" yaml:"encryption,omitempty"`
+	// Tiered reads through a local file based cache in front of the remote
+	// cache and writes to both.
+	Tiered bool `mapstructure:"tiered" yaml:"tiered,omitempty"`
 }
 
 type CacheObjectDetails struct {
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	digitsPattern     = regexp.MustCompile(`[0-9]+`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// Fingerprint identifies the object a result was reported for. It is stable
+// across runs as long as the cluster, kind and namespace/name do not change.
+func (r Result) Fingerprint() string {
+	if r.Cluster != "" {
+		return fingerprint(r.Cluster, r.Kind, r.Name)
+	}
+	return fingerprint(r.Kind, r.Name)
+}
+
+// FailureFingerprint identifies a single failure of the result. Results of
+// single-cluster analyses keep the fingerprint of Failure.Fingerprint.
+func (r Result) FailureFingerprint(f Failure) string {
+	if r.Cluster != "" {
+		return fingerprint(r.Cluster, r.Kind, r.Name, NormalizeFailureText(f.Text))
+	}
+	return f.Fingerprint(r.Kind, r.Name)
+}
+
+// Fingerprint identifies a single failure of the object described by kind and
+// name. Numbers in the failure text are ignored, so counters, timestamps and
+// durations that change between runs do not produce a new fingerprint.
+func (f Failure) Fingerprint(kind string, name string) string {
+	return fingerprint(kind, name, NormalizeFailureText(f.Text))
+}
+
+// NormalizeFailureText lowercases text, collapses whitespace and replaces
+// every run of digits with '#'.
+func NormalizeFailureText(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = whitespacePattern.ReplaceAllString(text, " ")
+	return digitsPattern.ReplaceAllString(text, "#")
+}
+
+func fingerprint(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(hash[:8])
+}
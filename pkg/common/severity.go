@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
+// ParseSeverity converts a user supplied severity name, case-insensitively.
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("unknown severity %q. Available severities info,warning,critical", s)
+	}
+	return severity, nil
+}
+
+// Level returns the severity of the failure. Failures from analyzers that do
+// not set one, such as custom analyzers, are treated as warnings.
+func (f Failure) Level() Severity {
+	if _, ok := severityRanks[f.Severity]; !ok {
+		return SeverityWarning
+	}
+	return f.Severity
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	rank, ok := severityRanks[s]
+	if !ok {
+		rank = severityRanks[SeverityWarning]
+	}
+	return rank >= severityRanks[min]
+}
+
+// MaxSeverity returns the highest severity among the failures of r.
+func (r Result) MaxSeverity() Severity {
+	max := SeverityInfo
+	for _, failure := range r.Error {
+		if level := failure.Level(); level.AtLeast(max) {
+			max = level
+		}
+	}
+	return max
+}
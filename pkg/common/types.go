@@ -45,6 +45,18 @@ type Analyzer struct {
 	PreAnalysis   map[string]PreAnalysis
 	Results       []Result
 	OpenapiSchema *openapi_v2.Document
+	// Store is shared by the analyzers of a run, see GetStore.
+	Store *kubernetes.Store
+}
+
+// GetStore returns the store shared by the analyzers of the run. An analyzer
+// run without one gets a store of its own, created on first use and kept in
+// Store for its later calls.
+func (a *Analyzer) GetStore() *kubernetes.Store {
+	if a.Store == nil {
+		a.Store = kubernetes.NewStore(a.Context, a.Client, a.Namespace)
+	}
+	return a.Store
 }
 
 type PreAnalysis struct {
@@ -79,6 +91,12 @@ type Result struct {
 	Error        []Failure `json:"error"`
 	Details      string    `json:"details"`
 	ParentObject string    `json:"parentObject"`
+	// Backend is the AI backend that explained the result, if it was not
+	// served from the cache.
+	Backend string `json:"backend,omitempty"`
+	// Cluster is the kubeconfig context of the cluster the result was found
+	// in, set only by multi-cluster analyses.
+	Cluster string `json:"cluster,omitempty"`
 }
 
 type AnalysisStats struct {
@@ -90,8 +108,17 @@ type Failure struct {
 	Text          string
 	KubernetesDoc string
 	Sensitive     []Sensitive
+	Severity      Severity
 }
 
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
 type Sensitive struct {
 	Unmasked string
 	Masked   string
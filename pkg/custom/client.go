@@ -4,21 +4,49 @@ import (
 	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
 	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"strings"
 )
 
+// RunOptions scope a run to the objects of the analysis.
+type RunOptions struct {
+	Namespace     string
+	LabelSelector string
+	Filters       []string
+}
+
 type Client struct {
 	c              *grpc.ClientConn
 	analyzerClient rpc.CustomAnalyzerServiceClient
 }
 
 func NewClient(c Connection) (*Client, error) {
+	options := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if c.TLS {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+	if c.TokenEnv != "" || c.TokenFile != "" {
+		if !c.TLS {
+			return nil, errors.New("bearer authentication requires TLS")
+		}
+		options = append(options, grpc.WithPerRPCCredentials(bearerToken{env: c.TokenEnv, file: c.TokenFile}))
+	}
 
 	//nolint:staticcheck // Ignoring SA1019 for compatibility reasons
-	conn, err := grpc.Dial(fmt.Sprintf("%s:%s", c.Url, c.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%s", c.Url, c.Port), options...)
 
 	if err != nil {
 		return nil, err
@@ -30,29 +58,107 @@ func NewClient(c Connection) (*Client, error) {
 	}, nil
 }
 
-func (cli *Client) Run() (common.Result, error) {
-	var result common.Result
-	req := &schemav1.RunRequest{}
-	res, err := cli.analyzerClient.Run(context.Background(), req)
+func (c Connection) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // opt-in for self-signed analyzers
+	}
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading the CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// bearerToken authenticates requests with a token from an environment
+// variable or a file. The file is read for every request so that rotated
+// tokens, e.g. projected service account tokens, are picked up.
+type bearerToken struct {
+	env  string
+	file string
+}
+
+func (b bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token := os.Getenv(b.env)
+	if b.file != "" {
+		data, err := os.ReadFile(b.file)
+		if err != nil {
+			return nil, fmt.Errorf("reading the token file: %w", err)
+		}
+		token = string(data)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New("the bearer token is empty")
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+func (cli *Client) Close() error {
+	return cli.c.Close()
+}
+
+// Run runs the analyzer until ctx is done and returns its results. The
+// options reach analyzers through the temporary shimRunRequest.
+func (cli *Client) Run(ctx context.Context, options RunOptions) ([]common.Result, error) {
+	res, err := cli.analyzerClient.Run(ctx, shimRunRequest(options).proto())
 	if err != nil {
-		return result, err
+		return nil, err
 	}
-	if res.Result != nil {
+	response, err := readShimRunResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]common.Result, 0, len(response.Results))
+	for _, result := range response.Results {
+		results = append(results, toResult(result))
+	}
+	return results, nil
+}
 
-		// We should refactor this, because Error and Failure do not map 1:1 from K8sGPT/schema
-		var errorsFound []common.Failure
-		for _, e := range res.Result.Error {
-			errorsFound = append(errorsFound, common.Failure{
-				Text: e.Text,
-				// TODO: Support sensitive data
+func toResult(res *schemav1.Result) common.Result {
+	var errorsFound []common.Failure
+	for _, e := range res.Error {
+		failure := common.Failure{
+			Text: e.Text,
+		}
+		for _, s := range e.Sensitive {
+			masked := s.Masked
+			if masked == "" {
+				masked = util.MaskString(s.Unmasked)
+			}
+			failure.Sensitive = append(failure.Sensitive, common.Sensitive{
+				Unmasked: s.Unmasked,
+				Masked:   masked,
 			})
 		}
+		errorsFound = append(errorsFound, failure)
+	}
 
-		result.Name = res.Result.Name
-		result.Kind = res.Result.Kind
-		result.Details = res.Result.Details
-		result.ParentObject = res.Result.ParentObject
-		result.Error = errorsFound
+	return common.Result{
+		Name:         res.Name,
+		Kind:         res.Kind,
+		Details:      res.Details,
+		ParentObject: res.ParentObject,
+		Error:        errorsFound,
 	}
-	return result, nil
 }
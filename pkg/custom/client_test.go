@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
+	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+type testAnalyzer struct {
+	rpc.UnimplementedCustomAnalyzerServiceServer
+	token   string
+	delay   time.Duration
+	request RunOptions
+}
+
+func (s *testAnalyzer) Run(ctx context.Context, req *schemav1.RunRequest) (*schemav1.RunResponse, error) {
+	if s.token != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if auth := md.Get("authorization"); len(auth) == 0 || auth[0] != "Bearer "+s.token {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// Read the request fields that the schema does not define yet.
+	unknown := req.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		number, _, n := protowire.ConsumeTag(unknown)
+		unknown = unknown[n:]
+		value, n := protowire.ConsumeString(unknown)
+		unknown = unknown[n:]
+		switch number {
+		case runRequestNamespaceField:
+			s.request.Namespace = value
+		case runRequestLabelSelectorField:
+			s.request.LabelSelector = value
+		case runRequestFiltersField:
+			s.request.Filters = append(s.request.Filters, value)
+		}
+	}
+
+	res := &schemav1.RunResponse{Result: &schemav1.Result{
+		Kind: "Pod",
+		Name: "default/web",
+		Error: []*schemav1.ErrorDetail{{
+			Text:      "web runs as root",
+			Sensitive: []*schemav1.SensitiveData{{Unmasked: "web"}},
+		}},
+	}}
+	var results []byte
+	for _, name := range []string{"default/api", "default/db"} {
+		result, err := proto.Marshal(&schemav1.Result{Kind: "Pod", Name: name})
+		if err != nil {
+			return nil, err
+		}
+		results = protowire.AppendTag(results, runResponseResultsField, protowire.BytesType)
+		results = protowire.AppendBytes(results, result)
+	}
+	res.ProtoReflect().SetUnknown(results)
+	return res, nil
+}
+
+func startTestAnalyzer(t *testing.T, analyzer *testAnalyzer, options ...grpc.ServerOption) Connection {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(options...)
+	rpc.RegisterCustomAnalyzerServiceServer(server, analyzer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return Connection{Url: "127.0.0.1", Port: strconv.Itoa(addr.Port)}
+}
+
+// writeCertificate writes a certificate for localhost signed by parent, or
+// self-signed if parent is nil, and returns its paths.
+func writeCertificate(t *testing.T, dir, name string, parent *tls.Certificate) (tls.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	certificate.Leaf, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, certFile, keyFile
+}
+
+func TestClientRun(t *testing.T) {
+	analyzer := &testAnalyzer{}
+	client, err := NewClient(startTestAnalyzer(t, analyzer))
+	require.NoError(t, err)
+	defer client.Close()
+
+	results, err := client.Run(context.Background(), RunOptions{
+		Namespace:     "default",
+		LabelSelector: "app=web",
+		Filters:       []string{"Pod", "Service"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, RunOptions{Namespace: "default", LabelSelector: "app=web", Filters: []string{"Pod", "Service"}}, analyzer.request)
+
+	require.Len(t, results, 3)
+	require.Equal(t, "default/web", results[0].Name)
+	require.Equal(t, "web", results[0].Error[0].Sensitive[0].Unmasked)
+	require.NotEmpty(t, results[0].Error[0].Sensitive[0].Masked)
+	require.NotEqual(t, "web", results[0].Error[0].Sensitive[0].Masked)
+	require.Equal(t, "default/api", results[1].Name)
+	require.Equal(t, "default/db", results[2].Name)
+}
+
+func TestClientRunTimeout(t *testing.T) {
+	client, err := NewClient(startTestAnalyzer(t, &testAnalyzer{delay: time.Minute}))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.Run(ctx, RunOptions{})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caFile, _ := writeCertificate(t, dir, "ca", nil)
+	serverCert, _, _ := writeCertificate(t, dir, "server", &ca)
+	_, certFile, keyFile := writeCertificate(t, dir, "client", &ca)
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	connection := startTestAnalyzer(t, &testAnalyzer{token: "secret"}, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    roots,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+
+	// The client must present a certificate.
+	connection.TLS = true
+	connection.CAFile = caFile
+	connection.TokenFile = tokenFile
+	client, err := NewClient(connection)
+	require.NoError(t, err)
+	_, err = client.Run(context.Background(), RunOptions{})
+	require.Error(t, err)
+	client.Close()
+
+	connection.CertFile, connection.KeyFile = certFile, keyFile
+	client, err = NewClient(connection)
+	require.NoError(t, err)
+	results, err := client.Run(context.Background(), RunOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	client.Close()
+
+	// A wrong token is rejected by the analyzer.
+	connection.TokenFile = ""
+	connection.TokenEnv = "K8SGPT_TEST_ANALYZER_TOKEN"
+	t.Setenv("K8SGPT_TEST_ANALYZER_TOKEN", "wrong")
+	client, err = NewClient(connection)
+	require.NoError(t, err)
+	_, err = client.Run(context.Background(), RunOptions{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	client.Close()
+}
+
+func TestClientBearerRequiresTLS(t *testing.T) {
+	_, err := NewClient(Connection{Url: "localhost", Port: "8085", TokenEnv: "TOKEN"})
+	require.EqualError(t, err, "bearer authentication requires TLS")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultProbeTimeout bounds the probe of an analyzer before it runs.
+const DefaultProbeTimeout = 5 * time.Second
+
+const (
+	healthStatusServing = "SERVING"
+	// healthStatusUnknown is reported by analyzers without a health service.
+	healthStatusUnknown = "UNKNOWN"
+	// healthStatusVerified is reported by WebAssembly analyzers whose module
+	// matches its digest.
+	healthStatusVerified = "VERIFIED"
+	capabilityWasm       = "wasm"
+)
+
+// Health is the outcome of a probe of an analyzer.
+type Health struct {
+	// Status is the status reported by the gRPC health service, UNKNOWN if
+	// the analyzer does not implement it.
+	Status       string
+	Name         string
+	Version      string
+	Capabilities []string
+	Latency      time.Duration
+}
+
+// Serving reports whether the analyzer can run.
+func (h Health) Serving() bool {
+	return h.Status == healthStatusServing || h.Status == healthStatusUnknown || h.Status == healthStatusVerified
+}
+
+// Check probes the analyzer through the standard gRPC health service and
+// asks it to describe itself through the temporary shimDescribe. Analyzers
+// without a health service are reported with an unknown status, analyzers
+// without Describe with no capabilities; both can run. An error means that
+// the analyzer could not be reached or refused the probe.
+func (cli *Client) Check(ctx context.Context) (Health, error) {
+	start := time.Now()
+	health := Health{Status: healthStatusUnknown}
+
+	res, err := grpc_health_v1.NewHealthClient(cli.c).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: rpc.CustomAnalyzerService_ServiceDesc.ServiceName,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		health.Status = res.Status.String()
+	case codes.Unimplemented, codes.NotFound:
+		// no health service, or the service is not registered with it
+	default:
+		return health, probeError(err)
+	}
+
+	description, err := shimDescribe(ctx, cli.c)
+	if err != nil {
+		return health, err
+	}
+	health.Name, health.Version, health.Capabilities = description.Name, description.Version, description.Capabilities
+	health.Latency = time.Since(start)
+	return health, nil
+}
+
+func probeError(err error) error {
+	s := status.Convert(err)
+	return fmt.Errorf("%s: %s", s.Code(), s.Message())
+}
+
+// Check verifies the module against its digest and its exports.
+func (c *WasmClient) Check(ctx context.Context) (Health, error) {
+	start := time.Now()
+	if _, err := validateWasmModule(ctx, c.module); err != nil {
+		return Health{}, err
+	}
+	return Health{
+		Status:       healthStatusVerified,
+		Name:         c.name,
+		Capabilities: []string{capabilityWasm},
+		Latency:      time.Since(start),
+	}, nil
+}
+
+// Probe checks a configured analyzer within DefaultProbeTimeout.
+func Probe(ctx context.Context, analyzer CustomAnalyzer) (Health, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultProbeTimeout)
+	defer cancel()
+
+	if analyzer.Wasm.Path != "" {
+		client, err := NewWasmClient(analyzer.Name, analyzer.Wasm)
+		if err != nil {
+			return Health{}, err
+		}
+		return client.Check(ctx)
+	}
+	client, err := NewClient(analyzer.Connection)
+	if err != nil {
+		return Health{}, err
+	}
+	defer client.Close()
+	return client.Check(ctx)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// describeHandler serves Describe, which the generated service lacks.
+func describeHandler(srv interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	if method != describeMethod {
+		return status.Error(codes.Unimplemented, method)
+	}
+	if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+		return err
+	}
+	var fields []byte
+	fields = appendStringField(fields, describeNameField, "host-analyzer")
+	fields = appendStringField(fields, describeVersionField, "v1.2.0")
+	fields = appendStringField(fields, describeCapabilitiesField, "Node")
+	fields = appendStringField(fields, describeCapabilitiesField, "scoped")
+	fields = protowire.AppendTag(fields, 4, protowire.VarintType)
+	fields = protowire.AppendVarint(fields, 1)
+	response := &emptypb.Empty{}
+	response.ProtoReflect().SetUnknown(fields)
+	return stream.SendMsg(response)
+}
+
+func TestCheck(t *testing.T) {
+	connection := startTestAnalyzer(t, &testAnalyzer{}, grpc.UnknownServiceHandler(describeHandler))
+	client, err := NewClient(connection)
+	require.NoError(t, err)
+	defer client.Close()
+
+	result, err := client.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "UNKNOWN", result.Status)
+	require.True(t, result.Serving())
+	require.Equal(t, "host-analyzer", result.Name)
+	require.Equal(t, "v1.2.0", result.Version)
+	require.Equal(t, []string{"Node", "scoped"}, result.Capabilities)
+
+	// Analyzers with a health service report its status.
+	healthServer := health.NewServer()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	rpc.RegisterCustomAnalyzerServiceServer(server, &testAnalyzer{})
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	healthServer.SetServingStatus(rpc.CustomAnalyzerService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	analyzer := CustomAnalyzer{Name: "host-analyzer", Connection: Connection{
+		Url:  "127.0.0.1",
+		Port: strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
+	}}
+	result, err = Probe(context.Background(), analyzer)
+	require.NoError(t, err)
+	require.Equal(t, "NOT_SERVING", result.Status)
+	require.False(t, result.Serving())
+	require.Empty(t, result.Version)
+
+	healthServer.SetServingStatus(rpc.CustomAnalyzerService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	result, err = Probe(context.Background(), analyzer)
+	require.NoError(t, err)
+	require.True(t, result.Serving())
+}
+
+func TestCheckWithoutDescribe(t *testing.T) {
+	// The generated service answers Describe with Unimplemented.
+	client, err := NewClient(startTestAnalyzer(t, &testAnalyzer{}))
+	require.NoError(t, err)
+	defer client.Close()
+
+	result, err := client.Check(context.Background())
+	require.NoError(t, err)
+	require.True(t, result.Serving())
+	require.Equal(t, "UNKNOWN", result.Status)
+	require.Empty(t, result.Name)
+	require.Empty(t, result.Capabilities)
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, listener.Close())
+
+	_, err = Probe(context.Background(), CustomAnalyzer{Connection: Connection{Url: "127.0.0.1", Port: port}})
+	require.ErrorContains(t, err, "Unavailable")
+}
+
+func TestProbeWasm(t *testing.T) {
+	path := testWasmModule(t, "{}", true)
+	digest, err := ValidateWasmModule(context.Background(), path)
+	require.NoError(t, err)
+
+	result, err := Probe(context.Background(), CustomAnalyzer{Name: "sandbox", Wasm: WasmModule{Path: path, Digest: digest}})
+	require.NoError(t, err)
+	require.Equal(t, "VERIFIED", result.Status)
+	require.Equal(t, []string{"wasm"}, result.Capabilities)
+
+	_, err = Probe(context.Background(), CustomAnalyzer{Name: "sandbox", Wasm: WasmModule{Path: path, Digest: WasmDigest(nil)}})
+	require.ErrorContains(t, err, "does not match the digest")
+}
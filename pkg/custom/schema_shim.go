@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+
+	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
+	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TEMPORARY: this file stands in for changes to the custom analyzer schema
+// (buf.build/k8sgpt-ai/k8sgpt, schema/v1) that are not published yet. The
+// published RunRequest has no fields, RunResponse holds a single result and
+// CustomAnalyzerService has no Describe RPC, so the client encodes them by
+// hand, as the schema is expected to declare them:
+//
+//	service CustomAnalyzerService {
+//	  rpc Run(RunRequest) returns (RunResponse);
+//	  rpc Describe(DescribeRequest) returns (DescribeResponse);
+//	}
+//	message RunRequest {
+//	  string namespace = 1;
+//	  string label_selector = 2;
+//	  repeated string filters = 3;
+//	}
+//	message RunResponse {
+//	  Result result = 1;
+//	  repeated Result results = 2;
+//	}
+//	message DescribeRequest {}
+//	message DescribeResponse {
+//	  string name = 1;
+//	  string version = 2;
+//	  repeated string capabilities = 3;
+//	}
+//
+// Analyzers generated from that schema read and write these fields without
+// changes, while analyzers built against the published schema ignore the
+// request fields, return a single result and answer Describe with
+// Unimplemented. The published Run RPC is
+// unary, so every result comes back in one response; streaming them needs a
+// server streaming RPC in the schema.
+//
+// Once the schema is published and regenerated, use the generated messages
+// in Client.Run and the generated Describe in Client.Check, remove the shim
+// types below and delete this file.
+const (
+	runRequestNamespaceField     protowire.Number = 1
+	runRequestLabelSelectorField protowire.Number = 2
+	runRequestFiltersField       protowire.Number = 3
+	runResponseResultsField      protowire.Number = 2
+
+	describeNameField         protowire.Number = 1
+	describeVersionField      protowire.Number = 2
+	describeCapabilitiesField protowire.Number = 3
+)
+
+var describeMethod = "/" + rpc.CustomAnalyzerService_ServiceDesc.ServiceName + "/Describe"
+
+// shimRunRequest is the RunRequest of the pending schema.
+type shimRunRequest RunOptions
+
+// proto encodes r as a published RunRequest with unknown fields.
+func (r shimRunRequest) proto() *schemav1.RunRequest {
+	req := &schemav1.RunRequest{}
+	var fields []byte
+	fields = appendStringField(fields, runRequestNamespaceField, r.Namespace)
+	fields = appendStringField(fields, runRequestLabelSelectorField, r.LabelSelector)
+	for _, filter := range r.Filters {
+		fields = appendStringField(fields, runRequestFiltersField, filter)
+	}
+	req.ProtoReflect().SetUnknown(fields)
+	return req
+}
+
+// shimRunResponse is the RunResponse of the pending schema.
+type shimRunResponse struct {
+	Results []*schemav1.Result
+}
+
+// readShimRunResponse decodes the results of res, the single result of the
+// published schema first.
+func readShimRunResponse(res *schemav1.RunResponse) (shimRunResponse, error) {
+	var response shimRunResponse
+	if res.Result != nil {
+		response.Results = append(response.Results, res.Result)
+	}
+	unknown := res.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		number, wireType, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			return response, protowire.ParseError(n)
+		}
+		unknown = unknown[n:]
+		if number == runResponseResultsField && wireType == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(unknown)
+			if n < 0 {
+				return response, protowire.ParseError(n)
+			}
+			result := &schemav1.Result{}
+			if err := proto.Unmarshal(value, result); err != nil {
+				return response, fmt.Errorf("reading the results: %w", err)
+			}
+			response.Results = append(response.Results, result)
+			unknown = unknown[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(number, wireType, unknown)
+		if n < 0 {
+			return response, protowire.ParseError(n)
+		}
+		unknown = unknown[n:]
+	}
+	return response, nil
+}
+
+// shimDescribeResponse is the DescribeResponse of the pending schema.
+type shimDescribeResponse struct {
+	Name         string
+	Version      string
+	Capabilities []string
+}
+
+// shimDescribe calls Describe on conn. An analyzer that does not implement it
+// is healthy and has no capabilities, so Unimplemented yields an empty
+// description rather than an error.
+func shimDescribe(ctx context.Context, conn *grpc.ClientConn) (shimDescribeResponse, error) {
+	var response shimDescribeResponse
+	describe := &emptypb.Empty{}
+	err := conn.Invoke(ctx, describeMethod, &emptypb.Empty{}, describe)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unimplemented:
+		return response, nil
+	default:
+		return response, probeError(err)
+	}
+
+	b := describe.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return response, fmt.Errorf("reading the description: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if wireType != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(number, wireType, b)
+			if n < 0 {
+				return response, fmt.Errorf("reading the description: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeString(b)
+		if n < 0 {
+			return response, fmt.Errorf("reading the description: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch number {
+		case describeNameField:
+			response.Name = value
+		case describeVersionField:
+			response.Version = value
+		case describeCapabilitiesField:
+			response.Capabilities = append(response.Capabilities, value)
+		}
+	}
+	return response, nil
+}
+
+func appendStringField(b []byte, number protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, number, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
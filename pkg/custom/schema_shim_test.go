@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	rpc "buf.build/gen/go/k8sgpt-ai/k8sgpt/grpc/go/schema/v1/schemav1grpc"
+	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// pendingSchema describes the custom analyzer schema that schema_shim.go
+// stands in for. The field numbers are spelled out here rather than taken
+// from the shim, so changing them on either side fails the round trip.
+func pendingSchema(t *testing.T) protoreflect.FileDescriptor {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     kind.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	const (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		str      = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		message  = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("schema/v1/custom_analyzer_pending.proto"),
+		Package:    proto.String("schema.v1"),
+		Dependency: []string{schemav1.File_schema_v1_server_analyzer_service_proto.Path()},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("RunRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("namespace", 1, optional, str, ""),
+				field("label_selector", 2, optional, str, ""),
+				field("filters", 3, repeated, str, ""),
+			}},
+			{Name: proto.String("RunResponse"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("result", 1, optional, message, ".schema.v1.Result"),
+				field("results", 2, repeated, message, ".schema.v1.Result"),
+			}},
+			{Name: proto.String("DescribeRequest")},
+			{Name: proto.String("DescribeResponse"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, optional, str, ""),
+				field("version", 2, optional, str, ""),
+				field("capabilities", 3, repeated, str, ""),
+			}},
+		},
+	}
+	descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return descriptor
+}
+
+// startPendingAnalyzer serves the pending schema through dynamic messages,
+// as an analyzer generated from it would, and records the run requests.
+func startPendingAnalyzer(t *testing.T, requests *[]RunOptions) Connection {
+	messages := pendingSchema(t).Messages()
+	newMessage := func(name protoreflect.Name) *dynamicpb.Message {
+		return dynamicpb.NewMessage(messages.ByName(name))
+	}
+	stringList := func(list protoreflect.List) []string {
+		var values []string
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, list.Get(i).String())
+		}
+		return values
+	}
+
+	service := grpc.ServiceDesc{
+		ServiceName: rpc.CustomAnalyzerService_ServiceDesc.ServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Run",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := newMessage("RunRequest")
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				fields := req.Descriptor().Fields()
+				*requests = append(*requests, RunOptions{
+					Namespace:     req.Get(fields.ByName("namespace")).String(),
+					LabelSelector: req.Get(fields.ByName("label_selector")).String(),
+					Filters:       stringList(req.Get(fields.ByName("filters")).List()),
+				})
+
+				res := newMessage("RunResponse")
+				fields = res.Descriptor().Fields()
+				res.Set(fields.ByName("result"), protoreflect.ValueOfMessage((&schemav1.Result{Kind: "Pod", Name: "default/web"}).ProtoReflect()))
+				results := res.Mutable(fields.ByName("results")).List()
+				for _, name := range []string{"default/api", "default/db"} {
+					results.Append(protoreflect.ValueOfMessage((&schemav1.Result{Kind: "Pod", Name: name}).ProtoReflect()))
+				}
+				return res, nil
+			},
+		}, {
+			MethodName: "Describe",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				if err := dec(newMessage("DescribeRequest")); err != nil {
+					return nil, err
+				}
+				res := newMessage("DescribeResponse")
+				fields := res.Descriptor().Fields()
+				res.Set(fields.ByName("name"), protoreflect.ValueOfString("pending-analyzer"))
+				res.Set(fields.ByName("version"), protoreflect.ValueOfString("v2.0.0"))
+				capabilities := res.Mutable(fields.ByName("capabilities")).List()
+				capabilities.Append(protoreflect.ValueOfString("Pod"))
+				capabilities.Append(protoreflect.ValueOfString("filters"))
+				return res, nil
+			},
+		}},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	server.RegisterService(&service, struct{}{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return Connection{Url: "127.0.0.1", Port: strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)}
+}
+
+func TestShimPendingSchema(t *testing.T) {
+	var requests []RunOptions
+	client, err := NewClient(startPendingAnalyzer(t, &requests))
+	require.NoError(t, err)
+	defer client.Close()
+
+	options := RunOptions{Namespace: "default", LabelSelector: "app=web", Filters: []string{"Pod", "Service"}}
+	results, err := client.Run(context.Background(), options)
+	require.NoError(t, err)
+	require.Equal(t, []RunOptions{options}, requests)
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	require.Equal(t, []string{"default/web", "default/api", "default/db"}, names)
+
+	health, err := client.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "pending-analyzer", health.Name)
+	require.Equal(t, "v2.0.0", health.Version)
+	require.Equal(t, []string{"Pod", "filters"}, health.Capabilities)
+}
@@ -1,10 +1,55 @@
 package custom
 
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeout bounds a run of a custom analyzer without a timeout.
+const DefaultTimeout = 30 * time.Second
+
 type Connection struct {
 	Url  string `json:"url"`
 	Port string `json:"port"`
+	// TLS enables transport security. CAFile verifies the analyzer instead of
+	// the system roots, CertFile and KeyFile authenticate K8sGPT to it.
+	TLS                bool   `json:"tls"`
+	CAFile             string `json:"caFile"`
+	CertFile           string `json:"certFile"`
+	KeyFile            string `json:"keyFile"`
+	ServerName         string `json:"serverName"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	// TokenEnv names the environment variable, TokenFile the file, that
+	// holds a bearer token sent with every request.
+	TokenEnv  string `json:"tokenEnv"`
+	TokenFile string `json:"tokenFile"`
 }
 type CustomAnalyzer struct {
 	Name       string     `json:"name"`
 	Connection Connection `json:"connection"`
+	Wasm       WasmModule `json:"wasm"`
+	// Timeout bounds each run of the analyzer, DefaultTimeout if empty.
+	Timeout string `json:"timeout"`
+}
+
+// WasmModule is a WebAssembly analyzer, pinned to the digest of the module
+// that was added.
+type WasmModule struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+	// Kinds are the kinds the module may list, as Kind or Kind.group. Every
+	// kind but Secrets may be listed when it is empty.
+	Kinds []string `json:"kinds"`
+}
+
+// GetTimeout returns the timeout of a run of the analyzer.
+func (c CustomAnalyzer) GetTimeout() (time.Duration, error) {
+	if c.Timeout == "" {
+		return DefaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q", c.Timeout)
+	}
+	return timeout, nil
 }
@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// A WebAssembly analyzer is a module that exports:
+//
+//   - memory
+//   - k8sgpt_alloc(size i32) i32, which returns a buffer of size bytes that
+//     the host writes its input to
+//   - k8sgpt_analyze(ptr i32, len i32) i64, which receives a WasmRunRequest
+//     and returns a WasmRunResponse
+//
+// and may import k8sgpt.list(ptr i32, len i32) i64, which receives a
+// WasmListRequest and returns a WasmListResponse. Requests and responses are
+// JSON; a response is returned as ptr<<32|len of a buffer in the memory of
+// the module. Modules built for WASI can run as well, without access to the
+// file system, the network or the environment.
+const (
+	wasmHostModule  = "k8sgpt"
+	wasmAllocExport = "k8sgpt_alloc"
+	wasmRunExport   = "k8sgpt_analyze"
+	wasmDigestAlgo  = "sha256:"
+	// wasmMemoryLimitPages limits the memory of a module to 256MiB.
+	wasmMemoryLimitPages = 4096
+)
+
+type WasmRunRequest struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+}
+
+type WasmRunResponse struct {
+	Results []common.Result `json:"results"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// WasmListRequest asks the host for objects of a kind. The namespace and
+// label selector of the analysis apply to every request; whether the kind
+// is namespaced is read from the API server.
+type WasmListRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Resource is the plural resource of the objects, for requests without
+	// a Kind.
+	Resource      string `json:"resource,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+type WasmListResponse struct {
+	Items []map[string]interface{} `json:"items"`
+	Error string                   `json:"error,omitempty"`
+}
+
+// wasmDeniedKinds can only be listed by modules that were allowed them
+// explicitly when they were added.
+var wasmDeniedKinds = []schema.GroupKind{{Kind: "Secret"}}
+
+// WasmClient runs a WebAssembly analyzer in-process.
+type WasmClient struct {
+	name   string
+	module []byte
+	kinds  []schema.GroupKind
+}
+
+// NewWasmClient reads the module of m and checks that it has not changed
+// since it was added.
+func NewWasmClient(name string, m WasmModule) (*WasmClient, error) {
+	kinds, err := ParseWasmKinds(m.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	module, err := os.ReadFile(m.Path)
+	if err != nil {
+		return nil, err
+	}
+	if m.Digest != "" && m.Digest != WasmDigest(module) {
+		return nil, fmt.Errorf("the module %s does not match the digest %s; add it again to use the new version", m.Path, m.Digest)
+	}
+	return &WasmClient{name: name, module: module, kinds: kinds}, nil
+}
+
+// ParseWasmKinds parses the kinds a module may list, written as Kind or
+// Kind.group, e.g. Pod or Deployment.apps.
+func ParseWasmKinds(kinds []string) ([]schema.GroupKind, error) {
+	parsed := make([]schema.GroupKind, 0, len(kinds))
+	for _, kind := range kinds {
+		gk := schema.ParseGroupKind(strings.TrimSpace(kind))
+		if gk.Kind == "" {
+			return nil, fmt.Errorf("invalid kind %q, expected Kind or Kind.group", kind)
+		}
+		parsed = append(parsed, gk)
+	}
+	return parsed, nil
+}
+
+// WasmDigest returns the digest a module is pinned to.
+func WasmDigest(module []byte) string {
+	sum := sha256.Sum256(module)
+	return wasmDigestAlgo + hex.EncodeToString(sum[:])
+}
+
+// ValidateWasmModule compiles the module at path and checks its exports. It
+// returns the digest of the module.
+func ValidateWasmModule(ctx context.Context, path string) (string, error) {
+	module, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return validateWasmModule(ctx, module)
+}
+
+func validateWasmModule(ctx context.Context, module []byte) (string, error) {
+	runtime := wazero.NewRuntime(ctx)
+	defer runtime.Close(ctx)
+
+	compiled, err := runtime.CompileModule(ctx, module)
+	if err != nil {
+		return "", fmt.Errorf("invalid WebAssembly module: %w", err)
+	}
+	exports := compiled.ExportedFunctions()
+	for _, name := range []string{wasmAllocExport, wasmRunExport} {
+		if _, ok := exports[name]; !ok {
+			return "", fmt.Errorf("the module does not export %s", name)
+		}
+	}
+	if len(compiled.ExportedMemories()) == 0 {
+		return "", errors.New("the module does not export its memory")
+	}
+	for _, f := range compiled.ImportedFunctions() {
+		moduleName, name, _ := f.Import()
+		if moduleName == wasmHostModule && name != "list" {
+			return "", fmt.Errorf("the module imports %s.%s, which the host does not provide", moduleName, name)
+		}
+	}
+	return WasmDigest(module), nil
+}
+
+// Run instantiates the module and runs its analysis. Objects requested by the
+// module are resolved through mapper and listed through client, restricted
+// to namespace and labelSelector.
+func (c *WasmClient) Run(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, namespace, labelSelector string) ([]common.Result, error) {
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(wasmMemoryLimitPages).
+		WithCloseOnContextDone(true))
+	defer runtime.Close(ctx)
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, err
+	}
+	host := &wasmHost{client: client, mapper: mapper, kinds: c.kinds, namespace: namespace, labelSelector: labelSelector}
+	_, err := runtime.NewHostModuleBuilder(wasmHostModule).
+		NewFunctionBuilder().WithFunc(host.list).Export("list").
+		Instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reactors built for WASI export _initialize instead of _start.
+	mod, err := runtime.InstantiateWithConfig(ctx, c.module, wazero.NewModuleConfig().
+		WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, fmt.Errorf("instantiating %s: %w", c.name, err)
+	}
+	run := mod.ExportedFunction(wasmRunExport)
+	if run == nil || mod.Memory() == nil {
+		return nil, fmt.Errorf("%s does not export %s and its memory", c.name, wasmRunExport)
+	}
+
+	request, err := json.Marshal(WasmRunRequest{Namespace: namespace, LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	ptr, err := writeWasm(ctx, mod, request)
+	if err != nil {
+		return nil, err
+	}
+	out, err := run.Call(ctx, uint64(ptr), uint64(len(request)))
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", c.name, err)
+	}
+	data, ok := readWasm(mod, out[0])
+	if !ok {
+		return nil, fmt.Errorf("%s returned a response outside of its memory", c.name)
+	}
+
+	var response WasmRunResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("reading the response of %s: %w", c.name, err)
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Results, nil
+}
+
+type wasmHost struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+	// kinds are the kinds the module may list; every kind but
+	// wasmDeniedKinds when empty.
+	kinds         []schema.GroupKind
+	namespace     string
+	labelSelector string
+}
+
+func (h *wasmHost) allowed(kind schema.GroupKind) bool {
+	if len(h.kinds) > 0 {
+		return slices.Contains(h.kinds, kind)
+	}
+	return !slices.Contains(wasmDeniedKinds, kind)
+}
+
+func (h *wasmHost) list(ctx context.Context, mod api.Module, ptr, size uint32) uint64 {
+	var response WasmListResponse
+	data, ok := mod.Memory().Read(ptr, size)
+	if !ok {
+		response.Error = "the request is outside of the memory of the module"
+	} else if items, err := h.listObjects(ctx, data); err != nil {
+		response.Error = err.Error()
+	} else {
+		response.Items = items
+	}
+
+	out, err := json.Marshal(response)
+	if err != nil {
+		panic(err)
+	}
+	// Errors of the allocator abort the module, which fails the run.
+	ptr, err = writeWasm(ctx, mod, out)
+	if err != nil {
+		panic(err)
+	}
+	return uint64(ptr)<<32 | uint64(len(out))
+}
+
+func (h *wasmHost) listObjects(ctx context.Context, data []byte) ([]map[string]interface{}, error) {
+	var request WasmListRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("invalid list request: %w", err)
+	}
+	if h.client == nil || h.mapper == nil {
+		return nil, errors.New("no Kubernetes client available")
+	}
+	gv, err := schema.ParseGroupVersion(request.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	gvk := gv.WithKind(request.Kind)
+	if request.Kind == "" {
+		if gvk, err = h.mapper.KindFor(gv.WithResource(request.Resource)); err != nil {
+			return nil, err
+		}
+	}
+	mapping, err := h.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if kind := mapping.GroupVersionKind.GroupKind(); !h.allowed(kind) {
+		return nil, fmt.Errorf("the module is not allowed to list %s", kind)
+	}
+
+	var resource dynamic.ResourceInterface = h.client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = h.client.Resource(mapping.Resource).Namespace(h.namespace)
+	}
+	selectors := []string{}
+	for _, selector := range []string{request.LabelSelector, h.labelSelector} {
+		if selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+	list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: strings.Join(selectors, ",")})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]map[string]interface{}, 0, len(list.Items))
+	for _, item := range list.Items {
+		items = append(items, item.Object)
+	}
+	return items, nil
+}
+
+// writeWasm copies data into a buffer allocated by the module.
+func writeWasm(ctx context.Context, mod api.Module, data []byte) (uint32, error) {
+	alloc := mod.ExportedFunction(wasmAllocExport)
+	if alloc == nil {
+		return 0, fmt.Errorf("the module does not export %s", wasmAllocExport)
+	}
+	out, err := alloc.Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	ptr := uint32(out[0])
+	if !mod.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("%s returned a buffer outside of the memory of the module", wasmAllocExport)
+	}
+	return ptr, nil
+}
+
+func readWasm(mod api.Module, packed uint64) ([]byte, bool) {
+	return mod.Memory().Read(uint32(packed>>32), uint32(packed))
+}
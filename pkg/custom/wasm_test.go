@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const (
+	testListRequest    = `{"apiVersion":"v1","kind":"Pod","labelSelector":"app=web"}`
+	testRequestOffset  = 16
+	testResponseOffset = 512
+	testHeapStart      = 8192
+)
+
+// testWasmModule assembles an analyzer that lists pods through the host,
+// traps if the host returns nothing, and returns response.
+func testWasmModule(t *testing.T, response string, exportAlloc bool) string {
+	uleb := func(v uint64) []byte {
+		var out []byte
+		for {
+			b := byte(v & 0x7f)
+			v >>= 7
+			if v != 0 {
+				out = append(out, b|0x80)
+				continue
+			}
+			return append(out, b)
+		}
+	}
+	sleb := func(v int64) []byte {
+		var out []byte
+		for {
+			b := byte(v & 0x7f)
+			v >>= 7
+			if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+				return append(out, b)
+			}
+			out = append(out, b|0x80)
+		}
+	}
+	name := func(s string) []byte { return append(uleb(uint64(len(s))), s...) }
+	vec := func(items ...[]byte) []byte {
+		out := uleb(uint64(len(items)))
+		for _, item := range items {
+			out = append(out, item...)
+		}
+		return out
+	}
+	section := func(id byte, content []byte) []byte {
+		return append(append([]byte{id}, uleb(uint64(len(content)))...), content...)
+	}
+	concat := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, part := range parts {
+			out = append(out, part...)
+		}
+		return out
+	}
+	body := func(code []byte) []byte {
+		code = append([]byte{0x00}, code...) // no locals
+		return append(uleb(uint64(len(code))), code...)
+	}
+	i32 := func(v int64) []byte { return append([]byte{0x41}, sleb(v)...) }
+
+	exports := [][]byte{
+		concat(name("memory"), []byte{0x02, 0x00}),
+		concat(name("k8sgpt_analyze"), []byte{0x00, 0x02}),
+	}
+	if exportAlloc {
+		exports = append(exports, concat(name("k8sgpt_alloc"), []byte{0x00, 0x01}))
+	}
+	packed := int64(testResponseOffset)<<32 | int64(len(response))
+
+	module := concat(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		// (i32, i32) -> i64 and (i32) -> i32
+		section(1, vec([]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e}, []byte{0x60, 0x01, 0x7f, 0x01, 0x7f})),
+		section(2, vec(concat(name("k8sgpt"), name("list"), []byte{0x00, 0x00}))),
+		section(3, vec([]byte{0x01}, []byte{0x00})),
+		section(5, vec([]byte{0x00, 0x02})),
+		section(6, vec(concat([]byte{0x7f, 0x01}, i32(testHeapStart), []byte{0x0b}))),
+		section(7, vec(exports...)),
+		section(10, vec(
+			// alloc: a bump allocator
+			body([]byte{0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b}),
+			// analyze: call list, trap on 0, return the response
+			body(concat(
+				i32(testRequestOffset), i32(int64(len(testListRequest))),
+				[]byte{0x10, 0x00, 0x50, 0x04, 0x40, 0x00, 0x0b},
+				[]byte{0x42}, sleb(packed), []byte{0x0b},
+			)),
+		)),
+		section(11, vec(
+			concat([]byte{0x00}, i32(testRequestOffset), []byte{0x0b}, name(testListRequest)),
+			concat([]byte{0x00}, i32(testResponseOffset), []byte{0x0b}, name(response)),
+		)),
+	)
+
+	path := filepath.Join(t.TempDir(), "analyzer.wasm")
+	require.NoError(t, os.WriteFile(path, module, 0o600))
+	return path
+}
+
+func newTestDynamicClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}: "PodList",
+	}, []runtime.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web", "tier": "db"}}},
+	}...)
+}
+
+func newTestRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestWasmClientRun(t *testing.T) {
+	path := testWasmModule(t, `{"results":[{"kind":"Pod","name":"default/web","error":[{"Text":"web is not sandboxed","Severity":"warning"}]}]}`, true)
+	digest, err := ValidateWasmModule(context.Background(), path)
+	require.NoError(t, err)
+
+	client, err := NewWasmClient("sandbox", WasmModule{Path: path, Digest: digest})
+	require.NoError(t, err)
+	dynamicClient := newTestDynamicClient()
+	results, err := client.Run(context.Background(), dynamicClient, newTestRESTMapper(), "default", "tier=db")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "default/web", results[0].Name)
+	require.Equal(t, "web is not sandboxed", results[0].Error[0].Text)
+
+	// The module listed the pods of the analysis.
+	actions := dynamicClient.Actions()
+	require.Len(t, actions, 1)
+	list := actions[0].(clienttesting.ListAction)
+	require.Equal(t, "default", list.GetNamespace())
+	require.Equal(t, "pods", list.GetResource().Resource)
+	require.Equal(t, "app=web,tier=db", list.GetListRestrictions().Labels.String())
+}
+
+func TestWasmHostList(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}:    "PodList",
+		{Version: "v1", Resource: "secrets"}: "SecretList",
+		{Version: "v1", Resource: "nodes"}:   "NodeList",
+	})
+	host := &wasmHost{client: dynamicClient, mapper: newTestRESTMapper(), namespace: "default"}
+	list := func(request string) error {
+		_, err := host.listObjects(context.Background(), []byte(request))
+		return err
+	}
+
+	// The scope comes from the mapper, not from the module.
+	require.NoError(t, list(`{"apiVersion":"v1","kind":"Node"}`))
+	require.NoError(t, list(`{"apiVersion":"v1","resource":"pods"}`))
+	actions := dynamicClient.Actions()
+	require.Equal(t, "", actions[0].GetNamespace())
+	require.Equal(t, "default", actions[1].GetNamespace())
+	require.ErrorContains(t, list(`{"apiVersion":"v1","kind":"ConfigMap"}`), "no matches for kind")
+
+	// Secrets are denied unless the module was allowed them.
+	require.EqualError(t, list(`{"apiVersion":"v1","kind":"Secret"}`), "the module is not allowed to list Secret")
+	host.kinds, _ = ParseWasmKinds([]string{"Secret"})
+	require.NoError(t, list(`{"apiVersion":"v1","kind":"Secret"}`))
+	require.EqualError(t, list(`{"apiVersion":"v1","kind":"Pod"}`), "the module is not allowed to list Pod")
+
+	kinds, err := ParseWasmKinds([]string{"Pod", "Deployment.apps"})
+	require.NoError(t, err)
+	require.Equal(t, []schema.GroupKind{{Kind: "Pod"}, {Group: "apps", Kind: "Deployment"}}, kinds)
+	_, err = ParseWasmKinds([]string{".apps"})
+	require.Error(t, err)
+}
+
+func TestWasmClientErrors(t *testing.T) {
+	path := testWasmModule(t, `{"error":"missing permissions"}`, true)
+	client, err := NewWasmClient("sandbox", WasmModule{Path: path})
+	require.NoError(t, err)
+	_, err = client.Run(context.Background(), newTestDynamicClient(), newTestRESTMapper(), "default", "")
+	require.EqualError(t, err, "missing permissions")
+
+	_, err = NewWasmClient("sandbox", WasmModule{Path: path, Digest: WasmDigest([]byte("other"))})
+	require.ErrorContains(t, err, "does not match the digest")
+
+	_, err = ValidateWasmModule(context.Background(), testWasmModule(t, "{}", false))
+	require.ErrorContains(t, err, "does not export k8sgpt_alloc")
+
+	invalid := filepath.Join(t.TempDir(), "invalid.wasm")
+	require.NoError(t, os.WriteFile(invalid, []byte("not wasm"), 0o600))
+	_, err = ValidateWasmModule(context.Background(), invalid)
+	require.ErrorContains(t, err, "invalid WebAssembly module")
+}
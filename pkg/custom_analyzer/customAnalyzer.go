@@ -1,19 +1,54 @@
 package custom_analyzer
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"reflect"
+	"path/filepath"
 	"regexp"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/custom"
 )
 
 type CustomAnalyzerConfiguration struct {
 	Name       string     `mapstructure:"name"`
 	Connection Connection `mapstructure:"connection"`
+	Wasm       Wasm       `mapstructure:"wasm"`
+	Timeout    string     `mapstructure:"timeout"`
+}
+
+// Wasm is a WebAssembly analyzer run in-process instead of over a connection.
+type Wasm struct {
+	Path   string   `mapstructure:"path"`
+	Digest string   `mapstructure:"digest"`
+	Kinds  []string `mapstructure:"kinds"`
 }
 
 type Connection struct {
-	Url  string `mapstructure:"url"`
-	Port int    `mapstructure:"port"`
+	Url                string `mapstructure:"url"`
+	Port               int    `mapstructure:"port"`
+	TLS                bool   `mapstructure:"tls"`
+	CAFile             string `mapstructure:"cafile"`
+	CertFile           string `mapstructure:"certfile"`
+	KeyFile            string `mapstructure:"keyfile"`
+	ServerName         string `mapstructure:"servername"`
+	InsecureSkipVerify bool   `mapstructure:"insecureskipverify"`
+	TokenEnv           string `mapstructure:"tokenenv"`
+	TokenFile          string `mapstructure:"tokenfile"`
+}
+
+// Validate checks the security settings of c.
+func (c Connection) Validate() error {
+	if !c.TLS && (c.CAFile != "" || c.CertFile != "" || c.ServerName != "" || c.InsecureSkipVerify) {
+		return errors.New("the TLS settings require --tls")
+	}
+	if !c.TLS && (c.TokenEnv != "" || c.TokenFile != "") {
+		return errors.New("bearer authentication requires --tls")
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("mutual TLS requires both a certificate and a key")
+	}
+	return nil
 }
 
 type CustomAnalyzer struct{}
@@ -23,6 +58,43 @@ func NewCustomAnalyzer() *CustomAnalyzer {
 }
 
 func (*CustomAnalyzer) Check(actualConfig []CustomAnalyzerConfiguration, name, url string, port int) error {
+	if err := checkName(actualConfig, name); err != nil {
+		return err
+	}
+
+	for _, analyzer := range actualConfig {
+		if analyzer.Wasm.Path != "" {
+			continue
+		}
+		if analyzer.Connection.Url == url && analyzer.Connection.Port == port {
+			return fmt.Errorf("custom analyzer with the same connection configuration (URL: '%s', Port: %d) already exists. Please use a different URL or port", url, port)
+		}
+	}
+
+	return nil
+}
+
+// CheckWasm validates the module at path and returns its configuration,
+// allowing it to list kinds.
+func (*CustomAnalyzer) CheckWasm(actualConfig []CustomAnalyzerConfiguration, name, path string, kinds []string) (Wasm, error) {
+	if err := checkName(actualConfig, name); err != nil {
+		return Wasm{}, err
+	}
+	if _, err := custom.ParseWasmKinds(kinds); err != nil {
+		return Wasm{}, err
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return Wasm{}, err
+	}
+	digest, err := custom.ValidateWasmModule(context.Background(), path)
+	if err != nil {
+		return Wasm{}, err
+	}
+	return Wasm{Path: path, Digest: digest, Kinds: kinds}, nil
+}
+
+func checkName(actualConfig []CustomAnalyzerConfiguration, name string) error {
 	validNameRegex := `^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
 	validName := regexp.MustCompile(validNameRegex)
 	if !validName.MatchString(name) {
@@ -33,14 +105,6 @@ func (*CustomAnalyzer) Check(actualConfig []CustomAnalyzerConfiguration, name, u
 		if analyzer.Name == name {
 			return fmt.Errorf("custom analyzer with the name '%s' already exists. Please use a different name", name)
 		}
-
-		if reflect.DeepEqual(analyzer.Connection, Connection{
-			Url:  url,
-			Port: port,
-		}) {
-			return fmt.Errorf("custom analyzer with the same connection configuration (URL: '%s', Port: %d) already exists. Please use a different URL or port", url, port)
-		}
 	}
-
 	return nil
 }
@@ -67,6 +67,7 @@ func (e *EKSAnalyzer) Analyze(analysis common.Analyzer) ([]common.Result, error)
 					Text:          issue.String(),
 					KubernetesDoc: "",
 					Sensitive:     nil,
+					Severity:      common.SeverityWarning,
 				})
 				cr = append(cr, common.Result{
 					Kind:  "EKS",
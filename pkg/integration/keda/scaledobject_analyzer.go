@@ -7,16 +7,14 @@ import (
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	kedaSchema "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
-	"github.com/kedacore/keda/v2/pkg/generated/clientset/versioned/typed/keda/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ScaledObjectAnalyzer struct{}
 
 func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
-	kClient, _ := v1alpha1.NewForConfig(a.Client.GetConfig())
 	kind := "ScaledObject"
 
 	apiDoc := kubernetes.K8sApiReference{
@@ -25,11 +23,16 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 		OpenapiSchema: a.OpenapiSchema,
 	}
 
-	list, err := kClient.ScaledObjects(a.Namespace).List(a.Context, metav1.ListOptions{})
-	if err != nil {
+	list := &kedaSchema.ScaledObjectList{}
+	client := a.Client.CtrlClient
+	if err := kedaSchema.AddToScheme(client.Scheme()); err != nil {
+		return nil, err
+	}
+	if err := client.List(a.Context, list, &ctrl.ListOptions{Namespace: a.Namespace}); err != nil {
 		return nil, err
 	}
 
+	store := a.GetStore()
 	var preAnalysis = map[string]common.PreAnalysis{}
 
 	for _, so := range list.Items {
@@ -44,22 +47,22 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 
 		switch scaleTargetRef.Kind {
 		case "Deployment":
-			deployment, err := a.Client.GetClient().AppsV1().Deployments(so.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			deployment, err := store.Deployment(so.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = DeploymentInfo{deployment}
 			}
 		case "ReplicationController":
-			rc, err := a.Client.GetClient().CoreV1().ReplicationControllers(so.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			rc, err := store.ReplicationController(so.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = ReplicationControllerInfo{rc}
 			}
 		case "ReplicaSet":
-			rs, err := a.Client.GetClient().AppsV1().ReplicaSets(so.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			rs, err := store.ReplicaSet(so.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = ReplicaSetInfo{rs}
 			}
 		case "StatefulSet":
-			ss, err := a.Client.GetClient().AppsV1().StatefulSets(so.Namespace).Get(a.Context, scaleTargetRef.Name, metav1.GetOptions{})
+			ss, err := store.StatefulSet(so.Namespace, scaleTargetRef.Name)
 			if err == nil {
 				podInfo = StatefulSetInfo{ss}
 			}
@@ -67,6 +70,7 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 			failures = append(failures, common.Failure{
 				Text:      fmt.Sprintf("ScaledObject uses %s as ScaleTargetRef which is not an option.", scaleTargetRef.Kind),
 				Sensitive: []common.Sensitive{},
+				Severity:  common.SeverityCritical,
 			})
 		}
 
@@ -82,6 +86,7 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 						Masked:   util.MaskString(scaleTargetRef.Name),
 					},
 				},
+				Severity: common.SeverityCritical,
 			})
 		} else {
 			containers := len(podInfo.GetPodSpec().Containers)
@@ -108,10 +113,11 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 							Masked:   util.MaskString(scaleTargetRef.Name),
 						},
 					},
+					Severity: common.SeverityWarning,
 				})
 			}
 
-			evt, err := util.FetchLatestEvent(a.Context, a.Client, so.Namespace, so.Name)
+			evt, err := store.LatestEvent(so.Namespace, so.Name)
 			if err != nil || evt == nil {
 				continue
 			}
@@ -125,6 +131,7 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 							Masked:   util.MaskString(scaleTargetRef.Name),
 						},
 					},
+					Severity: common.SeverityWarning,
 				})
 			}
 		}
@@ -144,7 +151,7 @@ func (s *ScaledObjectAnalyzer) Analyze(a common.Analyzer) ([]common.Result, erro
 			Error: value.FailureDetails,
 		}
 
-		parent, _ := util.GetParent(a.Client, value.ScaledObject.ObjectMeta)
+		parent, _ := util.GetParentFromStore(store, value.ScaledObject.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
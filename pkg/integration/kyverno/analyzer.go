@@ -57,6 +57,7 @@ func (KyvernoAnalyzer) analyzePolicyReports(a common.Analyzer) ([]common.Result,
 				failures = append(failures, common.Failure{
 					Text:      fmt.Sprintf("policy failure: %s (message: %s)", vuln.Policy, vuln.Message),
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityWarning,
 				})
 			}
 		}
@@ -76,7 +77,7 @@ func (KyvernoAnalyzer) analyzePolicyReports(a common.Analyzer) ([]common.Result,
 			Error: value.FailureDetails,
 		}
 
-		parent, _ := util.GetParent(a.Client, value.KyvernoPolicyReport.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.KyvernoPolicyReport.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -111,6 +112,7 @@ func (t KyvernoAnalyzer) analyzeClusterPolicyReports(a common.Analyzer) ([]commo
 				failures = append(failures, common.Failure{
 					Text:      fmt.Sprintf("critical Vulnerability found ID: %s (learn more at: %s)", vuln.ID, vuln.Source),
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityCritical,
 				})
 			}
 		}
@@ -130,7 +132,7 @@ func (t KyvernoAnalyzer) analyzeClusterPolicyReports(a common.Analyzer) ([]commo
 			Error: value.FailureDetails,
 		}
 
-		parent, _ := util.GetParent(a.Client, value.KyvernoClusterPolicyReport.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.KyvernoClusterPolicyReport.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	k8sgptkubernetes "github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
 	promconfig "github.com/prometheus/prometheus/config"
 	yaml "gopkg.in/yaml.v2"
@@ -48,7 +49,7 @@ func (c *ConfigAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	namespace := a.Namespace
 	kind := ConfigValidate
 
-	podConfigs, err := findPrometheusPodConfigs(ctx, client, namespace)
+	podConfigs, err := findPrometheusPodConfigs(ctx, client, a.GetStore(), namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -64,20 +65,23 @@ func (c *ConfigAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 		config, err := unmarshalPromConfigBytes(pc.b)
 		if err != nil {
 			failures = append(failures, common.Failure{
-				Text: fmt.Sprintf("error validating Prometheus YAML configuration: %s", err),
+				Text:     fmt.Sprintf("error validating Prometheus YAML configuration: %s", err),
+				Severity: common.SeverityCritical,
 			})
 		}
 		_, err = yaml.Marshal(config)
 		if err != nil {
 			failures = append(failures, common.Failure{
-				Text: fmt.Sprintf("error validating Prometheus struct configuration: %s", err),
+				Text:     fmt.Sprintf("error validating Prometheus struct configuration: %s", err),
+				Severity: common.SeverityCritical,
 			})
 		}
 
 		// Check for empty scrape config.
 		if len(config.ScrapeConfigs) == 0 {
 			failures = append(failures, common.Failure{
-				Text: "no scrape configurations. Prometheus will not scrape any metrics.",
+				Text:     "no scrape configurations. Prometheus will not scrape any metrics.",
+				Severity: common.SeverityWarning,
 			})
 		}
 
@@ -95,7 +99,7 @@ func (c *ConfigAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Name:  key,
 			Error: value.FailureDetails,
 		}
-		parent, _ := util.GetParent(a.Client, value.Pod.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.Pod.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -113,9 +117,9 @@ func configKey(namespace string, volume *corev1.Volume) (string, error) {
 	}
 }
 
-func findPrometheusPodConfigs(ctx context.Context, client kubernetes.Interface, namespace string) ([]podConfig, error) {
+func findPrometheusPodConfigs(ctx context.Context, client kubernetes.Interface, store *k8sgptkubernetes.Store, namespace string) ([]podConfig, error) {
 	var configs []podConfig
-	pods, err := findPrometheusPods(ctx, client, namespace)
+	pods, err := findPrometheusPods(store, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -153,26 +157,26 @@ func findPrometheusPodConfigs(ctx context.Context, client kubernetes.Interface,
 	return configs, nil
 }
 
-func findPrometheusPods(ctx context.Context, client kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+func findPrometheusPods(store *k8sgptkubernetes.Store, namespace string) ([]corev1.Pod, error) {
 	var proms []corev1.Pod
 	for k, v := range prometheusPodLabels {
-		pods, err := util.GetPodListByLabels(client, namespace, map[string]string{
-			k: v,
-		})
+		pods, err := store.Pods(namespace, v1.FormatLabelSelector(&v1.LabelSelector{
+			MatchLabels: map[string]string{k: v},
+		}))
 		if err != nil {
 			return nil, err
 		}
-		proms = append(proms, pods.Items...)
+		proms = append(proms, pods...)
 	}
 
 	// If we still haven't found any Prometheus pods, make a last-ditch effort to
 	// scrape the namespace for "prometheus" containers.
 	if len(proms) == 0 {
-		pods, err := client.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{})
+		pods, err := store.Pods(namespace, "")
 		if err != nil {
 			return nil, err
 		}
-		for _, pod := range pods.Items {
+		for _, pod := range pods {
 			for _, c := range pod.Spec.Containers {
 				if c.Name == prometheusContainerName {
 					proms = append(proms, pod)
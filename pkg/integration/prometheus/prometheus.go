@@ -51,7 +51,7 @@ func (p *Prometheus) Deploy(namespace string) error {
 	// is found in both.
 	// We accept this as a trade-off for the time-being to avoid having the tool
 	// manage Prometheus on the behalf of users.
-	podConfigs, err := findPrometheusPodConfigs(ctx, client.GetClient(), namespace)
+	podConfigs, err := findPrometheusPodConfigs(ctx, client.GetClient(), kubernetes.NewStore(ctx, client, namespace), namespace)
 	if err != nil {
 		color.Red("Error discovering Prometheus workloads: %v", err)
 		os.Exit(1)
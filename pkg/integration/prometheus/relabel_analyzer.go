@@ -18,7 +18,7 @@ func (r *RelabelAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	namespace := a.Namespace
 	kind := ConfigRelabel
 
-	podConfigs, err := findPrometheusPodConfigs(ctx, client, namespace)
+	podConfigs, err := findPrometheusPodConfigs(ctx, client, a.GetStore(), namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +57,8 @@ func (r *RelabelAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 				continue
 			}
 			failures = append(failures, common.Failure{
-				Text: fmt.Sprintf("job_name:\n%s\nrelabel_configs:\n%s\nkubernetes_sd_configs:\n%s\n", sc.JobName, string(brc), string(bsd)),
+				Text:     fmt.Sprintf("job_name:\n%s\nrelabel_configs:\n%s\nkubernetes_sd_configs:\n%s\n", sc.JobName, string(brc), string(bsd)),
+				Severity: common.SeverityInfo,
 			})
 			i++
 		}
@@ -76,7 +77,7 @@ func (r *RelabelAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 			Name:  key,
 			Error: value.FailureDetails,
 		}
-		parent, _ := util.GetParent(a.Client, value.Pod.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.Pod.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -58,6 +58,7 @@ func (TrivyAnalyzer) analyzeVulnerabilityReports(a common.Analyzer) ([]common.Re
 				distinctFailures[text] = common.Failure{
 					Text:      text,
 					Sensitive: []common.Sensitive{},
+					Severity:  common.SeverityCritical,
 				}
 			}
 		}
@@ -80,7 +81,7 @@ func (TrivyAnalyzer) analyzeVulnerabilityReports(a common.Analyzer) ([]common.Re
 			Error: value.FailureDetails,
 		}
 
-		parent, _ := util.GetParent(a.Client, value.TrivyVulnerabilityReport.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.TrivyVulnerabilityReport.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -123,6 +124,7 @@ func (t TrivyAnalyzer) analyzeConfigAuditReports(a common.Analyzer) ([]common.Re
 							Masked:   util.MaskString(report.Labels["trivy-operator.resource.namespace"]),
 						},
 					},
+					Severity: configCheckSeverity(check.Severity),
 				})
 			}
 		}
@@ -143,7 +145,7 @@ func (t TrivyAnalyzer) analyzeConfigAuditReports(a common.Analyzer) ([]common.Re
 			Error: value.FailureDetails,
 		}
 
-		parent, _ := util.GetParent(a.Client, value.TrivyConfigAuditReport.ObjectMeta)
+		parent, _ := util.GetParentFromStore(a.GetStore(), value.TrivyConfigAuditReport.ObjectMeta)
 		currentAnalysis.ParentObject = parent
 		a.Results = append(a.Results, currentAnalysis)
 	}
@@ -173,3 +175,11 @@ func (t TrivyAnalyzer) Analyze(a common.Analyzer) ([]common.Result, error) {
 	}
 	return make([]common.Result, 0), nil
 }
+
+// configCheckSeverity maps a trivy check severity to a failure severity.
+func configCheckSeverity(severity v1alpha1.Severity) common.Severity {
+	if severity == v1alpha1.SeverityCritical {
+		return common.SeverityCritical
+	}
+	return common.SeverityWarning
+}
@@ -14,9 +14,13 @@ limitations under the License.
 package kubernetes
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -33,6 +37,19 @@ func (c *Client) GetCtrlClient() ctrl.Client {
 	return c.CtrlClient
 }
 
+func (c *Client) GetDynamicClient() dynamic.Interface {
+	return c.DynamicClient
+}
+
+// GetRESTMapper returns a mapper of kinds to resources, discovered from the
+// API server on first use. It is nil without a clientset.
+func (c *Client) GetRESTMapper() meta.RESTMapper {
+	if c.Client == nil {
+		return nil
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(c.Client.Discovery()))
+}
+
 func NewClient(kubecontext string, kubeconfig string) (*Client, error) {
 	var config *rest.Config
 	config, err := rest.InClusterConfig()
@@ -64,6 +81,11 @@ func NewClient(kubecontext string, kubeconfig string) (*Client, error) {
 		return nil, err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	serverVersion, err := clientSet.ServerVersion()
 	if err != nil {
 		return nil, err
@@ -72,6 +94,7 @@ func NewClient(kubecontext string, kubeconfig string) (*Client, error) {
 	return &Client{
 		Client:        clientSet,
 		CtrlClient:    ctrlClient,
+		DynamicClient: dynamicClient,
 		Config:        config,
 		ServerVersion: serverVersion,
 	}, nil
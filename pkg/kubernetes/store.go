@@ -0,0 +1,716 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// StorePageSize is the number of objects requested per page when a Store
+// lists a resource.
+const StorePageSize = 500
+
+// Store serves the objects of one analysis run to all of its analyzers. Each
+// resource is listed once per namespace, a page at a time, on first use;
+// lookups by name and by selector are answered from that list. The returned
+// objects are shared and must not be modified. A Store is safe for concurrent
+// use.
+type Store struct {
+	ctx       context.Context
+	client    *Client
+	namespace string
+	informers informers.SharedInformerFactory
+
+	pods                            objectCache[v1.Pod]
+	services                        objectCache[v1.Service]
+	endpoints                       objectCache[v1.Endpoints]
+	events                          objectCache[v1.Event]
+	persistentVolumeClaims          objectCache[v1.PersistentVolumeClaim]
+	nodes                           objectCache[v1.Node]
+	replicationControllers          objectCache[v1.ReplicationController]
+	replicaSets                     objectCache[appsv1.ReplicaSet]
+	deployments                     objectCache[appsv1.Deployment]
+	statefulSets                    objectCache[appsv1.StatefulSet]
+	daemonSets                      objectCache[appsv1.DaemonSet]
+	jobs                            objectCache[batchv1.Job]
+	cronJobs                        objectCache[batchv1.CronJob]
+	horizontalPodAutoscalers        objectCache[autoscalingv2.HorizontalPodAutoscaler]
+	ingresses                       objectCache[networkingv1.Ingress]
+	ingressClasses                  objectCache[networkingv1.IngressClass]
+	networkPolicies                 objectCache[networkingv1.NetworkPolicy]
+	podDisruptionBudgets            objectCache[policyv1.PodDisruptionBudget]
+	storageClasses                  objectCache[storagev1.StorageClass]
+	mutatingWebhookConfigurations   objectCache[admissionregistrationv1.MutatingWebhookConfiguration]
+	validatingWebhookConfigurations objectCache[admissionregistrationv1.ValidatingWebhookConfiguration]
+
+	// objects holds the resources read through the dynamic client.
+	objectsMutex sync.Mutex
+	objects      map[schema.GroupVersionResource]*objectCache[unstructured.Unstructured]
+}
+
+// NewStore returns an empty store for the objects of namespace, all
+// namespaces if empty. Objects of other namespaces are listed separately
+// when asked for.
+func NewStore(ctx context.Context, client *Client, namespace string) *Store {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Store{
+		ctx:                             ctx,
+		client:                          client,
+		namespace:                       namespace,
+		pods:                            objectCache[v1.Pod]{resource: v1.SchemeGroupVersion.WithResource("pods")},
+		services:                        objectCache[v1.Service]{resource: v1.SchemeGroupVersion.WithResource("services")},
+		endpoints:                       objectCache[v1.Endpoints]{resource: v1.SchemeGroupVersion.WithResource("endpoints")},
+		events:                          objectCache[v1.Event]{resource: v1.SchemeGroupVersion.WithResource("events"), key: involvedObjectKey},
+		persistentVolumeClaims:          objectCache[v1.PersistentVolumeClaim]{resource: v1.SchemeGroupVersion.WithResource("persistentvolumeclaims")},
+		nodes:                           objectCache[v1.Node]{resource: v1.SchemeGroupVersion.WithResource("nodes"), clusterScoped: true},
+		replicationControllers:          objectCache[v1.ReplicationController]{resource: v1.SchemeGroupVersion.WithResource("replicationcontrollers")},
+		replicaSets:                     objectCache[appsv1.ReplicaSet]{resource: appsv1.SchemeGroupVersion.WithResource("replicasets")},
+		deployments:                     objectCache[appsv1.Deployment]{resource: appsv1.SchemeGroupVersion.WithResource("deployments")},
+		statefulSets:                    objectCache[appsv1.StatefulSet]{resource: appsv1.SchemeGroupVersion.WithResource("statefulsets")},
+		daemonSets:                      objectCache[appsv1.DaemonSet]{resource: appsv1.SchemeGroupVersion.WithResource("daemonsets")},
+		jobs:                            objectCache[batchv1.Job]{resource: batchv1.SchemeGroupVersion.WithResource("jobs")},
+		cronJobs:                        objectCache[batchv1.CronJob]{resource: batchv1.SchemeGroupVersion.WithResource("cronjobs")},
+		horizontalPodAutoscalers:        objectCache[autoscalingv2.HorizontalPodAutoscaler]{resource: autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers")},
+		ingresses:                       objectCache[networkingv1.Ingress]{resource: networkingv1.SchemeGroupVersion.WithResource("ingresses")},
+		ingressClasses:                  objectCache[networkingv1.IngressClass]{resource: networkingv1.SchemeGroupVersion.WithResource("ingressclasses"), clusterScoped: true},
+		networkPolicies:                 objectCache[networkingv1.NetworkPolicy]{resource: networkingv1.SchemeGroupVersion.WithResource("networkpolicies")},
+		podDisruptionBudgets:            objectCache[policyv1.PodDisruptionBudget]{resource: policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets")},
+		storageClasses:                  objectCache[storagev1.StorageClass]{resource: storagev1.SchemeGroupVersion.WithResource("storageclasses"), clusterScoped: true},
+		mutatingWebhookConfigurations:   objectCache[admissionregistrationv1.MutatingWebhookConfiguration]{resource: admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"), clusterScoped: true},
+		validatingWebhookConfigurations: objectCache[admissionregistrationv1.ValidatingWebhookConfiguration]{resource: admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"), clusterScoped: true},
+	}
+}
+
+// NewInformerStore returns a store that reads the objects of namespace from
+// the shared informers of factory, which must watch namespace, instead of
+// listing them. Informers are started on first use; the objects of other
+// namespaces are listed.
+func NewInformerStore(ctx context.Context, client *Client, namespace string, factory informers.SharedInformerFactory) *Store {
+	s := NewStore(ctx, client, namespace)
+	s.informers = factory
+	return s
+}
+
+// listPage returns one page of objects and the continue token of the next.
+type listPage[T any] func(ctx context.Context, namespace string, options metav1.ListOptions) ([]T, string, error)
+
+// objectList is a resource listed in one namespace, or in all of them.
+type objectList[T any] struct {
+	once  sync.Once
+	items []T
+	index map[string][]int // items by objectCache key
+	err   error
+}
+
+// objectCache holds the lists of a resource by namespace.
+type objectCache[T any] struct {
+	resource      schema.GroupVersionResource
+	clusterScoped bool
+	// listOnly caches are always listed, they have no typed informer.
+	listOnly bool
+	// key indexes the items, by namespace and name if nil.
+	key func(*T) string
+
+	mutex sync.Mutex
+	lists map[string]*objectList[T]
+}
+
+func objectMetaKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// involvedObjectKey indexes events by their namespace and the name of the
+// object they are about.
+func involvedObjectKey(event *v1.Event) string {
+	return objectMetaKey(event.Namespace, event.InvolvedObject.Name)
+}
+
+// scope returns the namespace to list for the objects of namespace: the
+// namespace of the store when it includes namespace, namespace otherwise.
+func (s *Store) scope(namespace string) string {
+	if s.namespace == "" || namespace == s.namespace {
+		return s.namespace
+	}
+	return namespace
+}
+
+// load returns the list of the objects of namespace, listing them page by
+// page on first use.
+func (c *objectCache[T]) load(s *Store, namespace string, list listPage[T]) (*objectList[T], error) {
+	scope := s.scope(namespace)
+	c.mutex.Lock()
+	if c.lists == nil {
+		c.lists = map[string]*objectList[T]{}
+	}
+	l, ok := c.lists[scope]
+	if !ok {
+		l = &objectList[T]{}
+		c.lists[scope] = l
+	}
+	c.mutex.Unlock()
+
+	l.once.Do(func() {
+		if s.informers != nil && !c.listOnly && (scope == s.namespace || c.clusterScoped) {
+			l.items, l.err = c.fromInformer(s)
+			if l.err != nil {
+				return
+			}
+			c.index(l)
+			return
+		}
+		options := metav1.ListOptions{Limit: StorePageSize}
+		for {
+			items, next, err := list(s.ctx, scope, options)
+			if err != nil {
+				l.items, l.err = nil, err
+				return
+			}
+			l.items = append(l.items, items...)
+			if next == "" {
+				break
+			}
+			options.Continue = next
+		}
+		c.index(l)
+	})
+	return l, l.err
+}
+
+func (c *objectCache[T]) index(l *objectList[T]) {
+	l.index = make(map[string][]int, len(l.items))
+	for i := range l.items {
+		key := c.keyOf(&l.items[i])
+		l.index[key] = append(l.index[key], i)
+	}
+}
+
+// fromInformer returns the objects in the cache of the informer of the
+// resource, starting the informer and waiting for its cache on first use.
+func (c *objectCache[T]) fromInformer(s *Store) ([]T, error) {
+	generic, err := s.informers.ForResource(c.resource)
+	if err != nil {
+		return nil, err
+	}
+	informer := generic.Informer()
+	s.informers.Start(s.ctx.Done())
+	if !toolscache.WaitForCacheSync(s.ctx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("failed to sync informer cache for %s", c.resource.Resource)
+	}
+	cached := informer.GetIndexer().List()
+	items := make([]T, 0, len(cached))
+	for _, object := range cached {
+		if item, ok := object.(*T); ok {
+			items = append(items, *item)
+		}
+	}
+	return items, nil
+}
+
+func (c *objectCache[T]) keyOf(item *T) string {
+	if c.key != nil {
+		return c.key(item)
+	}
+	meta := any(item).(metav1.Object)
+	return objectMetaKey(meta.GetNamespace(), meta.GetName())
+}
+
+// list returns the objects of namespace, all namespaces if empty, that match
+// the label selector.
+func (c *objectCache[T]) list(s *Store, namespace, selector string, list listPage[T]) ([]T, error) {
+	l, err := c.load(s, namespace, list)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == s.scope(namespace) && selector == "" {
+		return l.items, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	for i := range l.items {
+		meta := any(&l.items[i]).(metav1.Object)
+		if namespace != "" && meta.GetNamespace() != namespace {
+			continue
+		}
+		if parsed.Matches(labels.Set(meta.GetLabels())) {
+			items = append(items, l.items[i])
+		}
+	}
+	return items, nil
+}
+
+// get returns the object of namespace with name, or a NotFound error.
+func (c *objectCache[T]) get(s *Store, namespace, name string, list listPage[T]) (*T, error) {
+	l, err := c.load(s, namespace, list)
+	if err != nil {
+		return nil, err
+	}
+	if indices := l.index[objectMetaKey(namespace, name)]; len(indices) != 0 {
+		return &l.items[indices[0]], nil
+	}
+	return nil, apierrors.NewNotFound(c.resource.GroupResource(), name)
+}
+
+// Pods returns the pods of namespace that match selector.
+func (s *Store) Pods(namespace, selector string) ([]v1.Pod, error) {
+	return s.pods.list(s, namespace, selector, s.listPods)
+}
+
+// Pod returns the pod of namespace with name.
+func (s *Store) Pod(namespace, name string) (*v1.Pod, error) {
+	return s.pods.get(s, namespace, name, s.listPods)
+}
+
+func (s *Store) listPods(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.Pod, string, error) {
+	list, err := s.client.GetClient().CoreV1().Pods(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Services returns the services of namespace that match selector.
+func (s *Store) Services(namespace, selector string) ([]v1.Service, error) {
+	return s.services.list(s, namespace, selector, s.listServices)
+}
+
+// Service returns the service of namespace with name.
+func (s *Store) Service(namespace, name string) (*v1.Service, error) {
+	return s.services.get(s, namespace, name, s.listServices)
+}
+
+func (s *Store) listServices(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.Service, string, error) {
+	list, err := s.client.GetClient().CoreV1().Services(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Endpoints returns the endpoints of namespace that match selector.
+func (s *Store) Endpoints(namespace, selector string) ([]v1.Endpoints, error) {
+	return s.endpoints.list(s, namespace, selector, s.listEndpoints)
+}
+
+func (s *Store) listEndpoints(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.Endpoints, string, error) {
+	list, err := s.client.GetClient().CoreV1().Endpoints(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Events returns the events of namespace about the object with name.
+func (s *Store) Events(namespace, name string) ([]v1.Event, error) {
+	l, err := s.events.load(s, namespace, s.listEvents)
+	if err != nil {
+		return nil, err
+	}
+	indices := l.index[objectMetaKey(namespace, name)]
+	events := make([]v1.Event, 0, len(indices))
+	for _, i := range indices {
+		events = append(events, l.items[i])
+	}
+	return events, nil
+}
+
+// LatestEvent returns the most recent event of namespace about the object
+// with name, nil if there is none.
+func (s *Store) LatestEvent(namespace, name string) (*v1.Event, error) {
+	l, err := s.events.load(s, namespace, s.listEvents)
+	if err != nil {
+		return nil, err
+	}
+	var latest *v1.Event
+	for _, i := range l.index[objectMetaKey(namespace, name)] {
+		if latest == nil || l.items[i].LastTimestamp.After(latest.LastTimestamp.Time) {
+			latest = &l.items[i]
+		}
+	}
+	return latest, nil
+}
+
+func (s *Store) listEvents(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.Event, string, error) {
+	list, err := s.client.GetClient().CoreV1().Events(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// PersistentVolumeClaims returns the persistent volume claims of namespace
+// that match selector.
+func (s *Store) PersistentVolumeClaims(namespace, selector string) ([]v1.PersistentVolumeClaim, error) {
+	return s.persistentVolumeClaims.list(s, namespace, selector, s.listPersistentVolumeClaims)
+}
+
+func (s *Store) listPersistentVolumeClaims(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.PersistentVolumeClaim, string, error) {
+	list, err := s.client.GetClient().CoreV1().PersistentVolumeClaims(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Nodes returns the nodes that match selector.
+func (s *Store) Nodes(selector string) ([]v1.Node, error) {
+	return s.nodes.list(s, "", selector, s.listNodes)
+}
+
+func (s *Store) listNodes(ctx context.Context, _ string, options metav1.ListOptions) ([]v1.Node, string, error) {
+	list, err := s.client.GetClient().CoreV1().Nodes().List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// ReplicationController returns the replication controller of namespace with
+// name.
+func (s *Store) ReplicationController(namespace, name string) (*v1.ReplicationController, error) {
+	return s.replicationControllers.get(s, namespace, name, s.listReplicationControllers)
+}
+
+func (s *Store) listReplicationControllers(ctx context.Context, namespace string, options metav1.ListOptions) ([]v1.ReplicationController, string, error) {
+	list, err := s.client.GetClient().CoreV1().ReplicationControllers(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// ReplicaSets returns the replica sets of namespace that match selector.
+func (s *Store) ReplicaSets(namespace, selector string) ([]appsv1.ReplicaSet, error) {
+	return s.replicaSets.list(s, namespace, selector, s.listReplicaSets)
+}
+
+// ReplicaSet returns the replica set of namespace with name.
+func (s *Store) ReplicaSet(namespace, name string) (*appsv1.ReplicaSet, error) {
+	return s.replicaSets.get(s, namespace, name, s.listReplicaSets)
+}
+
+func (s *Store) listReplicaSets(ctx context.Context, namespace string, options metav1.ListOptions) ([]appsv1.ReplicaSet, string, error) {
+	list, err := s.client.GetClient().AppsV1().ReplicaSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Deployments returns the deployments of namespace that match selector.
+func (s *Store) Deployments(namespace, selector string) ([]appsv1.Deployment, error) {
+	return s.deployments.list(s, namespace, selector, s.listDeployments)
+}
+
+// Deployment returns the deployment of namespace with name.
+func (s *Store) Deployment(namespace, name string) (*appsv1.Deployment, error) {
+	return s.deployments.get(s, namespace, name, s.listDeployments)
+}
+
+func (s *Store) listDeployments(ctx context.Context, namespace string, options metav1.ListOptions) ([]appsv1.Deployment, string, error) {
+	list, err := s.client.GetClient().AppsV1().Deployments(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// StatefulSets returns the stateful sets of namespace that match selector.
+func (s *Store) StatefulSets(namespace, selector string) ([]appsv1.StatefulSet, error) {
+	return s.statefulSets.list(s, namespace, selector, s.listStatefulSets)
+}
+
+// StatefulSet returns the stateful set of namespace with name.
+func (s *Store) StatefulSet(namespace, name string) (*appsv1.StatefulSet, error) {
+	return s.statefulSets.get(s, namespace, name, s.listStatefulSets)
+}
+
+func (s *Store) listStatefulSets(ctx context.Context, namespace string, options metav1.ListOptions) ([]appsv1.StatefulSet, string, error) {
+	list, err := s.client.GetClient().AppsV1().StatefulSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// DaemonSet returns the daemon set of namespace with name.
+func (s *Store) DaemonSet(namespace, name string) (*appsv1.DaemonSet, error) {
+	return s.daemonSets.get(s, namespace, name, s.listDaemonSets)
+}
+
+func (s *Store) listDaemonSets(ctx context.Context, namespace string, options metav1.ListOptions) ([]appsv1.DaemonSet, string, error) {
+	list, err := s.client.GetClient().AppsV1().DaemonSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Jobs returns the jobs of namespace that match selector.
+func (s *Store) Jobs(namespace, selector string) ([]batchv1.Job, error) {
+	return s.jobs.list(s, namespace, selector, s.listJobs)
+}
+
+func (s *Store) listJobs(ctx context.Context, namespace string, options metav1.ListOptions) ([]batchv1.Job, string, error) {
+	list, err := s.client.GetClient().BatchV1().Jobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// CronJobs returns the cron jobs of namespace that match selector.
+func (s *Store) CronJobs(namespace, selector string) ([]batchv1.CronJob, error) {
+	return s.cronJobs.list(s, namespace, selector, s.listCronJobs)
+}
+
+func (s *Store) listCronJobs(ctx context.Context, namespace string, options metav1.ListOptions) ([]batchv1.CronJob, string, error) {
+	list, err := s.client.GetClient().BatchV1().CronJobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// HorizontalPodAutoscalers returns the horizontal pod autoscalers of
+// namespace that match selector.
+func (s *Store) HorizontalPodAutoscalers(namespace, selector string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	return s.horizontalPodAutoscalers.list(s, namespace, selector, s.listHorizontalPodAutoscalers)
+}
+
+func (s *Store) listHorizontalPodAutoscalers(ctx context.Context, namespace string, options metav1.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, string, error) {
+	list, err := s.client.GetClient().AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Ingresses returns the ingresses of namespace that match selector.
+func (s *Store) Ingresses(namespace, selector string) ([]networkingv1.Ingress, error) {
+	return s.ingresses.list(s, namespace, selector, s.listIngresses)
+}
+
+// Ingress returns the ingress of namespace with name.
+func (s *Store) Ingress(namespace, name string) (*networkingv1.Ingress, error) {
+	return s.ingresses.get(s, namespace, name, s.listIngresses)
+}
+
+func (s *Store) listIngresses(ctx context.Context, namespace string, options metav1.ListOptions) ([]networkingv1.Ingress, string, error) {
+	list, err := s.client.GetClient().NetworkingV1().Ingresses(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// IngressClass returns the ingress class with name.
+func (s *Store) IngressClass(name string) (*networkingv1.IngressClass, error) {
+	return s.ingressClasses.get(s, "", name, s.listIngressClasses)
+}
+
+func (s *Store) listIngressClasses(ctx context.Context, _ string, options metav1.ListOptions) ([]networkingv1.IngressClass, string, error) {
+	list, err := s.client.GetClient().NetworkingV1().IngressClasses().List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// NetworkPolicies returns the network policies of namespace that match
+// selector.
+func (s *Store) NetworkPolicies(namespace, selector string) ([]networkingv1.NetworkPolicy, error) {
+	return s.networkPolicies.list(s, namespace, selector, s.listNetworkPolicies)
+}
+
+func (s *Store) listNetworkPolicies(ctx context.Context, namespace string, options metav1.ListOptions) ([]networkingv1.NetworkPolicy, string, error) {
+	list, err := s.client.GetClient().NetworkingV1().NetworkPolicies(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// PodDisruptionBudgets returns the pod disruption budgets of namespace that
+// match selector.
+func (s *Store) PodDisruptionBudgets(namespace, selector string) ([]policyv1.PodDisruptionBudget, error) {
+	return s.podDisruptionBudgets.list(s, namespace, selector, s.listPodDisruptionBudgets)
+}
+
+func (s *Store) listPodDisruptionBudgets(ctx context.Context, namespace string, options metav1.ListOptions) ([]policyv1.PodDisruptionBudget, string, error) {
+	list, err := s.client.GetClient().PolicyV1().PodDisruptionBudgets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// StorageClass returns the storage class with name.
+func (s *Store) StorageClass(name string) (*storagev1.StorageClass, error) {
+	return s.storageClasses.get(s, "", name, s.listStorageClasses)
+}
+
+func (s *Store) listStorageClasses(ctx context.Context, _ string, options metav1.ListOptions) ([]storagev1.StorageClass, string, error) {
+	list, err := s.client.GetClient().StorageV1().StorageClasses().List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// MutatingWebhookConfigurations returns the mutating webhook configurations
+// that match selector.
+func (s *Store) MutatingWebhookConfigurations(selector string) ([]admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	return s.mutatingWebhookConfigurations.list(s, "", selector, s.listMutatingWebhookConfigurations)
+}
+
+// MutatingWebhookConfiguration returns the mutating webhook configuration
+// with name.
+func (s *Store) MutatingWebhookConfiguration(name string) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	return s.mutatingWebhookConfigurations.get(s, "", name, s.listMutatingWebhookConfigurations)
+}
+
+func (s *Store) listMutatingWebhookConfigurations(ctx context.Context, _ string, options metav1.ListOptions) ([]admissionregistrationv1.MutatingWebhookConfiguration, string, error) {
+	list, err := s.client.GetClient().AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// ValidatingWebhookConfigurations returns the validating webhook
+// configurations that match selector.
+func (s *Store) ValidatingWebhookConfigurations(selector string) ([]admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+	return s.validatingWebhookConfigurations.list(s, "", selector, s.listValidatingWebhookConfigurations)
+}
+
+// ValidatingWebhookConfiguration returns the validating webhook configuration
+// with name.
+func (s *Store) ValidatingWebhookConfiguration(name string) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+	return s.validatingWebhookConfigurations.get(s, "", name, s.listValidatingWebhookConfigurations)
+}
+
+func (s *Store) listValidatingWebhookConfigurations(ctx context.Context, _ string, options metav1.ListOptions) ([]admissionregistrationv1.ValidatingWebhookConfiguration, string, error) {
+	list, err := s.client.GetClient().AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Items, list.Continue, nil
+}
+
+// Objects returns the objects of resource in namespace that match selector,
+// read through the dynamic client.
+func (s *Store) Objects(resource schema.GroupVersionResource, namespace, selector string) ([]unstructured.Unstructured, error) {
+	return s.objectCache(resource).list(s, namespace, selector, s.listObjects(resource))
+}
+
+// Object returns the object of resource in namespace with name, read through
+// the dynamic client. Cluster-scoped objects have no namespace.
+func (s *Store) Object(resource schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return s.objectCache(resource).get(s, namespace, name, s.listObjects(resource))
+}
+
+func (s *Store) objectCache(resource schema.GroupVersionResource) *objectCache[unstructured.Unstructured] {
+	s.objectsMutex.Lock()
+	defer s.objectsMutex.Unlock()
+	if s.objects == nil {
+		s.objects = map[schema.GroupVersionResource]*objectCache[unstructured.Unstructured]{}
+	}
+	cache, ok := s.objects[resource]
+	if !ok {
+		cache = &objectCache[unstructured.Unstructured]{resource: resource, listOnly: true}
+		s.objects[resource] = cache
+	}
+	return cache
+}
+
+func (s *Store) listObjects(resource schema.GroupVersionResource) listPage[unstructured.Unstructured] {
+	return func(ctx context.Context, namespace string, options metav1.ListOptions) ([]unstructured.Unstructured, string, error) {
+		if s.client.GetDynamicClient() == nil {
+			return nil, "", errors.New("no dynamic client")
+		}
+		list, err := s.client.GetDynamicClient().Resource(resource).Namespace(namespace).List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.GetContinue(), nil
+	}
+}
+
+// ObjectMeta returns the metadata of the object of resource in namespace with
+// name. Resources the store lists for the analyzers are read from those lists,
+// others through the dynamic client.
+func (s *Store) ObjectMeta(resource schema.GroupVersionResource, namespace, name string) (metav1.Object, error) {
+	switch resource {
+	case s.pods.resource:
+		return objectMeta(s.Pod(namespace, name))
+	case s.services.resource:
+		return objectMeta(s.Service(namespace, name))
+	case s.replicationControllers.resource:
+		return objectMeta(s.ReplicationController(namespace, name))
+	case s.replicaSets.resource:
+		return objectMeta(s.ReplicaSet(namespace, name))
+	case s.deployments.resource:
+		return objectMeta(s.Deployment(namespace, name))
+	case s.statefulSets.resource:
+		return objectMeta(s.StatefulSet(namespace, name))
+	case s.daemonSets.resource:
+		return objectMeta(s.DaemonSet(namespace, name))
+	case s.persistentVolumeClaims.resource:
+		return objectMeta(s.persistentVolumeClaims.get(s, namespace, name, s.listPersistentVolumeClaims))
+	case s.jobs.resource:
+		return objectMeta(s.jobs.get(s, namespace, name, s.listJobs))
+	case s.horizontalPodAutoscalers.resource:
+		return objectMeta(s.horizontalPodAutoscalers.get(s, namespace, name, s.listHorizontalPodAutoscalers))
+	case s.ingresses.resource:
+		return objectMeta(s.Ingress(namespace, name))
+	case s.storageClasses.resource:
+		return objectMeta(s.StorageClass(name))
+	case s.mutatingWebhookConfigurations.resource:
+		return objectMeta(s.MutatingWebhookConfiguration(name))
+	case s.validatingWebhookConfigurations.resource:
+		return objectMeta(s.ValidatingWebhookConfiguration(name))
+	}
+	return objectMeta(s.Object(resource, namespace, name))
+}
+
+func objectMeta[T any](item *T, err error) (metav1.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	return any(item).(metav1.Object), nil
+}
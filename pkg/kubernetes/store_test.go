@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// pagedPods serves the pods of each namespace in pages of two and records the
+// namespace of every list call.
+func pagedPods(clientset *fake.Clientset, pods []v1.Pod, calls *[]string) {
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := action.(k8stesting.ListActionImpl)
+		*calls = append(*calls, list.GetNamespace())
+
+		var matching []v1.Pod
+		for _, pod := range pods {
+			if list.GetNamespace() == "" || pod.Namespace == list.GetNamespace() {
+				matching = append(matching, pod)
+			}
+		}
+		start := 0
+		if list.ListOptions.Continue != "" {
+			start, _ = strconv.Atoi(list.ListOptions.Continue)
+		}
+		end := min(start+2, len(matching))
+		page := &v1.PodList{Items: matching[start:end]}
+		if end < len(matching) {
+			page.Continue = strconv.Itoa(end)
+		}
+		return true, page, nil
+	})
+}
+
+func TestStorePods(t *testing.T) {
+	var pods []v1.Pod
+	for i := 0; i < 5; i++ {
+		pods = append(pods, v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("web-%d", i),
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web", "index": strconv.Itoa(i)},
+		}})
+	}
+	pods = append(pods, v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "other", Labels: map[string]string{"app": "api"}}})
+
+	clientset := fake.NewSimpleClientset()
+	var calls []string
+	pagedPods(clientset, pods, &calls)
+	store := NewStore(context.Background(), &Client{Client: clientset}, "")
+
+	list, err := store.Pods("", "")
+	require.NoError(t, err)
+	require.Len(t, list, 6)
+	// Three pages of two pods.
+	require.Equal(t, []string{"", "", ""}, calls)
+
+	list, err = store.Pods("default", "index in (1,3)")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	list, err = store.Pods("other", "")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	pod, err := store.Pod("default", "web-4")
+	require.NoError(t, err)
+	require.Equal(t, "web-4", pod.Name)
+	_, err = store.Pod("default", "web-5")
+	require.True(t, apierrors.IsNotFound(err))
+	_, err = store.Pods("", "app in (")
+	require.Error(t, err)
+	require.Len(t, calls, 3)
+
+	// A store of one namespace lists other namespaces when asked for them.
+	calls = nil
+	store = NewStore(context.Background(), &Client{Client: clientset}, "default")
+	list, err = store.Pods("default", "app=web")
+	require.NoError(t, err)
+	require.Len(t, list, 5)
+	_, err = store.Pod("other", "api")
+	require.NoError(t, err)
+	_, err = store.Pod("default", "web-0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"default", "default", "default", "other"}, calls)
+}
+
+func TestStoreEvents(t *testing.T) {
+	event := func(name, namespace, object string, at time.Time) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: object, Namespace: namespace},
+			LastTimestamp:  metav1.NewTime(at),
+			Message:        name,
+		}
+	}
+	now := time.Now()
+	clientset := fake.NewSimpleClientset(
+		event("scheduled", "default", "web", now.Add(-time.Hour)),
+		event("failed", "default", "web", now),
+		event("pulled", "default", "web", now.Add(-time.Minute)),
+		event("other", "default", "api", now),
+		event("elsewhere", "test", "web", now.Add(time.Hour)),
+	)
+	var lists int
+	clientset.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	store := NewStore(context.Background(), &Client{Client: clientset}, "")
+
+	latest, err := store.LatestEvent("default", "web")
+	require.NoError(t, err)
+	require.Equal(t, "failed", latest.Message)
+	events, err := store.Events("default", "web")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	latest, err = store.LatestEvent("default", "db")
+	require.NoError(t, err)
+	require.Nil(t, latest)
+	latest, err = store.LatestEvent("test", "web")
+	require.NoError(t, err)
+	require.Equal(t, "elsewhere", latest.Message)
+	require.Equal(t, 1, lists)
+}
+
+func TestInformerStore(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "other"}},
+	)
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace("default"))
+	defer factory.Shutdown()
+	// The informers stop before Shutdown waits for them.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lists := func() map[string]int {
+		counts := map[string]int{}
+		for _, action := range clientset.Actions() {
+			if action.GetVerb() == "list" {
+				counts[action.GetNamespace()]++
+			}
+		}
+		return counts
+	}
+
+	// Every pass gets a new store; only the first starts the informer.
+	for i := 0; i < 3; i++ {
+		store := NewInformerStore(ctx, &Client{Client: clientset}, "default", factory)
+		pods, err := store.Pods("default", "")
+		require.NoError(t, err)
+		require.Len(t, pods, 1)
+		_, err = store.Pod("other", "api")
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]int{"default": 1, "other": 3}, lists())
+
+	// Changes reach the next pass through the informer.
+	_, err := clientset.CoreV1().Pods("default").Create(ctx, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := NewInformerStore(ctx, &Client{Client: clientset}, "default", factory).Pod("default", "db")
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, lists()["default"])
+}
+
+func TestStoreObjectMeta(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}}})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme,
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Labels: map[string]string{"app": "report"}}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "hourly", Namespace: "default"}},
+	)
+	store := NewStore(context.Background(), &Client{Client: clientset, DynamicClient: dynamicClient}, "default")
+
+	// Pods come from the typed list the analyzers share.
+	meta, err := store.ObjectMeta(v1.SchemeGroupVersion.WithResource("pods"), "default", "web")
+	require.NoError(t, err)
+	require.Equal(t, "web", meta.GetLabels()["app"])
+
+	cronJobs := batchv1.SchemeGroupVersion.WithResource("cronjobs")
+	meta, err = store.ObjectMeta(cronJobs, "default", "nightly")
+	require.NoError(t, err)
+	require.Equal(t, "report", meta.GetLabels()["app"])
+	_, err = store.ObjectMeta(cronJobs, "default", "hourly")
+	require.NoError(t, err)
+	_, err = store.Object(cronJobs, "default", "weekly")
+	require.True(t, apierrors.IsNotFound(err))
+
+	require.Len(t, clientset.Actions(), 1)
+	require.Len(t, dynamicClient.Actions(), 1)
+	require.Equal(t, "list", dynamicClient.Actions()[0].GetVerb())
+}
+
+func TestStoreClusterScoped(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Labels: map[string]string{"role": "worker"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "control-plane"}},
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}},
+	)
+	// A store of one namespace still lists cluster scoped resources once.
+	store := NewStore(context.Background(), &Client{Client: clientset}, "default")
+
+	nodes, err := store.Nodes("")
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	nodes, err = store.Nodes("role=worker")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	_, err = store.IngressClass("nginx")
+	require.NoError(t, err)
+	_, err = store.IngressClass("traefik")
+	require.True(t, apierrors.IsNotFound(err))
+
+	for _, action := range clientset.Actions() {
+		require.Empty(t, action.GetNamespace())
+	}
+	require.Len(t, clientset.Actions(), 2)
+}
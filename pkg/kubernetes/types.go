@@ -4,6 +4,7 @@ import (
 	openapi_v2 "github.com/google/gnostic/openapiv2"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
@@ -12,6 +13,7 @@ import (
 type Client struct {
 	Client        kubernetes.Interface
 	CtrlClient    ctrl.Client
+	DynamicClient dynamic.Interface
 	Config        *rest.Config
 	ServerVersion *version.Info
 }
@@ -33,11 +33,15 @@ func (h *Handler) Analyze(ctx context.Context, i *schemav1.AnalyzeRequest) (
 		false,      // Interactive mode disabled in server mode
 		[]string{}, //TODO: add custom http headers in server mode
 		false,      // with stats disable
+		"",         // snapshots are not supported in server mode
+		nil,        // multi-cluster analysis is not supported in server mode
 	)
 	config.Context = ctx // Replace context for correct timeouts.
 	if err != nil {
 		return &schemav1.AnalyzeResponse{}, err
 	}
+	// The server saves the cache statistics periodically and on shutdown.
+	config.DeferStatsSave = true
 	defer config.Close()
 
 	if config.CustomAnalyzersAreAvailable() {
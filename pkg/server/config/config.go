@@ -10,16 +10,6 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const (
-	notUsedBucket        = ""
-	notUsedRegion        = ""
-	notUsedEndpoint      = ""
-	notUsedStorageAcc    = ""
-	notUsedContainerName = ""
-	notUsedProjectId     = ""
-	notUsedInsecure      = false
-)
-
 func (h *Handler) AddConfig(ctx context.Context, i *schemav1.AddConfigRequest) (*schemav1.AddConfigResponse, error,
 ) {
 
@@ -66,13 +56,40 @@ func (h *Handler) AddConfig(ctx context.Context, i *schemav1.AddConfigRequest) (
 
 		switch i.Cache.GetCacheType().(type) {
 		case *schemav1.Cache_AzureCache:
-			remoteCache, err = cache.NewCacheProvider("azure", notUsedBucket, notUsedRegion, notUsedEndpoint, i.Cache.GetAzureCache().StorageAccount, i.Cache.GetAzureCache().ContainerName, notUsedProjectId, notUsedInsecure)
+			remoteCache, err = cache.NewCacheProviderWithOptions("azure", cache.CacheProviderOptions{
+				StorageAccount: i.Cache.GetAzureCache().StorageAccount,
+				ContainerName:  i.Cache.GetAzureCache().ContainerName,
+			})
 		case *schemav1.Cache_S3Cache:
-			remoteCache, err = cache.NewCacheProvider("s3", i.Cache.GetS3Cache().BucketName, i.Cache.GetS3Cache().Region, i.Cache.GetS3Cache().Endpoint, notUsedStorageAcc, notUsedContainerName, notUsedProjectId, i.Cache.GetS3Cache().Insecure)
+			remoteCache, err = cache.NewCacheProviderWithOptions("s3", cache.CacheProviderOptions{
+				BucketName: i.Cache.GetS3Cache().BucketName,
+				Region:     i.Cache.GetS3Cache().Region,
+				Endpoint:   i.Cache.GetS3Cache().Endpoint,
+				Insecure:   i.Cache.GetS3Cache().Insecure,
+			})
 		case *schemav1.Cache_GcsCache:
-			remoteCache, err = cache.NewCacheProvider("gcs", i.Cache.GetGcsCache().BucketName, i.Cache.GetGcsCache().Region, notUsedEndpoint, notUsedStorageAcc, notUsedContainerName, i.Cache.GetGcsCache().GetProjectId(), notUsedInsecure)
+			remoteCache, err = cache.NewCacheProviderWithOptions("gcs", cache.CacheProviderOptions{
+				BucketName: i.Cache.GetGcsCache().BucketName,
+				Region:     i.Cache.GetGcsCache().Region,
+				ProjectId:  i.Cache.GetGcsCache().GetProjectId(),
+			})
 		default:
-			return resp, status.Error(codes.InvalidArgument, "Invalid cache configuration")
+			// The Redis variant is not in the published schema yet, see
+			// schema_shim.go.
+			redisCache, ok, shimErr := readShimRedisCache(i.Cache)
+			if shimErr != nil {
+				return resp, status.Error(codes.InvalidArgument, shimErr.Error())
+			}
+			if !ok {
+				return resp, status.Error(codes.InvalidArgument, "Invalid cache configuration")
+			}
+			remoteCache, err = cache.NewCacheProviderWithOptions("redis", cache.CacheProviderOptions{
+				Endpoint:    redisCache.Endpoint,
+				RedisDB:     redisCache.DB,
+				RedisTLS:    redisCache.TLS,
+				Insecure:    redisCache.Insecure,
+				RedisPrefix: redisCache.Prefix,
+			})
 		}
 
 		if err != nil {
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TEMPORARY: this file stands in for the Redis variant of the cache_type
+// oneof of the server config schema (buf.build/k8sgpt-ai/k8sgpt, schema/v1),
+// which is not published yet. The published Cache has no Redis field, so
+// AddConfig decodes it by hand from the unknown fields of the request, as the
+// schema is expected to declare it:
+//
+//	message Cache {
+//	  oneof cache_type {
+//	    S3Cache s3_cache = 1;
+//	    AzureCache azure_cache = 2;
+//	    GCSCache gcs_cache = 3;
+//	    RedisCache redis_cache = 4;
+//	  }
+//	}
+//	message RedisCache {
+//	  string endpoint = 1;
+//	  int32 db = 2;
+//	  bool tls = 3;
+//	  bool insecure = 4;
+//	  string prefix = 5;
+//	}
+//
+// Clients generated from that schema send these fields without changes.
+//
+// Once the schema is published and regenerated, add a *schemav1.Cache_RedisCache
+// case to AddConfig, remove shimRedisCache and delete this file.
+const (
+	cacheRedisField protowire.Number = 4
+
+	redisEndpointField protowire.Number = 1
+	redisDBField       protowire.Number = 2
+	redisTLSField      protowire.Number = 3
+	redisInsecureField protowire.Number = 4
+	redisPrefixField   protowire.Number = 5
+)
+
+// shimRedisCache is the RedisCache of the pending schema.
+type shimRedisCache struct {
+	Endpoint string
+	DB       int
+	TLS      bool
+	Insecure bool
+	Prefix   string
+}
+
+// readShimRedisCache decodes the Redis variant of c. It reports false when c
+// does not hold one.
+func readShimRedisCache(c *schemav1.Cache) (shimRedisCache, bool, error) {
+	var redisCache shimRedisCache
+	found := false
+	b := c.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return redisCache, false, fmt.Errorf("reading the cache: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if number != cacheRedisField || wireType != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(number, wireType, b)
+			if n < 0 {
+				return redisCache, false, fmt.Errorf("reading the cache: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return redisCache, false, fmt.Errorf("reading the cache: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		// As for any message field, a later occurrence replaces the earlier one.
+		redisCache = shimRedisCache{}
+		if err := redisCache.unmarshal(value); err != nil {
+			return redisCache, false, err
+		}
+		found = true
+	}
+	return redisCache, found, nil
+}
+
+func (r *shimRedisCache) unmarshal(b []byte) error {
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("reading the redis cache: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch {
+		case wireType == protowire.BytesType && (number == redisEndpointField || number == redisPrefixField):
+			value, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return fmt.Errorf("reading the redis cache: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+			if number == redisEndpointField {
+				r.Endpoint = value
+			} else {
+				r.Prefix = value
+			}
+		case wireType == protowire.VarintType && (number == redisDBField || number == redisTLSField || number == redisInsecureField):
+			value, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return fmt.Errorf("reading the redis cache: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+			switch number {
+			case redisDBField:
+				r.DB = int(int32(value))
+			case redisTLSField:
+				r.TLS = protowire.DecodeBool(value)
+			case redisInsecureField:
+				r.Insecure = protowire.DecodeBool(value)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(number, wireType, b)
+			if n < 0 {
+				return fmt.Errorf("reading the redis cache: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
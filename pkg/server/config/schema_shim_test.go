@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	schemav1 "buf.build/gen/go/k8sgpt-ai/k8sgpt/protocolbuffers/go/schema/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// redisCacheRequest encodes an AddConfigRequest as a client of the pending
+// schema sends it.
+func redisCacheRequest(t *testing.T, redisCache []byte) *schemav1.AddConfigRequest {
+	var cacheFields []byte
+	cacheFields = protowire.AppendTag(cacheFields, cacheRedisField, protowire.BytesType)
+	cacheFields = protowire.AppendBytes(cacheFields, redisCache)
+	var request []byte
+	// cache = 1 of AddConfigRequest.
+	request = protowire.AppendTag(request, 1, protowire.BytesType)
+	request = protowire.AppendBytes(request, cacheFields)
+
+	i := &schemav1.AddConfigRequest{}
+	require.NoError(t, proto.Unmarshal(request, i))
+	return i
+}
+
+func TestReadShimRedisCache(t *testing.T) {
+	var fields []byte
+	fields = protowire.AppendTag(fields, redisEndpointField, protowire.BytesType)
+	fields = protowire.AppendString(fields, "redis:6379")
+	fields = protowire.AppendTag(fields, redisDBField, protowire.VarintType)
+	fields = protowire.AppendVarint(fields, 3)
+	fields = protowire.AppendTag(fields, redisTLSField, protowire.VarintType)
+	fields = protowire.AppendVarint(fields, protowire.EncodeBool(true))
+	fields = protowire.AppendTag(fields, redisInsecureField, protowire.VarintType)
+	fields = protowire.AppendVarint(fields, protowire.EncodeBool(true))
+	fields = protowire.AppendTag(fields, redisPrefixField, protowire.BytesType)
+	fields = protowire.AppendString(fields, "team-a:")
+	// Fields of a later schema are skipped.
+	fields = protowire.AppendTag(fields, 99, protowire.VarintType)
+	fields = protowire.AppendVarint(fields, 1)
+
+	i := redisCacheRequest(t, fields)
+	require.Nil(t, i.Cache.GetCacheType())
+	redisCache, ok, err := readShimRedisCache(i.Cache)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, shimRedisCache{Endpoint: "redis:6379", DB: 3, TLS: true, Insecure: true, Prefix: "team-a:"}, redisCache)
+
+	// Defaults are not encoded.
+	redisCache, ok, err = readShimRedisCache(redisCacheRequest(t, nil).Cache)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, shimRedisCache{}, redisCache)
+
+	_, ok, err = readShimRedisCache(&schemav1.Cache{})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = readShimRedisCache(redisCacheRequest(t, []byte{0x0a, 0x05, 'r'}).Cache)
+	require.Error(t, err)
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/server/analyze"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/server/config"
 	"github.com/k8sgpt-ai/k8sgpt/pkg/server/query"
@@ -57,8 +58,13 @@ type Config struct {
 	metricsServer  *http.Server
 	listener       net.Listener
 	EnableHttp     bool
+	stopStats      context.CancelFunc
 }
 
+// statsSaveInterval is how often the server saves the cache statistics of the
+// analyses it ran.
+const statsSaveInterval = time.Minute
+
 type Health struct {
 	Status  string `json:"status"`
 	Success int    `json:"success"`
@@ -73,9 +79,37 @@ var health = Health{
 }
 
 func (s *Config) Shutdown() error {
+	if s.stopStats != nil {
+		s.stopStats()
+	}
+	s.saveStats()
+	if s.listener == nil {
+		return nil
+	}
 	return s.listener.Close()
 }
 
+// saveStatsPeriodically saves the cache statistics every interval until ctx
+// is done.
+func (s *Config) saveStatsPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.saveStats()
+		}
+	}
+}
+
+func (s *Config) saveStats() {
+	if err := cache.SaveStats(); err != nil {
+		s.Logger.Warn(fmt.Sprintf("error while saving cache statistics: %v", err))
+	}
+}
+
 // grpcHandlerFunc returns an http.Handler that delegates to grpcServer on incoming gRPC
 // connections or otherHandler otherwise.
 func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
@@ -103,6 +137,9 @@ func (s *Config) Serve() error {
 	s.AnalyzeHandler = &analyze.Handler{}
 	s.QueryHandler = &query.Handler{}
 	s.listener = lis
+	var statsCtx context.Context
+	statsCtx, s.stopStats = context.WithCancel(context.Background())
+	go s.saveStatsPeriodically(statsCtx, statsSaveInterval)
 	s.Logger.Info(fmt.Sprintf("binding api to %s", s.Port))
 	grpcServerUnaryInterceptor := grpc.UnaryInterceptor(LogInterceptor(s.Logger))
 	grpcServer := grpc.NewServer(grpcServerUnaryInterceptor)
@@ -129,13 +166,13 @@ func (s *Config) Serve() error {
 			Handler: h2c.NewHandler(grpcHandlerFunc(grpcServer, gwmux), &http2.Server{}),
 		}
 
-		if err := srv.Serve(lis); err != nil {
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, net.ErrClosed) {
 			return err
 		}
 	} else {
 		if err := grpcServer.Serve(
 			lis,
-		); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		); err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, net.ErrClosed) {
 			return err
 		}
 	}
@@ -4,8 +4,11 @@ import (
 	"os"
 	"testing"
 
+	"github.com/adrg/xdg"
 	"github.com/fatih/color"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/cache"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -36,3 +39,21 @@ func TestServerInit(t *testing.T) {
 		}
 	}()
 }
+
+func TestShutdownSavesStats(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	statsFile, err := xdg.CacheFile("k8sgpt-cache-stats.json")
+	require.NoError(t, err)
+
+	// A lookup of an analysis leaves statistics pending.
+	c, err := cache.GetCacheConfiguration()
+	require.NoError(t, err)
+	require.False(t, c.Exists("key"))
+	require.NoFileExists(t, statsFile)
+
+	server := Config{Logger: zap.NewNop()}
+	require.NoError(t, server.Shutdown())
+	require.FileExists(t, statsFile)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/common"
+	"github.com/k8sgpt-ai/k8sgpt/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	networkv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// supportingObjects are kinds the analyzers look up while checking the kinds
+// in common.PreAnalysis, e.g. the Events of a Pod or the Service of an Ingress.
+var supportingObjects = []runtime.Object{
+	&v1.Event{},
+	&v1.Service{},
+	&v1.Secret{},
+	&v1.ReplicationController{},
+	&appsv1.DaemonSet{},
+	&batchv1.CronJob{},
+	&networkv1.IngressClass{},
+	&storagev1.StorageClass{},
+}
+
+// listPageSize is the number of objects requested per List call.
+const listPageSize = 500
+
+// Kinds returns the kinds captured in a snapshot: every kind referenced in
+// common.PreAnalysis plus the supporting kinds the analyzers read.
+func Kinds() ([]schema.GroupVersionKind, error) {
+	var objects []runtime.Object
+	preAnalysis := reflect.TypeOf(common.PreAnalysis{})
+	for i := 0; i < preAnalysis.NumField(); i++ {
+		field := preAnalysis.Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if obj, ok := reflect.New(field.Type).Interface().(runtime.Object); ok {
+			objects = append(objects, obj)
+		}
+	}
+	objects = append(objects, supportingObjects...)
+
+	seen := map[schema.GroupVersionKind]bool{}
+	var kinds []schema.GroupVersionKind
+	for _, obj := range objects {
+		gvks, _, err := Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[gvks[0]] {
+			seen[gvks[0]] = true
+			kinds = append(kinds, gvks[0])
+		}
+	}
+	return kinds, nil
+}
+
+// Capture lists every kind returned by Kinds. Kinds whose API is not served
+// by the cluster, or that the caller may not list, are reported as warnings.
+// Secret data is never captured.
+func Capture(ctx context.Context, client ctrl.Client, namespace string) ([]*unstructured.Unstructured, []string, error) {
+	kinds, err := Kinds()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+	var warnings []string
+	for _, gvk := range kinds {
+		items, err := listAll(ctx, client, gvk, namespace)
+		if err != nil {
+			if meta.IsNoMatchError(err) || errors.IsNotFound(err) || errors.IsForbidden(err) {
+				warnings = append(warnings, fmt.Sprintf("skipping %s: %s", gvk.Kind, err))
+				continue
+			}
+			return nil, nil, fmt.Errorf("listing %s: %w", gvk.Kind, err)
+		}
+		objects = append(objects, items...)
+	}
+	return objects, warnings, nil
+}
+
+func listAll(ctx context.Context, client ctrl.Client, gvk schema.GroupVersionKind, namespace string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := client.List(ctx, list, ctrl.InNamespace(namespace), ctrl.Limit(listPageSize), ctrl.Continue(continueToken)); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetGroupVersionKind(gvk)
+			obj.SetManagedFields(nil)
+			if gvk.Kind == "Secret" {
+				unstructured.RemoveNestedField(obj.Object, "data")
+				unstructured.RemoveNestedField(obj.Object, "stringData")
+			}
+			objects = append(objects, obj)
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return objects, nil
+		}
+	}
+}
+
+// Redact replaces the names and namespaces of objects, and every reference
+// to them, with masked values. The same name is always masked the same way,
+// so relationships between objects survive.
+func Redact(objects []*unstructured.Unstructured) {
+	masks := map[string]string{}
+	for _, obj := range objects {
+		for _, value := range []string{obj.GetName(), obj.GetNamespace()} {
+			if _, ok := masks[value]; value != "" && !ok {
+				masks[value] = util.MaskString(value)
+			}
+		}
+	}
+
+	// Replace longer names first so a name that contains another one is
+	// masked as a whole in free text.
+	names := make([]string, 0, len(masks))
+	for name := range masks {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, obj := range objects {
+		obj.Object = redactValue(obj.Object, "", masks, names).(map[string]interface{})
+	}
+}
+
+func redactValue(value interface{}, key string, masks map[string]string, names []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = redactValue(item, k, masks, names)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, key, masks, names)
+		}
+		return v
+	case string:
+		if key == "apiVersion" || key == "kind" {
+			return v
+		}
+		if masked, ok := masks[v]; ok {
+			return masked
+		}
+		// Event and condition messages mention objects by name.
+		if key == "message" {
+			for _, name := range names {
+				v = util.ReplaceIfMatch(v, regexp.QuoteMeta(name), masks[name])
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// WriteArchive writes objects as a gzip compressed tar archive holding one
+// List manifest per kind, in the layout Load reads.
+func WriteArchive(w io.Writer, objects []*unstructured.Unstructured) error {
+	byKind := map[string][]interface{}{}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		file := strings.ToLower(gvk.Kind) + ".json"
+		if gvk.Group != "" {
+			file = strings.ToLower(gvk.Kind) + "." + gvk.Group + ".json"
+		}
+		byKind[file] = append(byKind[file], obj.Object)
+	}
+
+	files := make([]string, 0, len(byKind))
+	for file := range byKind {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, file := range files {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      byKind[file],
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     file,
+			Mode:     0o600,
+			Size:     int64(len(data)),
+			ModTime:  now,
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func captureFixture(t *testing.T) []*unstructured.Unstructured {
+	client := ctrlfake.NewClientBuilder().
+		WithScheme(Scheme).
+		WithObjects(
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-1",
+					Namespace: "shop",
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{Manager: "kubectl"},
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "web", Image: "nginx"}},
+					Volumes: []v1.Volume{{
+						Name:         "creds",
+						VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "db-creds"}},
+					}},
+				},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "shop"},
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			},
+			&v1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "shop"},
+				InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "web-1"},
+				Reason:         "FailedMount",
+				Message:        `secret "db-creds" not found for pod shop/web-1`,
+			},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			},
+		).
+		Build()
+
+	objects, _, err := Capture(context.Background(), client, "shop")
+	require.NoError(t, err)
+	return objects
+}
+
+func TestCapture(t *testing.T) {
+	objects := captureFixture(t)
+
+	kinds := map[string]int{}
+	for _, obj := range objects {
+		kinds[obj.GetKind()]++
+		require.Equal(t, "shop", obj.GetNamespace())
+		require.Empty(t, obj.GetManagedFields())
+		if obj.GetKind() == "Secret" {
+			_, found, err := unstructured.NestedFieldNoCopy(obj.Object, "data")
+			require.NoError(t, err)
+			require.False(t, found, "secret data must not be captured")
+		}
+	}
+	require.Equal(t, map[string]int{"Pod": 1, "Secret": 1, "Event": 1}, kinds)
+}
+
+func TestRedact(t *testing.T) {
+	objects := captureFixture(t)
+	Redact(objects)
+
+	byKind := map[string]*unstructured.Unstructured{}
+	for _, obj := range objects {
+		byKind[obj.GetKind()] = obj
+	}
+	pod, secret, event := byKind["Pod"], byKind["Secret"], byKind["Event"]
+
+	require.NotEqual(t, "web-1", pod.GetName())
+	require.NotEqual(t, "shop", pod.GetNamespace())
+	require.Equal(t, pod.GetNamespace(), secret.GetNamespace())
+	require.Equal(t, "v1", pod.GetAPIVersion())
+
+	// References keep pointing at the masked objects.
+	involvedName, _, _ := unstructured.NestedString(event.Object, "involvedObject", "name")
+	require.Equal(t, pod.GetName(), involvedName)
+	involvedKind, _, _ := unstructured.NestedString(event.Object, "involvedObject", "kind")
+	require.Equal(t, "Pod", involvedKind)
+
+	volumes, _, _ := unstructured.NestedSlice(pod.Object, "spec", "volumes")
+	secretName, _, _ := unstructured.NestedString(volumes[0].(map[string]interface{}), "secret", "secretName")
+	require.Equal(t, secret.GetName(), secretName)
+
+	message, _, _ := unstructured.NestedString(event.Object, "message")
+	require.NotContains(t, message, "db-creds")
+	require.NotContains(t, message, "web-1")
+	require.Contains(t, message, secret.GetName())
+	require.Contains(t, message, pod.GetNamespace()+"/"+pod.GetName())
+}
+
+func TestWriteArchive(t *testing.T) {
+	objects := captureFixture(t)
+
+	var archive bytes.Buffer
+	require.NoError(t, WriteArchive(&archive, objects))
+	path := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	require.NoError(t, os.WriteFile(path, archive.Bytes(), 0o600))
+
+	client, err := NewClient(path)
+	require.NoError(t, err)
+	pods, err := client.GetClient().CoreV1().Pods("shop").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	require.Equal(t, "web-1", pods.Items[0].Name)
+}
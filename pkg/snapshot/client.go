@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// NewClient returns a client that serves the objects stored at path instead
+// of talking to an API server. See Load for the supported layouts.
+func NewClient(path string) (*kubernetes.Client, error) {
+	objects, err := Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading snapshot %s: %w", path, err)
+	}
+	return NewClientFromObjects(objects), nil
+}
+
+// NewClientFromObjects returns a client that serves objects. Built-in kinds
+// are served by both the typed and the controller-runtime client, custom
+// resources only by the latter.
+func NewClientFromObjects(objects []runtime.Object) *kubernetes.Client {
+	var builtin []runtime.Object
+	for _, obj := range objects {
+		if clientgoscheme.Scheme.Recognizes(obj.GetObjectKind().GroupVersionKind()) {
+			builtin = append(builtin, obj)
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(builtin...)
+	// The fake clientset ignores field selectors, which the analyzers use to
+	// find the events of a single object.
+	clientset.PrependReactor("list", "events", eventFieldSelectorReactor(clientset.Tracker()))
+
+	return &kubernetes.Client{
+		Client: clientset,
+		CtrlClient: ctrlfake.NewClientBuilder().
+			WithScheme(Scheme).
+			WithRuntimeObjects(objects...).
+			Build(),
+		DynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(Scheme, listKinds(), objects...),
+	}
+}
+
+// listKinds maps the resource of every kind in Scheme to its list kind, so
+// the fake dynamic client can list kinds the snapshot has no objects of.
+func listKinds() map[schema.GroupVersionResource]string {
+	kinds := map[schema.GroupVersionResource]string{}
+	for gvk := range Scheme.AllKnownTypes() {
+		kind, ok := strings.CutSuffix(gvk.Kind, "List")
+		if !ok || kind == "" {
+			continue
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk.GroupVersion().WithKind(kind))
+		kinds[gvr] = gvk.Kind
+	}
+	return kinds
+}
+
+func eventFieldSelectorReactor(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list, ok := action.(k8stesting.ListActionImpl)
+		if !ok || list.ListRestrictions.Fields == nil || list.ListRestrictions.Fields.Empty() {
+			return false, nil, nil
+		}
+
+		obj, err := tracker.List(list.GetResource(), list.GetKind(), list.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		events := obj.(*v1.EventList)
+		selector := list.ListRestrictions.Labels
+		if selector == nil {
+			selector = labels.Everything()
+		}
+
+		filtered := &v1.EventList{ListMeta: events.ListMeta}
+		for _, event := range events.Items {
+			if !selector.Matches(labels.Set(event.Labels)) {
+				continue
+			}
+			if !list.ListRestrictions.Fields.Matches(eventFields(&event)) {
+				continue
+			}
+			filtered.Items = append(filtered.Items, event)
+		}
+		return true, filtered, nil
+	}
+}
+
+// eventFields mirrors the field selectors the API server supports for events.
+func eventFields(event *v1.Event) fields.Set {
+	return fields.Set{
+		"metadata.name":                  event.Name,
+		"metadata.namespace":             event.Namespace,
+		"involvedObject.kind":            event.InvolvedObject.Kind,
+		"involvedObject.namespace":       event.InvolvedObject.Namespace,
+		"involvedObject.name":            event.InvolvedObject.Name,
+		"involvedObject.uid":             string(event.InvolvedObject.UID),
+		"involvedObject.apiVersion":      event.InvolvedObject.APIVersion,
+		"involvedObject.resourceVersion": event.InvolvedObject.ResourceVersion,
+		"involvedObject.fieldPath":       event.InvolvedObject.FieldPath,
+		"reason":                         event.Reason,
+		"reportingComponent":             event.ReportingController,
+		"source":                         event.Source.Component,
+		"type":                           event.Type,
+	}
+}
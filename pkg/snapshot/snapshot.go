@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	trivy "github.com/aquasecurity/trivy-operator/pkg/apis/aquasecurity/v1alpha1"
+	keda "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kyverno "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/policyreport/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	gtwapi "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Scheme knows every kind the analyzers and integrations read.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(gtwapi.AddToScheme(Scheme))
+	utilruntime.Must(keda.AddToScheme(Scheme))
+	utilruntime.Must(trivy.AddToScheme(Scheme))
+	utilruntime.Must(kyverno.AddToScheme(Scheme))
+}
+
+var manifestExtensions = map[string]bool{
+	".json": true,
+	".yaml": true,
+	".yml":  true,
+}
+
+// Load reads the Kubernetes objects stored at path. path may be a directory
+// of manifests, the output directory of `kubectl cluster-info dump`, a single
+// manifest file or a tar archive (optionally gzip compressed) of either.
+// Kinds that are not known to Scheme are skipped.
+func Load(path string) ([]runtime.Object, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := newLoader()
+	switch {
+	case info.IsDir():
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !manifestExtensions[strings.ToLower(filepath.Ext(file))] {
+				return nil
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return l.add(file, data)
+		})
+	case isArchive(path):
+		err = l.addArchive(path)
+	default:
+		var data []byte
+		data, err = os.ReadFile(path)
+		if err == nil {
+			err = l.add(path, data)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.objects, nil
+}
+
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+type loader struct {
+	decoder runtime.Decoder
+	objects []runtime.Object
+	// seen maps an object identity to its index in objects, so an object
+	// present in several files is only loaded once.
+	seen map[string]int
+}
+
+func newLoader() *loader {
+	return &loader{
+		decoder: serializer.NewCodecFactory(Scheme).UniversalDeserializer(),
+		seen:    map[string]int{},
+	}
+}
+
+func (l *loader) addArchive(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(strings.ToLower(path), ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		if header.Typeflag != tar.TypeReg || !manifestExtensions[strings.ToLower(filepath.Ext(header.Name))] {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("reading %s from %s: %w", header.Name, path, err)
+		}
+		if err := l.add(header.Name, data); err != nil {
+			return err
+		}
+	}
+}
+
+// add decodes every YAML or JSON document in data, expanding lists.
+func (l *loader) add(file string, data []byte) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("parsing %s: %w", file, err)
+		}
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || string(raw.Raw) == "null" {
+			continue
+		}
+		if err := l.decode(file, raw.Raw); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *loader) decode(file string, data []byte) error {
+	obj, _, err := l.decoder.Decode(data, nil, nil)
+	if err != nil {
+		if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+			return nil
+		}
+		return fmt.Errorf("decoding object in %s: %w", file, err)
+	}
+
+	if !meta.IsListType(obj) {
+		return l.append(obj)
+	}
+
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		return fmt.Errorf("reading list in %s: %w", file, err)
+	}
+	for _, item := range items {
+		// Items of a generic v1.List are left undecoded.
+		if unknown, ok := item.(*runtime.Unknown); ok {
+			if err := l.decode(file, unknown.Raw); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := l.append(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) append(obj runtime.Object) error {
+	gvks, _, err := Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	// Items of typed lists come without their own type information.
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s/%s/%s", gvks[0].String(), accessor.GetNamespace(), accessor.GetName())
+	if i, ok := l.seen[key]; ok {
+		l.objects[i] = obj
+		return nil
+	}
+	l.seen[key] = len(l.objects)
+	l.objects = append(l.objects, obj)
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The K8sGPT Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gtwapi "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// manifests mimics a directory of YAML manifests.
+const manifests = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+spec:
+  selector:
+    app: web
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: default
+spec:
+  gatewayClassName: missing
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: ignored
+`
+
+// podsDump mimics a pods.json file written by `kubectl cluster-info dump`.
+const podsDump = `{
+    "kind": "PodList",
+    "apiVersion": "v1",
+    "metadata": {},
+    "items": [
+        {
+            "metadata": {"name": "web-1", "namespace": "default", "labels": {"app": "web"}},
+            "spec": {"containers": [{"name": "web", "image": "nginx"}]},
+            "status": {"phase": "Pending"}
+        }
+    ]
+}`
+
+// eventsDump mimics an events.json file written by `kubectl cluster-info dump`.
+const eventsDump = `{
+    "kind": "EventList",
+    "apiVersion": "v1",
+    "metadata": {},
+    "items": [
+        {
+            "metadata": {"name": "web-1.1", "namespace": "default"},
+            "involvedObject": {"kind": "Pod", "namespace": "default", "name": "web-1"},
+            "reason": "FailedMount",
+            "message": "MountVolume.SetUp failed"
+        },
+        {
+            "metadata": {"name": "other.1", "namespace": "default"},
+            "involvedObject": {"kind": "Pod", "namespace": "default", "name": "other"},
+            "reason": "Pulled",
+            "message": "Successfully pulled image"
+        }
+    ]
+}`
+
+func writeSnapshot(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manifests.yaml"), []byte(manifests), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "default", "web-1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "default", "pods.json"), []byte(podsDump), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "default", "events.json"), []byte(eventsDump), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "default", "web-1", "logs.txt"), []byte("not a manifest"), 0o600))
+	return dir
+}
+
+func TestLoadDirectory(t *testing.T) {
+	objects, err := Load(writeSnapshot(t))
+	require.NoError(t, err)
+
+	kinds := map[string]int{}
+	for _, obj := range objects {
+		kinds[obj.GetObjectKind().GroupVersionKind().Kind]++
+	}
+	require.Equal(t, map[string]int{"Service": 1, "Gateway": 1, "Pod": 1, "Event": 2}, kinds)
+}
+
+func TestLoadArchive(t *testing.T) {
+	dir := writeSnapshot(t)
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"manifests.yaml", "default/pods.json", "default/events.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	objects, err := Load(archive)
+	require.NoError(t, err)
+	require.Len(t, objects, 5)
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(writeSnapshot(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	pods, err := client.GetClient().CoreV1().Pods("default").List(ctx, metav1.ListOptions{LabelSelector: "app=web"})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+
+	events, err := client.GetClient().CoreV1().Events("default").List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.name=web-1",
+	})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	require.Equal(t, "FailedMount", events.Items[0].Reason)
+
+	gateways := &gtwapi.GatewayList{}
+	require.NoError(t, client.CtrlClient.List(ctx, gateways))
+	require.Len(t, gateways.Items, 1)
+
+	services := &v1.ServiceList{}
+	require.NoError(t, client.CtrlClient.List(ctx, services))
+	require.Len(t, services.Items, 1)
+
+	dynamicPods, err := client.GetDynamicClient().Resource(v1.SchemeGroupVersion.WithResource("pods")).Namespace("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, dynamicPods.Items, 1)
+	// Kinds without objects can be listed too.
+	nodes, err := client.GetDynamicClient().Resource(v1.SchemeGroupVersion.WithResource("nodes")).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, nodes.Items)
+}
+
+func TestLoadMissingPath(t *testing.T) {
+	_, err := NewClient(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "loading snapshot")
+}
@@ -36,6 +36,11 @@ import (
 
 var anonymizePattern = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{}|;':\",./<>?")
 
+// GetParent returns the topmost owner of the object as Kind/name, fetching
+// each owner from the API server.
+//
+// Deprecated: analyzers should use GetParentFromStore with the store of the
+// run, which reads the owners from lists shared by all analyzers.
 func GetParent(client *kubernetes.Client, meta metav1.ObjectMeta) (string, bool) {
 	if meta.OwnerReferences != nil {
 		for _, owner := range meta.OwnerReferences {
@@ -115,6 +120,87 @@ func GetParent(client *kubernetes.Client, meta metav1.ObjectMeta) (string, bool)
 	return "", false
 }
 
+// GetParentFromStore returns the topmost owner of the object, read from
+// store, as Kind/name.
+func GetParentFromStore(store *kubernetes.Store, meta metav1.ObjectMeta) (string, bool) {
+	if meta.OwnerReferences != nil {
+		for _, owner := range meta.OwnerReferences {
+			switch owner.Kind {
+			case "ReplicaSet":
+				rs, err := store.ReplicaSet(meta.Namespace, owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if rs.OwnerReferences != nil {
+					return GetParentFromStore(store, rs.ObjectMeta)
+				}
+				return "ReplicaSet/" + rs.Name, true
+
+			case "Deployment":
+				dep, err := store.Deployment(meta.Namespace, owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if dep.OwnerReferences != nil {
+					return GetParentFromStore(store, dep.ObjectMeta)
+				}
+				return "Deployment/" + dep.Name, true
+
+			case "StatefulSet":
+				sts, err := store.StatefulSet(meta.Namespace, owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if sts.OwnerReferences != nil {
+					return GetParentFromStore(store, sts.ObjectMeta)
+				}
+				return "StatefulSet/" + sts.Name, true
+
+			case "DaemonSet":
+				ds, err := store.DaemonSet(meta.Namespace, owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if ds.OwnerReferences != nil {
+					return GetParentFromStore(store, ds.ObjectMeta)
+				}
+				return "DaemonSet/" + ds.Name, true
+
+			case "Ingress":
+				ds, err := store.Ingress(meta.Namespace, owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if ds.OwnerReferences != nil {
+					return GetParentFromStore(store, ds.ObjectMeta)
+				}
+				return "Ingress/" + ds.Name, true
+
+			case "MutatingWebhookConfiguration":
+				mw, err := store.MutatingWebhookConfiguration(owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if mw.OwnerReferences != nil {
+					return GetParentFromStore(store, mw.ObjectMeta)
+				}
+				return "MutatingWebhook/" + mw.Name, true
+
+			case "ValidatingWebhookConfiguration":
+				vw, err := store.ValidatingWebhookConfiguration(owner.Name)
+				if err != nil {
+					return "", false
+				}
+				if vw.OwnerReferences != nil {
+					return GetParentFromStore(store, vw.ObjectMeta)
+				}
+				return "ValidatingWebhook/" + vw.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
 func RemoveDuplicates(slice []string) ([]string, []string) {
 	set := make(map[string]bool)
 	duplicates := []string{}
@@ -167,6 +253,12 @@ func ReplaceIfMatch(text string, pattern string, replacement string) string {
 	return text
 }
 
+// GetCacheKey returns the cache key of an AI response by provider and
+// language.
+//
+// Deprecated: use GetModelCacheKey. The key of GetCacheKey stays the same
+// when the model, temperature or prompt change, so it returns the answers
+// cached for the previous ones.
 func GetCacheKey(provider string, language string, sEnc string) string {
 	data := fmt.Sprintf("%s-%s-%s", provider, language, sEnc)
 
@@ -175,6 +267,18 @@ func GetCacheKey(provider string, language string, sEnc string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// GetModelCacheKey returns the cache key of an AI response. Every input that
+// changes the response is part of the key, so changing e.g. the model does
+// not return answers of the previous one.
+func GetModelCacheKey(provider string, model string, temperature float32, promptTemplate string, language string, sEnc string) string {
+	promptHash := sha256.Sum256([]byte(promptTemplate))
+	data := fmt.Sprintf("%s-%s-%g-%s-%s-%s", provider, model, temperature, hex.EncodeToString(promptHash[:]), language, sEnc)
+
+	hash := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(hash[:])
+}
+
 func GetPodListByLabels(client k.Interface,
 	namespace string,
 	labels map[string]string,
@@ -237,6 +341,11 @@ func LabelsIncludeAny(predefinedSelector, Labels map[string]string) bool {
 	return false
 }
 
+// FetchLatestEvent returns the most recent event about the object of namespace
+// with name, nil if there is none.
+//
+// Deprecated: analyzers should use the LatestEvent method of the store of the
+// run, which lists the events of a namespace once for all objects.
 func FetchLatestEvent(ctx context.Context, kubernetesClient *kubernetes.Client, namespace string, name string) (*v1.Event, error) {
 
 	// get the list of events
@@ -14,6 +14,7 @@ limitations under the License.
 package util
 
 import (
+	"context"
 	"testing"
 
 	"github.com/k8sgpt-ai/k8sgpt/pkg/kubernetes"
@@ -161,6 +162,10 @@ func TestGetParent(t *testing.T) {
 				require.Equal(t, false, ok)
 			}
 			require.Equal(t, tt.expectedOutput, output)
+
+			storeOutput, storeOk := GetParentFromStore(kubernetes.NewStore(context.Background(), &kubeClient, ""), meta)
+			require.Equal(t, ok, storeOk)
+			require.Equal(t, output, storeOutput)
 		})
 	}
 }
@@ -282,6 +287,50 @@ func TestGetCacheKey(t *testing.T) {
 	}
 }
 
+func TestGetModelCacheKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		provider       string
+		model          string
+		temperature    float32
+		promptTemplate string
+		language       string
+		sEnc           string
+		expectedOutput string
+	}{
+		{
+			name:           "empty",
+			expectedOutput: "253085b709fe765cfba235ef543b88a8ad2439d2c2cc440a7ab7c9775e471a34",
+		},
+		{
+			name:           "gpt-4o",
+			provider:       "provider",
+			model:          "gpt-4o",
+			temperature:    0.7,
+			promptTemplate: "Explain %s %s",
+			language:       "english",
+			sEnc:           "encoding",
+			expectedOutput: "1f25cb2c3c6111514cdbc11973462526deda52386e10473d93f020c75a1b728f",
+		},
+		{
+			name:           "other model",
+			provider:       "provider",
+			model:          "gpt-3.5-turbo",
+			temperature:    0.7,
+			promptTemplate: "Explain %s %s",
+			language:       "english",
+			sEnc:           "encoding",
+			expectedOutput: "8de4ab70b5c4902e6574ddf1a188f3828084c1a0b8f105c432c51d2806d5a3ca",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedOutput, GetModelCacheKey(tt.provider, tt.model, tt.temperature, tt.promptTemplate, tt.language, tt.sEnc))
+		})
+	}
+}
+
 func TestGetPodListByLabels(t *testing.T) {
 	namespace1 := "test1"
 	namespace2 := "test2"